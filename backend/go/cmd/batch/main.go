@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/internal/app"
+)
+
+// batch stores the results of enrichment batches the scraper submitted with
+// ENRICHMENT_MODE=batch. Without -wait it makes a single pass, which suits a
+// schedule; with -wait it polls until no batch is pending.
+func main() {
+	wait := flag.Bool("wait", false, "poll until every submitted batch has been applied")
+	pollInterval := flag.Duration("poll-interval", 5*time.Minute, "time between passes with -wait")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	for {
+		result, err := app.ApplyEnrichmentBatches(ctx, cfg)
+		if err != nil {
+			log.Fatalf("Batch enrichment failed: %v", err)
+		}
+		log.Printf("Applied %d batch(es): %d job(s) stored, %d failed (%d queued for retry), ~$%.4f, %d batch(es) pending",
+			result.BatchesApplied, result.Stats.SuccessfulJobs, result.Stats.FailedJobs, result.DeadLetters, result.Stats.TotalUsage().CostUSD, result.BatchesPending)
+		if !*wait || result.BatchesPending == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(*pollInterval):
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"gopher-source/config"
+	"gopher-source/internal/eval"
+	"gopher-source/models"
+	"gopher-source/services"
+)
+
+// eval runs the labeled corpus through the configured LLM provider and
+// reports field-level accuracy. With -baseline it exits non-zero when any
+// metric drops by more than -tolerance, so prompt edits can be gated on it.
+func main() {
+	corpus := flag.String("corpus", "testdata/eval/golden.jsonl", "labeled JSONL corpus")
+	baseline := flag.String("baseline", "", "report to compare against")
+	out := flag.String("out", "", "write the report to this file")
+	tolerance := flag.Float64("tolerance", 0.02, "largest drop in any metric that is not a regression")
+	concurrency := flag.Int("concurrency", 4, "examples parsed at once")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	examples, err := eval.LoadCorpus(*corpus)
+	if err != nil {
+		log.Fatalf("Failed to load corpus: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	llmClient, err := services.NewLLMClient(*cfg)
+	if err != nil {
+		log.Fatalf("Failed to create LLM client: %v", err)
+	}
+	// Cached responses would score an older prompt, so eval always parses.
+	stats := &models.JobStats{}
+	parser := services.NewParserService(llmClient, stats)
+	report := eval.Run(ctx, parser, stats, examples, *concurrency)
+	printReport(report)
+
+	if *out != "" {
+		if err := eval.WriteReport(*out, report); err != nil {
+			log.Fatalf("Failed to save report: %v", err)
+		}
+		log.Printf("Report written to %s", *out)
+	}
+
+	if *baseline == "" {
+		return
+	}
+	base, err := eval.LoadReport(*baseline)
+	if err != nil {
+		log.Fatalf("Failed to load baseline: %v", err)
+	}
+	if base.PromptVersion != report.PromptVersion {
+		log.Printf("Prompt version %s differs from baseline %s", report.PromptVersion, base.PromptVersion)
+	}
+	deltas := eval.Compare(base, report, *tolerance)
+	for _, delta := range deltas {
+		marker := ""
+		if delta.Regressed {
+			marker = "  REGRESSED"
+		}
+		log.Printf("%-32s %.3f -> %.3f (%+.3f)%s", delta.Metric, delta.Baseline, delta.Current, delta.Delta, marker)
+	}
+	if regressions := eval.Regressions(deltas); regressions > 0 {
+		log.Printf("%d metric(s) regressed by more than %.3f", regressions, *tolerance)
+		os.Exit(1)
+	}
+}
+
+func printReport(report eval.Report) {
+	log.Printf("Prompt %s: %d example(s), %d parse failure(s), ~$%.4f",
+		report.PromptVersion, report.Examples, report.Failures, report.Usage.CostUSD)
+	log.Printf("minYearsExperience exact %.3f, within 1 year %.3f", report.YOE.ExactRate, report.YOE.Within1Rate)
+	for _, field := range slices.Sorted(maps.Keys(report.Categorical)) {
+		metrics := report.Categorical[field]
+		log.Printf("%s accuracy %.3f (%d/%d)", field, metrics.Accuracy, metrics.Correct, metrics.Total)
+		for _, expected := range slices.Sorted(maps.Keys(metrics.Confusion)) {
+			row := metrics.Confusion[expected]
+			for _, predicted := range slices.Sorted(maps.Keys(row)) {
+				if predicted != expected {
+					log.Printf("  %s -> %s: %d", expected, predicted, row[predicted])
+				}
+			}
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(report.Lists)) {
+		metrics := report.Lists[field]
+		log.Printf("%s precision %.3f, recall %.3f, f1 %.3f", field, metrics.Precision, metrics.Recall, metrics.F1)
+	}
+	for _, mismatch := range report.Mismatches {
+		log.Printf("  %s %s: expected %q, got %q", mismatch.JobID, mismatch.Field, mismatch.Expected, mismatch.Got)
+	}
+}
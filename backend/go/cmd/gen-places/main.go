@@ -0,0 +1,407 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gen-places writes the gazetteer services/location.go embeds, from three
+// public-domain Census Bureau files: the Gazetteer places file for names and
+// internal points, the place-by-county codes to find each place's counties,
+// and the CBSA delineation (list 1) to name the metropolitan statistical area
+// of those counties. Run it with go generate ./services.
+const (
+	defaultPlacesURL      = "https://www2.census.gov/geo/docs/maps-data/data/gazetteer/2023_Gazetteer/2023_Gaz_place_national.zip"
+	defaultPlaceCountyURL = "https://www2.census.gov/geo/docs/reference/codes2020/national_place_by_county2020.txt"
+	defaultDelineationURL = "https://www2.census.gov/programs-surveys/metro-micro/geographies/reference-files/2023/delineation-files/list1_2023.xlsx"
+)
+
+// placeSuffixes are the legal descriptions Census appends to place names,
+// longest first so "city and borough" wins over "borough".
+var placeSuffixes = []string{
+	" consolidated government (balance)", " metropolitan government (balance)", " unified government (balance)",
+	" city and borough", " (balance)", " municipality", " urban county", " zona urbana", " comunidad",
+	" borough", " village", " town", " city", " CDP",
+}
+
+// placeAliases are the other names postings use for a place.
+var placeAliases = map[string][]string{
+	"New York|NY":       {"New York City", "NYC", "Manhattan", "Brooklyn"},
+	"Salt Lake City|UT": {"SLC"},
+}
+
+type place struct {
+	name         string
+	state        string
+	latitude     float64
+	longitude    float64
+	incorporated bool
+	landArea     int64
+	metro        string
+}
+
+func main() {
+	out := flag.String("out", "data/us_places.csv", "file to write")
+	placesURL := flag.String("places", defaultPlacesURL, "Gazetteer places file (zip)")
+	placeCountyURL := flag.String("place-county", defaultPlaceCountyURL, "place-by-county codes file")
+	delineationURL := flag.String("delineation", defaultDelineationURL, "CBSA delineation list 1 (xlsx)")
+	flag.Parse()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	placesZip, err := download(ctx, *placesURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	placeCounty, err := download(ctx, *placeCountyURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	delineation, err := download(ctx, *delineationURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gazetteer, err := zipEntry(placesZip, ".txt")
+	if err != nil {
+		log.Fatalf("read places file: %v", err)
+	}
+	places, err := readGazetteerPlaces(gazetteer)
+	if err != nil {
+		log.Fatalf("read places file: %v", err)
+	}
+	counties, err := readPlaceCounties(placeCounty)
+	if err != nil {
+		log.Fatalf("read place-by-county file: %v", err)
+	}
+	rows, err := readXLSXRows(delineation)
+	if err != nil {
+		log.Fatalf("read delineation file: %v", err)
+	}
+	metros, err := metroByCounty(rows)
+	if err != nil {
+		log.Fatalf("read delineation file: %v", err)
+	}
+	for geoid, p := range places {
+		for _, county := range counties[geoid] {
+			if metro := metros[county]; metro != "" {
+				p.metro = metro
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Generated by cmd/gen-places; DO NOT EDIT.\n# Sources (public domain, U.S. Census Bureau):\n#   %s\n#   %s\n#   %s\n", *placesURL, *placeCountyURL, *delineationURL)
+	if err := writePlaces(&buf, places); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Wrote %d places to %s", len(places), *out)
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: HTTP %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// zipEntry returns the first file in the archive whose name ends in suffix.
+func zipEntry(data []byte, suffix string) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, suffix) {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+	return nil, fmt.Errorf("no %s file in archive", suffix)
+}
+
+// readGazetteerPlaces keys the places of the tab-separated Gazetteer file by
+// GEOID.
+func readGazetteerPlaces(data []byte) (map[string]*place, error) {
+	records, err := readDelimited(data, '\t')
+	if err != nil {
+		return nil, err
+	}
+	column, err := columns(records[0], "USPS", "GEOID", "NAME", "FUNCSTAT", "ALAND", "INTPTLAT", "INTPTLONG")
+	if err != nil {
+		return nil, err
+	}
+	places := make(map[string]*place, len(records)-1)
+	for i, record := range records[1:] {
+		latitude, latErr := strconv.ParseFloat(record[column["INTPTLAT"]], 64)
+		longitude, lonErr := strconv.ParseFloat(record[column["INTPTLONG"]], 64)
+		if latErr != nil || lonErr != nil {
+			return nil, fmt.Errorf("line %d has invalid coordinates", i+2)
+		}
+		landArea, _ := strconv.ParseInt(record[column["ALAND"]], 10, 64)
+		places[record[column["GEOID"]]] = &place{
+			name:         placeName(record[column["NAME"]]),
+			state:        record[column["USPS"]],
+			latitude:     latitude,
+			longitude:    longitude,
+			incorporated: record[column["FUNCSTAT"]] == "A",
+			landArea:     landArea,
+		}
+	}
+	return places, nil
+}
+
+// readPlaceCounties maps each place GEOID to the county FIPS codes it lies in.
+func readPlaceCounties(data []byte) (map[string][]string, error) {
+	records, err := readDelimited(data, '|')
+	if err != nil {
+		return nil, err
+	}
+	column, err := columns(records[0], "STATEFP", "COUNTYFP", "PLACEFP")
+	if err != nil {
+		return nil, err
+	}
+	counties := make(map[string][]string)
+	for _, record := range records[1:] {
+		state := record[column["STATEFP"]]
+		geoid := state + record[column["PLACEFP"]]
+		counties[geoid] = append(counties[geoid], state+record[column["COUNTYFP"]])
+	}
+	return counties, nil
+}
+
+// metroByCounty maps county FIPS codes to the title of their metropolitan
+// statistical area; micropolitan areas are left out.
+func metroByCounty(rows [][]string) (map[string]string, error) {
+	header := -1
+	for i, row := range rows {
+		if len(row) > 0 && row[0] == "CBSA Code" {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, fmt.Errorf("no CBSA Code header row")
+	}
+	column, err := columns(rows[header], "CBSA Title", "Metropolitan/Micropolitan Statistical Area", "FIPS State Code", "FIPS County Code")
+	if err != nil {
+		return nil, err
+	}
+	metros := make(map[string]string)
+	for _, row := range rows[header+1:] {
+		if len(row) <= column["FIPS County Code"] || row[column["Metropolitan/Micropolitan Statistical Area"]] != "Metropolitan Statistical Area" {
+			continue
+		}
+		metros[row[column["FIPS State Code"]]+row[column["FIPS County Code"]]] = row[column["CBSA Title"]]
+	}
+	return metros, nil
+}
+
+// writePlaces writes one row per name and state, preferring an incorporated
+// place over a census-designated one and then the larger, sorted by state
+// and name.
+func writePlaces(w io.Writer, places map[string]*place) error {
+	byKey := make(map[string]*place, len(places))
+	for _, p := range places {
+		key := p.name + "|" + p.state
+		if kept, ok := byKey[key]; ok && (kept.incorporated && !p.incorporated || kept.incorporated == p.incorporated && kept.landArea >= p.landArea) {
+			continue
+		}
+		byKey[key] = p
+	}
+	keys := make([]string, 0, len(byKey))
+	for key := range byKey {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := byKey[keys[i]], byKey[keys[j]]
+		if a.state != b.state {
+			return a.state < b.state
+		}
+		return a.name < b.name
+	})
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"city", "state", "lat", "lon", "metro", "aliases"}); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		p := byKey[key]
+		aliases := placeAliases[key]
+		if rest, ok := strings.CutPrefix(p.name, "St. "); ok {
+			aliases = append(aliases, "Saint "+rest)
+		}
+		record := []string{p.name, p.state, strconv.FormatFloat(p.latitude, 'f', 4, 64), strconv.FormatFloat(p.longitude, 'f', 4, 64), p.metro, strings.Join(aliases, "|")}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// placeName drops the legal description from a Census place name, so
+// "Seattle city" becomes "Seattle".
+func placeName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, suffix := range placeSuffixes {
+		if trimmed, ok := strings.CutSuffix(name, suffix); ok && trimmed != "" {
+			return trimmed
+		}
+	}
+	return name
+}
+
+func readDelimited(data []byte, comma rune) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = comma
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("no rows")
+	}
+	for _, record := range records {
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+	}
+	return records, nil
+}
+
+// columns finds each named column in header.
+func columns(header []string, names ...string) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	found := make(map[string]int, len(names))
+	for _, name := range names {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+		found[name] = i
+	}
+	return found, nil
+}
+
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSXRows reads the cell text of the first worksheet, which is all the
+// delineation file needs; cells are placed by their column letters.
+func readXLSXRows(data []byte) ([][]string, error) {
+	var strs xlsxSharedStrings
+	if raw, err := zipEntry(data, "xl/sharedStrings.xml"); err == nil {
+		if err := xml.Unmarshal(raw, &strs); err != nil {
+			return nil, fmt.Errorf("decode shared strings: %w", err)
+		}
+	}
+	shared := make([]string, len(strs.Items))
+	for i, item := range strs.Items {
+		shared[i] = item.Text
+		for _, run := range item.Runs {
+			shared[i] += run.Text
+		}
+	}
+
+	raw, err := zipEntry(data, "xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(raw, &sheet); err != nil {
+		return nil, fmt.Errorf("decode worksheet: %w", err)
+	}
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, sheetRow := range sheet.Rows {
+		var row []string
+		for _, cell := range sheetRow.Cells {
+			column := columnIndex(cell.Ref)
+			for len(row) <= column {
+				row = append(row, "")
+			}
+			switch cell.Type {
+			case "s":
+				i, err := strconv.Atoi(cell.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, fmt.Errorf("cell %s has invalid shared string %q", cell.Ref, cell.Value)
+				}
+				row[column] = shared[i]
+			case "inlineStr":
+				row[column] = cell.Inline
+			default:
+				row[column] = cell.Value
+			}
+			row[column] = strings.TrimSpace(row[column])
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// columnIndex turns the letters of a cell reference such as "AB12" into a
+// zero-based column.
+func columnIndex(ref string) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A'+1)
+	}
+	return column - 1
+}
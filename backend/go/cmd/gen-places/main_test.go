@@ -0,0 +1,133 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPlaceNameDropsLegalDescription(t *testing.T) {
+	cases := map[string]string{
+		"Seattle city":            "Seattle",
+		"Juneau city and borough": "Juneau",
+		"Nashville-Davidson metropolitan government (balance)": "Nashville-Davidson",
+		"Spokane Valley city": "Spokane Valley",
+		"Elma city":           "Elma",
+		"Town":                "Town",
+	}
+	for name, want := range cases {
+		if got := placeName(name); got != want {
+			t.Errorf("placeName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGeneratedPlacesJoinMetrosAndAliases(t *testing.T) {
+	gazetteer := "USPS\tGEOID\tANSICODE\tNAME\tLSAD\tFUNCSTAT\tALAND\tAWATER\tALAND_SQMI\tAWATER_SQMI\tINTPTLAT\tINTPTLONG                                                                                                               \n" +
+		"WA\t5363000\t02411856\tSeattle city\t25\tA\t217425000\t152000000\t83.9\t58.7\t47.620564\t-122.350876          \n" +
+		"MO\t2965000\t00767557\tSt. Louis city\t25\tA\t160000000\t10000000\t61.7\t3.9\t38.635699\t-90.244582          \n" +
+		"WA\t5320680\t02410416\tElma city\t25\tA\t5000000\t0\t1.9\t0\t47.004\t-123.404          \n"
+	placeCounty := "STATE|STATEFP|COUNTYFP|COUNTYNAME|PLACEFP|PLACENS|PLACENAME|TYPE|CLASSFP|FUNCSTAT\n" +
+		"WA|53|033|King County|63000|02411856|Seattle city|INCORPORATED PLACE|C1|A\n" +
+		"MO|29|510|St. Louis city|65000|00767557|St. Louis city|INCORPORATED PLACE|C7|A\n" +
+		"WA|53|027|Grays Harbor County|20680|02410416|Elma city|INCORPORATED PLACE|C1|A\n"
+	delineation := [][]string{
+		{"List 1. Core Based Statistical Areas"},
+		{"CBSA Code", "Metropolitan Division Code", "CSA Code", "CBSA Title", "Metropolitan/Micropolitan Statistical Area", "Metropolitan Division Title", "CSA Title", "County/County Equivalent", "State Name", "FIPS State Code", "FIPS County Code", "Central/Outlying County"},
+		{"42660", "42644", "500", "Seattle-Tacoma-Bellevue, WA", "Metropolitan Statistical Area", "", "", "King County", "Washington", "53", "033", "Central"},
+		{"41180", "", "476", "St. Louis, MO-IL", "Metropolitan Statistical Area", "", "", "St. Louis city", "Missouri", "29", "510", "Central"},
+		{"10140", "", "", "Aberdeen, WA", "Micropolitan Statistical Area", "", "", "Grays Harbor County", "Washington", "53", "027", "Central"},
+	}
+
+	places, err := readGazetteerPlaces([]byte(gazetteer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	counties, err := readPlaceCounties([]byte(placeCounty))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := readXLSXRows(testWorkbook(t, delineation))
+	if err != nil {
+		t.Fatal(err)
+	}
+	metros, err := metroByCounty(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for geoid, p := range places {
+		for _, county := range counties[geoid] {
+			if metro := metros[county]; metro != "" {
+				p.metro = metro
+				break
+			}
+		}
+	}
+	var out bytes.Buffer
+	if err := writePlaces(&out, places); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "city,state,lat,lon,metro,aliases\n" +
+		"St. Louis,MO,38.6357,-90.2446,\"St. Louis, MO-IL\",Saint Louis\n" +
+		"Elma,WA,47.0040,-123.4040,,\n" +
+		"Seattle,WA,47.6206,-122.3509,\"Seattle-Tacoma-Bellevue, WA\",\n"
+	if out.String() != want {
+		t.Errorf("generated places:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestWritePlacesPrefersIncorporatedPlaceOfTheSameName(t *testing.T) {
+	places := map[string]*place{
+		"1": {name: "Springfield", state: "IL", latitude: 1, longitude: 1, landArea: 900},
+		"2": {name: "Springfield", state: "IL", latitude: 2, longitude: 2, landArea: 100, incorporated: true},
+	}
+	var out bytes.Buffer
+	if err := writePlaces(&out, places); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Springfield,IL,2.0000,2.0000") || strings.Count(out.String(), "Springfield") != 1 {
+		t.Errorf("generated places = %q, want only the incorporated Springfield", out.String())
+	}
+}
+
+// testWorkbook builds a minimal xlsx with one worksheet of shared strings.
+func testWorkbook(t *testing.T, rows [][]string) []byte {
+	t.Helper()
+	var shared, sheet strings.Builder
+	shared.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	index := 0
+	for r, row := range rows {
+		sheet.WriteString("<row>")
+		for c, value := range row {
+			if value == "" {
+				continue
+			}
+			shared.WriteString("<si><t>" + value + "</t></si>")
+			sheet.WriteString(`<c r="` + string(rune('A'+c)) + strconv.Itoa(r+1) + `" t="s"><v>` + strconv.Itoa(index) + "</v></c>")
+			index++
+		}
+		sheet.WriteString("</row>")
+	}
+	shared.WriteString("</sst>")
+	sheet.WriteString("</sheetData></worksheet>")
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range map[string]string{"xl/sharedStrings.xml": shared.String(), "xl/worksheets/sheet1.xml": sheet.String()} {
+		w, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
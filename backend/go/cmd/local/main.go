@@ -14,6 +14,18 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 	cfg.UseJobIDFile = true
+	if cfg.HTTPFixtureMode != "" {
+		// recorded and replayed runs start from the same empty state so a
+		// replay asks for exactly the requests the recording captured
+		cfg.UseJobIDFile = false
+		cfg.UseScrapeWatermarks = false
+		cfg.UseScrapeCheckpoints = false
+		cfg.UseEnrichmentCache = false
+		cfg.UseDeadLetters = false
+		cfg.UseSkillReview = false
+		cfg.UsePostingHistory = false
+		log.Printf("HTTP fixture %s mode using %s; job ID cache, watermarks, checkpoints, enrichment cache, dead letters, skill review, and posting history are off", cfg.HTTPFixtureMode, cfg.HTTPFixtureDir)
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/internal/app"
+)
+
+// reparse enriches stored jobs again from their archived descriptions after
+// a prompt or schema change, updating their enriched fields in place.
+func main() {
+	today := time.Now().Format("2006-01-02")
+	from := flag.String("from", today, "first PostedDate to select, YYYY-MM-DD")
+	to := flag.String("to", today, "last PostedDate to select, YYYY-MM-DD")
+	outdated := flag.Bool("outdated", false, "select only jobs not extracted by the current prompt version")
+	versions := flag.String("version", "", "comma-separated ExtractionVersion values to select, e.g. fallback")
+	dryRun := flag.Bool("dry-run", false, "count the selected jobs without parsing them")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	opts := app.ReparseOptions{StartDate: *from, EndDate: *to, Outdated: *outdated, DryRun: *dryRun}
+	if *versions != "" {
+		for _, version := range strings.Split(*versions, ",") {
+			opts.Versions = append(opts.Versions, strings.TrimSpace(version))
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := app.Reparse(ctx, cfg, opts)
+	if err != nil {
+		log.Fatalf("Reparse failed: %v", err)
+	}
+	if opts.DryRun {
+		log.Printf("Dry run: %d of %d job(s) selected", result.Selected, result.Scanned)
+		return
+	}
+	log.Printf("Reparsed %d of %d selected job(s), ~$%.4f; %d failed, %d without a description",
+		result.Updated, result.Selected, result.Stats.TotalUsage().CostUSD, result.Stats.FailedJobs, result.Skipped)
+}
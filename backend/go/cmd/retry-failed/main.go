@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"gopher-source/config"
+	"gopher-source/internal/app"
+)
+
+// retry-failed parses the jobs whose enrichment failed again. Each job waits
+// DEAD_LETTER_BACKOFF_MINUTES after its first failure, doubling after every
+// further one, and is given up on after DEAD_LETTER_MAX_ATTEMPTS, so the
+// command can run on a schedule.
+func main() {
+	force := flag.Bool("force", false, "retry every queued job now, ignoring backoff and the attempt limit")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	result, err := app.RetryFailedJobs(ctx, cfg, *force)
+	if err != nil {
+		log.Fatalf("Retrying failed jobs failed: %v", err)
+	}
+	log.Printf("Retried %d job(s): %d recovered, ~$%.4f; %d still queued (%d out of attempts)",
+		result.Retried, result.Recovered, result.Stats.TotalUsage().CostUSD, result.Remaining, result.Exhausted)
+}
@@ -22,6 +22,7 @@ import (
 
 	"gopher-source/config"
 	"gopher-source/internal/app"
+	"gopher-source/models"
 )
 
 type Request events.APIGatewayV2HTTPRequest
@@ -32,8 +33,13 @@ type apiResponse struct {
 	Query         string               `json:"query"`
 	DebugMode     bool                 `json:"debugMode"`
 	DryRun        bool                 `json:"dryRunMode"`
+	Queries       []models.QueryStats  `json:"queries,omitempty"`
 	Stats         jobStatsPayload      `json:"stats"`
 	JobCache      jobCachePayload      `json:"jobCache"`
+	Checkpoint    checkpointPayload    `json:"checkpoint"`
+	Enrichment    enrichmentPayload    `json:"enrichment"`
+	History       historyPayload       `json:"history"`
+	Usage         usagePayload         `json:"usage"`
 	LambdaMetrics lambdaMetricsPayload `json:"lambdaMetrics"`
 }
 
@@ -44,9 +50,17 @@ type jobStatsPayload struct {
 	UnrelatedJobs        int64   `json:"unrelatedJobs"`
 	SuccessfullyParsed   int64   `json:"successfullyParsed"`
 	FailedToParse        int64   `json:"failedToParse"`
+	EnrichmentCacheHits  int64   `json:"enrichmentCacheHits"`
+	YOERetries           int64   `json:"yoeRetries"`
+	FallbackJobs         int64   `json:"fallbackJobs"`
 	SuccessRate          float64 `json:"successRate"`
 	ExecutionTimeSeconds float64 `json:"executionTimeSeconds"`
 	JobsPerSecond        float64 `json:"jobsPerSecond"`
+	StoredJobs           int64   `json:"storedJobs"`
+	StoreCalls           int64   `json:"storeCalls"`
+	StoreRetries         int64   `json:"storeRetries"`
+	StoreFailures        int64   `json:"storeFailures"`
+	StoreWritesPerSecond float64 `json:"storeWritesPerSecond"`
 }
 
 type jobCachePayload struct {
@@ -58,6 +72,34 @@ type jobCachePayload struct {
 	ObjectKey      string `json:"objectKey,omitempty"`
 }
 
+type checkpointPayload struct {
+	Resumed     bool `json:"resumed"`
+	Interrupted bool `json:"interrupted"`
+	Saved       bool `json:"saved"`
+}
+
+type enrichmentPayload struct {
+	Mode         string   `json:"mode"`
+	JobsEnqueued int      `json:"jobsEnqueued,omitempty"`
+	Batches      []string `json:"batches,omitempty"`
+	DeadLetters  int      `json:"deadLetters,omitempty"`
+}
+
+type historyPayload struct {
+	PostingsUpdated int `json:"postingsUpdated"`
+	PostingsClosed  int `json:"postingsClosed"`
+	PostingsFailed  int `json:"postingsFailed"`
+}
+
+type usagePayload struct {
+	Calls            int64               `json:"calls"`
+	PromptTokens     int64               `json:"promptTokens"`
+	CachedTokens     int64               `json:"cachedTokens"`
+	CompletionTokens int64               `json:"completionTokens"`
+	EstimatedCostUSD float64             `json:"estimatedCostUsd"`
+	Models           []models.TokenUsage `json:"models,omitempty"`
+}
+
 type lambdaMetricsPayload struct {
 	FunctionDurationMs  int64   `json:"functionDurationMs"`
 	BilledDurationMs    int64   `json:"billedDurationMs"`
@@ -82,13 +124,31 @@ func handler(ctx context.Context, event Request) (Response, error) {
 	}
 	functionDuration := time.Since(start)
 
+	message := "Job processing completed"
+	if runResult.Interrupted {
+		message = "Job processing stopped before the deadline"
+	}
 	payload := apiResponse{
-		Message:       "Job processing completed",
-		Query:         cfg.Query,
-		DebugMode:     parseBool(cfg.DebugOutput),
-		DryRun:        parseBool(cfg.ApiDryRun),
-		Stats:         buildJobStatsPayload(runResult),
-		JobCache:      buildJobCachePayload(runResult),
+		Message:    message,
+		Query:      cfg.Query,
+		DebugMode:  parseBool(cfg.DebugOutput),
+		DryRun:     parseBool(cfg.ApiDryRun),
+		Queries:    runResult.QueryStats,
+		Stats:      buildJobStatsPayload(runResult),
+		JobCache:   buildJobCachePayload(runResult),
+		Checkpoint: buildCheckpointPayload(runResult),
+		Enrichment: enrichmentPayload{
+			Mode:         cfg.EnrichmentMode,
+			JobsEnqueued: runResult.JobsEnqueued,
+			Batches:      runResult.EnrichmentBatchIDs,
+			DeadLetters:  runResult.DeadLetters,
+		},
+		History: historyPayload{
+			PostingsUpdated: runResult.PostingsUpdated,
+			PostingsClosed:  runResult.PostingsClosed,
+			PostingsFailed:  runResult.PostingsFailed,
+		},
+		Usage:         buildUsagePayload(runResult),
 		LambdaMetrics: buildLambdaMetrics(functionDuration, runResult.ExecutionTime),
 	}
 
@@ -105,16 +165,18 @@ func evaluateSnapshotTrigger(
 		return
 	}
 
-	log.Printf("snapshot trigger evaluation: jobsAdded=%d cacheEnabled=%t snapshotLambdaSet=%t", runResult.JobsAddedToCache, runResult.JobCacheEnabled, cfg.SnapshotLambda != "")
+	log.Printf("snapshot trigger evaluation: jobsAdded=%d postingsUpdated=%d cacheEnabled=%t snapshotLambdaSet=%t", runResult.JobsAddedToCache, runResult.PostingsUpdated, runResult.JobCacheEnabled, cfg.SnapshotLambda != "")
 	switch {
 	case !runResult.JobCacheEnabled:
 		log.Printf("snapshot trigger skipped: job cache disabled")
-	case runResult.JobsAddedToCache <= 0:
-		log.Printf("snapshot trigger skipped: no new jobs added to cache")
+	case runResult.JobsAddedToCache <= 0 && runResult.PostingsUpdated <= 0:
+		log.Printf("snapshot trigger skipped: no new jobs added to cache and no postings updated")
+	case runResult.JobsEnqueued > 0:
+		log.Printf("snapshot trigger skipped: new jobs are waiting on batch enrichment")
 	case cfg.SnapshotLambda == "":
 		log.Printf("snapshot trigger skipped: SNAPSHOT_LAMBDA_FUNCTION_NAME not configured")
 	default:
-		log.Printf("invoking snapshot lambda %s after %d new jobs", cfg.SnapshotLambda, runResult.JobsAddedToCache)
+		log.Printf("invoking snapshot lambda %s after %d new jobs and %d updated postings", cfg.SnapshotLambda, runResult.JobsAddedToCache, runResult.PostingsUpdated)
 		if err := trigger(ctx, cfg); err != nil {
 			log.Printf("snapshot trigger failed: %v", err)
 		} else {
@@ -129,8 +191,10 @@ func applyRequestOverrides(cfg *config.Config, params map[string]string) {
 		return
 	}
 
+	// an explicit query replaces any configured scrape plan
 	if q := strings.TrimSpace(params["query"]); q != "" {
 		cfg.Query = q
+		cfg.ScrapePlan = nil
 	}
 
 	if pages := strings.TrimSpace(params["pages"]); pages != "" {
@@ -184,9 +248,33 @@ func buildJobStatsPayload(result *app.RunResult) jobStatsPayload {
 		UnrelatedJobs:        stats.UnrelatedJobs,
 		SuccessfullyParsed:   stats.SuccessfulJobs,
 		FailedToParse:        stats.FailedJobs,
+		EnrichmentCacheHits:  stats.EnrichmentCacheHits,
+		YOERetries:           stats.YOERetries,
+		FallbackJobs:         stats.FallbackJobs,
 		SuccessRate:          round(successRate, 2),
 		ExecutionTimeSeconds: round(executionSeconds, 2),
 		JobsPerSecond:        round(jobsPerSecond, 4),
+		StoredJobs:           stats.StoredJobs,
+		StoreCalls:           stats.StoreCalls,
+		StoreRetries:         stats.StoreRetries,
+		StoreFailures:        stats.StoreFailures,
+		StoreWritesPerSecond: round(stats.StoreWritesPerSecond(), 2),
+	}
+}
+
+func buildUsagePayload(result *app.RunResult) usagePayload {
+	byModel := result.Stats.UsageByModel()
+	for i := range byModel {
+		byModel[i].CostUSD = round(byModel[i].CostUSD, 6)
+	}
+	total := result.Stats.TotalUsage()
+	return usagePayload{
+		Calls:            total.Calls,
+		PromptTokens:     total.PromptTokens,
+		CachedTokens:     total.CachedTokens,
+		CompletionTokens: total.CompletionTokens,
+		EstimatedCostUSD: round(total.CostUSD, 6),
+		Models:           byModel,
 	}
 }
 
@@ -201,6 +289,14 @@ func buildJobCachePayload(result *app.RunResult) jobCachePayload {
 	}
 }
 
+func buildCheckpointPayload(result *app.RunResult) checkpointPayload {
+	return checkpointPayload{
+		Resumed:     result.ResumedFromCheckpoint,
+		Interrupted: result.Interrupted,
+		Saved:       result.CheckpointSaved,
+	}
+}
+
 func buildLambdaMetrics(functionDuration, pipelineDuration time.Duration) lambdaMetricsPayload {
 	memStats := &runtime.MemStats{}
 	runtime.ReadMemStats(memStats)
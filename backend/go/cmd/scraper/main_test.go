@@ -8,6 +8,7 @@ import (
 
 	"gopher-source/config"
 	"gopher-source/internal/app"
+	"gopher-source/models"
 )
 
 func TestErrorResponseReturnsLambdaError(t *testing.T) {
@@ -51,3 +52,46 @@ func TestEvaluateSnapshotTriggerSkipsResultWithoutNewJobs(t *testing.T) {
 		t.Fatal("expected snapshot trigger to be skipped without new jobs")
 	}
 }
+
+func TestEvaluateSnapshotTriggerInvokesForUpdatedPostings(t *testing.T) {
+	cfg := &config.Config{SnapshotLambda: "snapshot-lambda"}
+	result := &app.RunResult{JobCacheEnabled: true, PostingsUpdated: 2}
+	called := false
+
+	evaluateSnapshotTrigger(context.Background(), cfg, result, func(context.Context, *config.Config) error {
+		called = true
+		return nil
+	})
+
+	if !called {
+		t.Fatal("expected snapshot trigger when closed or edited postings were stored")
+	}
+}
+
+func TestApplyRequestOverridesQueryReplacesScrapePlan(t *testing.T) {
+	cfg := &config.Config{
+		Query:      "software engineer",
+		ScrapePlan: []config.ScrapeQuery{{Query: "sre"}, {Query: "data engineer"}},
+	}
+
+	applyRequestOverrides(cfg, map[string]string{"query": "  platform engineer "})
+
+	queries := cfg.ScrapeQueries()
+	if len(queries) != 1 || queries[0].Query != "platform engineer" {
+		t.Fatalf("expected override to replace the plan, got %+v", queries)
+	}
+}
+
+func TestBuildUsagePayloadTotalsModels(t *testing.T) {
+	stats := &models.JobStats{}
+	stats.AddUsage(models.TokenUsage{Model: "gpt-4.1-nano", Calls: 1, PromptTokens: 1000, CachedTokens: 400, CompletionTokens: 200, CostUSD: 0.00015})
+	stats.AddUsage(models.TokenUsage{Model: "gpt-4.1-mini", Calls: 1, PromptTokens: 1000, CompletionTokens: 100, CostUSD: 0.00056})
+
+	payload := buildUsagePayload(&app.RunResult{Stats: stats.Snapshot()})
+	if payload.Calls != 2 || payload.PromptTokens != 2000 || payload.CachedTokens != 400 || payload.CompletionTokens != 300 {
+		t.Fatalf("unexpected totals: %+v", payload)
+	}
+	if payload.EstimatedCostUSD != 0.00071 || len(payload.Models) != 2 || payload.Models[0].Model != "gpt-4.1-mini" {
+		t.Fatalf("unexpected cost breakdown: %+v", payload)
+	}
+}
@@ -45,7 +45,10 @@ func handler(ctx context.Context) (Response, error) {
 		return errorResponse(http.StatusInternalServerError, fmt.Errorf("load aws config: %w", err))
 	}
 
-	dynamoService := services.NewDynamoService(awscfg, cfg.DynamoTableName, cfg.DynamoEndpoint)
+	store, err := services.NewJobStore(*cfg, awscfg)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, fmt.Errorf("open job store: %w", err))
+	}
 	s3Service := services.NewS3Service(awscfg)
 
 	startDate, endDate, err := determineDateRange(cfg)
@@ -54,17 +57,9 @@ func handler(ctx context.Context) (Response, error) {
 	}
 
 	// get sorted jobs in descending order
-	var sortedJobs []models.Job
-	dateCursor, _ := time.Parse("2006-01-02", startDate)
-	endTime, _ := time.Parse("2006-01-02", endDate)
-	for !dateCursor.After(endTime) {
-		dateStr := dateCursor.Format("2006-01-02")
-		dailyJobs, queryErr := dynamoService.QueryJobsByPostedDate(ctx, dateStr)
-		if queryErr != nil {
-			return errorResponse(http.StatusInternalServerError, fmt.Errorf("query DynamoDB for %s: %w", dateStr, queryErr))
-		}
-		sortedJobs = append(sortedJobs, dailyJobs...)
-		dateCursor = dateCursor.AddDate(0, 0, 1)
+	sortedJobs, err := store.QueryJobsByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return errorResponse(http.StatusInternalServerError, fmt.Errorf("query jobs from %s to %s: %w", startDate, endDate, err))
 	}
 
 	log.Printf("snapshot: fetched %d jobs between %s and %s", len(sortedJobs), startDate, endDate)
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -13,30 +14,101 @@ import (
 )
 
 type Config struct {
-	MaxPages          int // 1 page = 1 apex api call = up to 100 jobs per call
-	BaseURL           string
-	RequestDelay      time.Duration
-	OpenAIAPIKey      string
-	Query             string
-	DebugOutput       string
-	ApiDryRun         string
-	MaxConcurrency    int
-	DefaultQuery      string
-	Filename          string
-	UseJobIDFile      bool
-	UseS3JobIDFile    bool
-	AWSRegion         string
-	DynamoTableName   string
-	DynamoEndpoint    string
-	JobIDsBucket      string
-	JobIDsS3Key       string
-	SnapshotBucket    string
-	SnapshotS3Key     string
-	SnapshotLambda    string
-	SnapshotStartDate string
-	SnapshotEndDate   string
+	MaxPages              int // 1 page = 1 apex api call = up to 100 jobs per call
+	BaseURL               string
+	RequestDelay          time.Duration
+	OpenAIAPIKey          string
+	LLMProvider           string // openai, openai-compatible, or anthropic
+	LLMModel              string // empty uses the provider default
+	LLMBaseURL            string
+	LLMAPIKey             string
+	EnrichmentMode        string // sync parses during the run; batch submits to the OpenAI Batch API
+	Query                 string
+	ScrapePlan            []ScrapeQuery
+	JobSources            []string
+	GreenhouseBoardTokens []string
+	LeverBoardTokens      []string
+	DebugOutput           string
+	ApiDryRun             string
+	MaxConcurrency        int
+	DefaultQuery          string
+	Filename              string
+	UseJobIDFile          bool
+	UseS3JobIDFile        bool
+	UseScrapeWatermarks   bool
+	WatermarksFilename    string
+	UseScrapeCheckpoints  bool
+	CheckpointFilename    string
+	CheckpointReserve     time.Duration // time left before the Lambda deadline to drain jobs and save the checkpoint
+	UseEnrichmentCache    bool
+	EnrichmentCacheFile   string
+	EnrichmentCacheMaxAge time.Duration // entries not served for this long are evicted
+	EnrichmentCacheSize   int           // most entries kept, least recently used evicted first
+	EnrichmentBatchFile   string
+	UseDeadLetters        bool // queue jobs whose enrichment failed for cmd/retry-failed
+	DeadLetterFile        string
+	DeadLetterMaxAttempts int
+	DeadLetterBackoff     time.Duration // wait after the first failed attempt, doubled after each later one
+	UseDescriptionArchive bool          // keep raw descriptions in S3 for reparsing
+	UseSkillReview        bool          // collect skills missing from the taxonomy
+	SkillReviewFile       string
+	UsePostingHistory     bool // revisit known listings to record edits and closures
+	PostingHistoryFile    string
+	DescriptionBucket     string
+	DescriptionPrefix     string
+	AWSRegion             string
+	DynamoTableName       string
+	DynamoEndpoint        string
+	JobStore              string // dynamodb, sqlite, or memory
+	SQLitePath            string
+	JobWriteMode          string // insert skips stored jobs; upsert replaces changed postings
+	UseBatchWrites        bool   // coalesce inserts into 25-job store writes
+	JobIDsBucket          string
+	JobIDsS3Key           string
+	WatermarksS3Key       string
+	CheckpointS3Key       string
+	EnrichmentCacheS3Key  string
+	EnrichmentBatchS3Key  string
+	DeadLetterS3Key       string
+	SkillReviewS3Key      string
+	PostingHistoryS3Key   string
+	SnapshotBucket        string
+	SnapshotS3Key         string
+	SnapshotLambda        string
+	SnapshotStartDate     string
+	SnapshotEndDate       string
+	HTTPFixtureMode       string // record or replay outbound API calls; empty goes to the network
+	HTTPFixtureDir        string
 }
 
+const (
+	HTTPFixtureRecord = "record"
+	HTTPFixtureReplay = "replay"
+)
+
+const (
+	JobStoreDynamoDB = "dynamodb"
+	JobStoreSQLite   = "sqlite"
+	JobStoreMemory   = "memory"
+)
+
+const (
+	JobWriteInsert = "insert"
+	JobWriteUpsert = "upsert"
+)
+
+const (
+	scrapeWatermarksFile  = "scrape-watermarks.json"
+	scrapeCheckpointFile  = "scrape-checkpoint.json"
+	enrichmentCacheFile   = "enrichment-cache.json"
+	enrichmentBatchFile   = "enrichment-batches.json"
+	deadLetterFile        = "dead-letters.jsonl"
+	skillReviewFile       = "skill-review.json"
+	postingHistoryFile    = "posting-history.json"
+	sqliteFile            = "jobs.db"
+	descriptionArchiveDir = "descriptions"
+)
+
 var (
 	envLoadOnce sync.Once
 	envLoadErr  error
@@ -54,39 +126,108 @@ func Load() (*Config, error) {
 	query := getEnvOrDefault("QUERY", "software engineer")
 	jobIDsPath := getEnvOrDefault("JOB_IDS_PATH", defaultJobIDsPath())
 
+	fixtureMode := strings.ToLower(strings.TrimSpace(os.Getenv("HTTP_FIXTURE_MODE")))
+	switch fixtureMode {
+	case "", HTTPFixtureRecord, HTTPFixtureReplay:
+	default:
+		return nil, fmt.Errorf("HTTP_FIXTURE_MODE must be %q or %q, got %q", HTTPFixtureRecord, HTTPFixtureReplay, fixtureMode)
+	}
+
+	jobStore := strings.ToLower(getEnvOrDefault("JOB_STORE", JobStoreDynamoDB))
+	switch jobStore {
+	case JobStoreDynamoDB, JobStoreSQLite, JobStoreMemory:
+	default:
+		return nil, fmt.Errorf("JOB_STORE must be %q, %q or %q, got %q", JobStoreDynamoDB, JobStoreSQLite, JobStoreMemory, jobStore)
+	}
+
+	jobWriteMode := strings.ToLower(getEnvOrDefault("JOB_WRITE_MODE", JobWriteInsert))
+	switch jobWriteMode {
+	case JobWriteInsert, JobWriteUpsert:
+	default:
+		return nil, fmt.Errorf("JOB_WRITE_MODE must be %q or %q, got %q", JobWriteInsert, JobWriteUpsert, jobWriteMode)
+	}
+
 	apiKey := strings.TrimSpace(os.Getenv("OPENAI_API_KEY"))
 	apiDryRun := getBoolEnv("API_DRY_RUN", false)
-	if apiKey == "" && apiDryRun != "true" {
-		return nil, fmt.Errorf("OPENAI_API_KEY must be set unless API_DRY_RUN is true")
-	}
 
 	useJobIDFile := normalizeBoolString(os.Getenv("USE_JOB_ID_FILE"), !runningInLambda()) == "true"
 	useS3JobIDFile := normalizeBoolString(os.Getenv("USE_S3_JOB_ID_FILE"), runningInLambda()) == "true"
+	jobIDsS3Key := strings.TrimSpace(os.Getenv("JOB_IDS_S3_KEY"))
+	descriptionPrefix := descriptionArchiveDir
+	if jobIDsS3Key != "" {
+		descriptionPrefix = siblingS3Key(jobIDsS3Key, descriptionArchiveDir)
+	}
+	scrapePlan, err := loadScrapePlan()
+	if err != nil {
+		return nil, err
+	}
 
-	return &Config{
-		MaxPages:          getIntEnv("MAX_PAGES", 5),
-		BaseURL:           "https://seeker.worksourcewa.com/",
-		RequestDelay:      1 * time.Nanosecond,
-		OpenAIAPIKey:      apiKey,
-		Query:             query,
-		DebugOutput:       getBoolEnv("DEBUG_OUTPUT", false),
-		ApiDryRun:         apiDryRun,
-		MaxConcurrency:    25,
-		DefaultQuery:      query,
-		Filename:          jobIDsPath,
-		UseJobIDFile:      useJobIDFile,
-		UseS3JobIDFile:    useS3JobIDFile,
-		AWSRegion:         getEnvOrDefault("AWS_REGION", "us-west-2"),
-		DynamoTableName:   getEnvOrDefault("DYNAMODB_TABLE_NAME", "Jobs"),
-		DynamoEndpoint:    strings.TrimSpace(os.Getenv("DYNAMODB_ENDPOINT")),
-		JobIDsBucket:      strings.TrimSpace(os.Getenv("JOB_IDS_BUCKET")),
-		JobIDsS3Key:       strings.TrimSpace(os.Getenv("JOB_IDS_S3_KEY")),
-		SnapshotBucket:    strings.TrimSpace(os.Getenv("SNAPSHOT_BUCKET")),
-		SnapshotS3Key:     strings.TrimSpace(os.Getenv("SNAPSHOT_S3_KEY")),
-		SnapshotLambda:    strings.TrimSpace(os.Getenv("SNAPSHOT_LAMBDA_FUNCTION_NAME")),
-		SnapshotStartDate: strings.TrimSpace(os.Getenv("SNAPSHOT_START_DATE")),
-		SnapshotEndDate:   strings.TrimSpace(os.Getenv("SNAPSHOT_END_DATE")),
-	}, nil
+	cfg := &Config{
+		MaxPages:              getIntEnv("MAX_PAGES", 5),
+		BaseURL:               "https://seeker.worksourcewa.com/",
+		RequestDelay:          1 * time.Nanosecond,
+		OpenAIAPIKey:          apiKey,
+		Query:                 query,
+		ScrapePlan:            scrapePlan,
+		JobSources:            getListEnv("JOB_SOURCES", []string{"worksourcewa"}),
+		GreenhouseBoardTokens: getListEnv("GREENHOUSE_BOARD_TOKENS", nil),
+		LeverBoardTokens:      getListEnv("LEVER_BOARD_TOKENS", nil),
+		DebugOutput:           getBoolEnv("DEBUG_OUTPUT", false),
+		ApiDryRun:             apiDryRun,
+		MaxConcurrency:        25,
+		DefaultQuery:          query,
+		Filename:              jobIDsPath,
+		UseJobIDFile:          useJobIDFile,
+		UseS3JobIDFile:        useS3JobIDFile,
+		UseScrapeWatermarks:   getBoolEnv("USE_SCRAPE_WATERMARKS", true) == "true",
+		WatermarksFilename:    getEnvOrDefault("SCRAPE_WATERMARKS_PATH", siblingPath(jobIDsPath, scrapeWatermarksFile)),
+		UseScrapeCheckpoints:  getBoolEnv("USE_SCRAPE_CHECKPOINTS", true) == "true",
+		CheckpointFilename:    getEnvOrDefault("SCRAPE_CHECKPOINT_PATH", siblingPath(jobIDsPath, scrapeCheckpointFile)),
+		CheckpointReserve:     time.Duration(getIntEnv("SCRAPE_CHECKPOINT_RESERVE_SECONDS", 90)) * time.Second,
+		UseEnrichmentCache:    getBoolEnv("USE_ENRICHMENT_CACHE", true) == "true",
+		EnrichmentCacheFile:   getEnvOrDefault("ENRICHMENT_CACHE_PATH", siblingPath(jobIDsPath, enrichmentCacheFile)),
+		EnrichmentCacheMaxAge: time.Duration(getIntEnv("ENRICHMENT_CACHE_MAX_AGE_DAYS", 30)) * 24 * time.Hour,
+		EnrichmentCacheSize:   getIntEnv("ENRICHMENT_CACHE_MAX_ENTRIES", 5000),
+		EnrichmentBatchFile:   getEnvOrDefault("ENRICHMENT_BATCH_PATH", siblingPath(jobIDsPath, enrichmentBatchFile)),
+		UseDeadLetters:        getBoolEnv("USE_DEAD_LETTERS", true) == "true",
+		DeadLetterFile:        getEnvOrDefault("DEAD_LETTER_PATH", siblingPath(jobIDsPath, deadLetterFile)),
+		DeadLetterMaxAttempts: getIntEnv("DEAD_LETTER_MAX_ATTEMPTS", 5),
+		DeadLetterBackoff:     time.Duration(getIntEnv("DEAD_LETTER_BACKOFF_MINUTES", 15)) * time.Minute,
+		UseSkillReview:        getBoolEnv("USE_SKILL_REVIEW", true) == "true",
+		SkillReviewFile:       getEnvOrDefault("SKILL_REVIEW_PATH", siblingPath(jobIDsPath, skillReviewFile)),
+		UsePostingHistory:     getBoolEnv("USE_POSTING_HISTORY", true) == "true",
+		PostingHistoryFile:    getEnvOrDefault("POSTING_HISTORY_PATH", siblingPath(jobIDsPath, postingHistoryFile)),
+		UseDescriptionArchive: getBoolEnv("USE_DESCRIPTION_ARCHIVE", true) == "true",
+		DescriptionBucket:     getEnvOrDefault("DESCRIPTION_ARCHIVE_BUCKET", strings.TrimSpace(os.Getenv("JOB_IDS_BUCKET"))),
+		DescriptionPrefix:     getEnvOrDefault("DESCRIPTION_ARCHIVE_PREFIX", descriptionPrefix),
+		AWSRegion:             getEnvOrDefault("AWS_REGION", "us-west-2"),
+		DynamoTableName:       getEnvOrDefault("DYNAMODB_TABLE_NAME", "Jobs"),
+		DynamoEndpoint:        strings.TrimSpace(os.Getenv("DYNAMODB_ENDPOINT")),
+		JobStore:              jobStore,
+		SQLitePath:            getEnvOrDefault("SQLITE_PATH", siblingPath(jobIDsPath, sqliteFile)),
+		JobWriteMode:          jobWriteMode,
+		UseBatchWrites:        getBoolEnv("USE_BATCH_WRITES", true) == "true",
+		JobIDsBucket:          strings.TrimSpace(os.Getenv("JOB_IDS_BUCKET")),
+		JobIDsS3Key:           jobIDsS3Key,
+		WatermarksS3Key:       getEnvOrDefault("SCRAPE_WATERMARKS_S3_KEY", siblingS3Key(jobIDsS3Key, scrapeWatermarksFile)),
+		CheckpointS3Key:       getEnvOrDefault("SCRAPE_CHECKPOINT_S3_KEY", siblingS3Key(jobIDsS3Key, scrapeCheckpointFile)),
+		EnrichmentCacheS3Key:  getEnvOrDefault("ENRICHMENT_CACHE_S3_KEY", siblingS3Key(jobIDsS3Key, enrichmentCacheFile)),
+		EnrichmentBatchS3Key:  getEnvOrDefault("ENRICHMENT_BATCH_S3_KEY", siblingS3Key(jobIDsS3Key, enrichmentBatchFile)),
+		DeadLetterS3Key:       getEnvOrDefault("DEAD_LETTER_S3_KEY", siblingS3Key(jobIDsS3Key, deadLetterFile)),
+		SkillReviewS3Key:      getEnvOrDefault("SKILL_REVIEW_S3_KEY", siblingS3Key(jobIDsS3Key, skillReviewFile)),
+		PostingHistoryS3Key:   getEnvOrDefault("POSTING_HISTORY_S3_KEY", siblingS3Key(jobIDsS3Key, postingHistoryFile)),
+		SnapshotBucket:        strings.TrimSpace(os.Getenv("SNAPSHOT_BUCKET")),
+		SnapshotS3Key:         strings.TrimSpace(os.Getenv("SNAPSHOT_S3_KEY")),
+		SnapshotLambda:        strings.TrimSpace(os.Getenv("SNAPSHOT_LAMBDA_FUNCTION_NAME")),
+		SnapshotStartDate:     strings.TrimSpace(os.Getenv("SNAPSHOT_START_DATE")),
+		SnapshotEndDate:       strings.TrimSpace(os.Getenv("SNAPSHOT_END_DATE")),
+		HTTPFixtureMode:       fixtureMode,
+		HTTPFixtureDir:        getEnvOrDefault("HTTP_FIXTURE_DIR", filepath.Join("testdata", "fixtures")),
+	}
+	if err := loadLLMConfig(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
 }
 
 func EnsureEnvLoaded() error {
@@ -134,6 +275,24 @@ func getIntEnv(key string, fallback int) int {
 	return fallback
 }
 
+// getListEnv splits a comma-separated variable, dropping empty entries.
+func getListEnv(key string, fallback []string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	var values []string
+	for _, part := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			values = append(values, trimmed)
+		}
+	}
+	if len(values) == 0 {
+		return fallback
+	}
+	return values
+}
+
 func normalizeBoolString(value string, fallback bool) string {
 	normalized := strings.ToLower(strings.Trim(value, " \t\n\r,"))
 	switch normalized {
@@ -159,6 +318,20 @@ func defaultJobIDsPath() string {
 	return "job-ids.txt"
 }
 
+// siblingPath places name in the same directory as a local file path.
+func siblingPath(filename, name string) string {
+	return filepath.Join(filepath.Dir(filename), name)
+}
+
+// siblingS3Key places name under the same prefix as an S3 object key, or
+// returns "" when there is no key to sit next to.
+func siblingS3Key(key, name string) string {
+	if key == "" {
+		return ""
+	}
+	return path.Join(path.Dir(key), name)
+}
+
 func runningInLambda() bool {
 	return strings.TrimSpace(os.Getenv("AWS_LAMBDA_FUNCTION_NAME")) != "" ||
 		strings.TrimSpace(os.Getenv("LAMBDA_TASK_ROOT")) != ""
@@ -147,6 +147,7 @@ func TestLoadSuccess(t *testing.T) {
 	t.Setenv("JOB_IDS_S3_KEY", "ids.txt")
 	t.Setenv("SNAPSHOT_BUCKET", "snapshot-bucket")
 	t.Setenv("SNAPSHOT_S3_KEY", "snapshot-key.txt")
+	t.Setenv("JOB_SOURCES", "worksourcewa, greenhouse,,")
 
 	cfg, err := Load()
 	if err != nil {
@@ -195,6 +196,18 @@ func TestLoadSuccess(t *testing.T) {
 	if cfg.SnapshotS3Key != "snapshot-key.txt" {
 		t.Fatalf("expected SnapshotS3Key override, got %q", cfg.SnapshotS3Key)
 	}
+	if strings.Join(cfg.JobSources, "|") != "worksourcewa|greenhouse" {
+		t.Fatalf("expected JobSources override, got %q", cfg.JobSources)
+	}
+	if !cfg.UseScrapeWatermarks {
+		t.Fatalf("expected scrape watermarks to default on")
+	}
+	if cfg.WatermarksFilename != "/tmp/scrape-watermarks.json" {
+		t.Fatalf("expected watermarks file next to JOB_IDS_PATH, got %q", cfg.WatermarksFilename)
+	}
+	if cfg.WatermarksS3Key != "scrape-watermarks.json" {
+		t.Fatalf("expected watermarks key next to JOB_IDS_S3_KEY, got %q", cfg.WatermarksS3Key)
+	}
 }
 
 func TestLoadRequiresAPIKeyWhenNotDryRun(t *testing.T) {
@@ -228,3 +241,213 @@ func TestLoadAllowsDryRunWithoutAPIKey(t *testing.T) {
 		t.Fatalf("expected OpenAIAPIKey to remain empty, got %q", cfg.OpenAIAPIKey)
 	}
 }
+
+func TestLoadHTTPFixtureMode(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("OPENAI_API_KEY", "")
+	t.Setenv("API_DRY_RUN", "false")
+	t.Setenv("HTTP_FIXTURE_DIR", "/tmp/fixtures")
+
+	t.Setenv("HTTP_FIXTURE_MODE", " Replay ")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected replay to run without an API key, got %v", err)
+	}
+	if cfg.HTTPFixtureMode != HTTPFixtureReplay || cfg.HTTPFixtureDir != "/tmp/fixtures" {
+		t.Fatalf("unexpected fixture config: mode=%q dir=%q", cfg.HTTPFixtureMode, cfg.HTTPFixtureDir)
+	}
+
+	t.Setenv("HTTP_FIXTURE_MODE", "record")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "OPENAI_API_KEY") {
+		t.Fatalf("expected record mode to need an API key, got %v", err)
+	}
+
+	t.Setenv("HTTP_FIXTURE_MODE", "rewind")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "HTTP_FIXTURE_MODE") {
+		t.Fatalf("expected invalid mode error, got %v", err)
+	}
+}
+
+func TestLoadJobStore(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("API_DRY_RUN", "true")
+	t.Setenv("JOB_IDS_PATH", filepath.Join("/tmp", "state", "job-ids.txt"))
+	t.Setenv("SQLITE_PATH", "")
+
+	t.Setenv("JOB_STORE", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.JobStore != JobStoreDynamoDB || cfg.SQLitePath != filepath.Join("/tmp", "state", "jobs.db") {
+		t.Fatalf("expected DynamoDB with the SQLite file beside the job ID cache, got %q and %q", cfg.JobStore, cfg.SQLitePath)
+	}
+
+	t.Setenv("JOB_STORE", " SQLite ")
+	if cfg, err = Load(); err != nil || cfg.JobStore != JobStoreSQLite {
+		t.Fatalf("expected the sqlite store, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("JOB_STORE", "postgres")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "JOB_STORE") {
+		t.Fatalf("expected invalid store error, got %v", err)
+	}
+}
+
+func TestLoadJobWriteMode(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("API_DRY_RUN", "true")
+	t.Setenv("JOB_STORE", "")
+
+	t.Setenv("JOB_WRITE_MODE", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.JobWriteMode != JobWriteInsert {
+		t.Fatalf("expected insert-if-absent by default, got %q", cfg.JobWriteMode)
+	}
+
+	t.Setenv("JOB_WRITE_MODE", "Upsert")
+	if cfg, err = Load(); err != nil || cfg.JobWriteMode != JobWriteUpsert {
+		t.Fatalf("expected upsert mode, got %+v (%v)", cfg, err)
+	}
+
+	t.Setenv("JOB_WRITE_MODE", "overwrite")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "JOB_WRITE_MODE") {
+		t.Fatalf("expected invalid write mode error, got %v", err)
+	}
+}
+
+func TestLoadLLMProvider(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	t.Setenv("API_DRY_RUN", "false")
+	t.Setenv("LLM_API_KEY", "")
+	t.Setenv("ANTHROPIC_API_KEY", "")
+	t.Setenv("LLM_MODEL", "")
+	t.Setenv("LLM_BASE_URL", "")
+
+	t.Setenv("LLM_PROVIDER", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.LLMProvider != LLMProviderOpenAI || cfg.LLMAPIKey != "openai-key" {
+		t.Fatalf("expected default openai provider with OPENAI_API_KEY, got %q key=%q", cfg.LLMProvider, cfg.LLMAPIKey)
+	}
+
+	t.Setenv("LLM_PROVIDER", "Anthropic")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "ANTHROPIC_API_KEY") {
+		t.Fatalf("expected anthropic to need its own key, got %v", err)
+	}
+	t.Setenv("ANTHROPIC_API_KEY", "anthropic-key")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.LLMProvider != LLMProviderAnthropic || cfg.LLMAPIKey != "anthropic-key" {
+		t.Fatalf("unexpected anthropic config: %q key=%q", cfg.LLMProvider, cfg.LLMAPIKey)
+	}
+
+	t.Setenv("LLM_PROVIDER", "openai-compatible")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "LLM_BASE_URL") {
+		t.Fatalf("expected openai-compatible to need a base URL, got %v", err)
+	}
+	t.Setenv("LLM_BASE_URL", "http://localhost:11434/v1")
+	t.Setenv("LLM_MODEL", "llama3.1")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.LLMBaseURL != "http://localhost:11434/v1" || cfg.LLMModel != "llama3.1" {
+		t.Fatalf("unexpected openai-compatible config: base=%q model=%q", cfg.LLMBaseURL, cfg.LLMModel)
+	}
+	if cfg.LLMAPIKey != "" {
+		t.Fatalf("expected OPENAI_API_KEY not to be reused for a self-hosted server, got %q", cfg.LLMAPIKey)
+	}
+
+	t.Setenv("LLM_PROVIDER", "gemini")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "LLM_PROVIDER") {
+		t.Fatalf("expected unknown provider error, got %v", err)
+	}
+}
+
+func TestSiblingS3Key(t *testing.T) {
+	cases := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"job-ids.txt", "scrape-watermarks.json"},
+		{"cache/prod/job-ids.txt", "cache/prod/scrape-watermarks.json"},
+	}
+	for _, tc := range cases {
+		if got := siblingS3Key(tc.key, "scrape-watermarks.json"); got != tc.want {
+			t.Fatalf("siblingS3Key(%q) = %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
+
+func TestGetListEnv(t *testing.T) {
+	cases := []struct {
+		name     string
+		setEnv   bool
+		envValue string
+		want     string
+	}{
+		{"missing", false, "", "fallback"},
+		{"single", true, "worksourcewa", "worksourcewa"},
+		{"trimsAndDropsEmpty", true, " a , ,b,", "a|b"},
+		{"onlySeparators", true, " , ", "fallback"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			const key = "JOB_SOURCES"
+			t.Setenv(key, tc.envValue)
+			if !tc.setEnv {
+				if err := os.Unsetenv(key); err != nil {
+					t.Fatalf("failed to unset %s: %v", key, err)
+				}
+			}
+
+			if got := strings.Join(getListEnv(key, []string{"fallback"}), "|"); got != tc.want {
+				t.Fatalf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadEnrichmentMode(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("OPENAI_API_KEY", "openai-key")
+	t.Setenv("API_DRY_RUN", "false")
+	t.Setenv("LLM_PROVIDER", "")
+
+	t.Setenv("ENRICHMENT_MODE", "Batch")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.EnrichmentMode != EnrichmentModeBatch {
+		t.Fatalf("expected batch mode, got %q", cfg.EnrichmentMode)
+	}
+
+	t.Setenv("LLM_PROVIDER", "anthropic")
+	t.Setenv("ANTHROPIC_API_KEY", "anthropic-key")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "ENRICHMENT_MODE") {
+		t.Fatalf("expected batch mode to require the openai provider, got %v", err)
+	}
+
+	t.Setenv("LLM_PROVIDER", "")
+	t.Setenv("ENRICHMENT_MODE", "later")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "ENRICHMENT_MODE") {
+		t.Fatalf("expected invalid mode error, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	LLMProviderOpenAI           = "openai"
+	LLMProviderOpenAICompatible = "openai-compatible"
+	LLMProviderAnthropic        = "anthropic"
+)
+
+const (
+	EnrichmentModeSync  = "sync"
+	EnrichmentModeBatch = "batch"
+)
+
+// loadLLMConfig selects the enrichment provider from LLM_PROVIDER and how jobs
+// reach it from ENRICHMENT_MODE. The API key
+// comes from LLM_API_KEY or the provider's own variable and is only required
+// when the run actually calls the provider.
+func loadLLMConfig(cfg *Config) error {
+	cfg.LLMProvider = strings.ToLower(getEnvOrDefault("LLM_PROVIDER", LLMProviderOpenAI))
+	cfg.LLMModel = strings.TrimSpace(os.Getenv("LLM_MODEL"))
+	cfg.LLMBaseURL = strings.TrimSpace(os.Getenv("LLM_BASE_URL"))
+	callsProvider := cfg.ApiDryRun != "true" && cfg.HTTPFixtureMode != HTTPFixtureReplay
+
+	switch cfg.LLMProvider {
+	case LLMProviderOpenAI:
+		cfg.LLMAPIKey = getEnvOrDefault("LLM_API_KEY", cfg.OpenAIAPIKey)
+		if cfg.LLMAPIKey == "" && callsProvider {
+			return fmt.Errorf("OPENAI_API_KEY must be set unless API_DRY_RUN is true or HTTP_FIXTURE_MODE is replay")
+		}
+	case LLMProviderAnthropic:
+		cfg.LLMAPIKey = getEnvOrDefault("LLM_API_KEY", strings.TrimSpace(os.Getenv("ANTHROPIC_API_KEY")))
+		if cfg.LLMAPIKey == "" && callsProvider {
+			return fmt.Errorf("ANTHROPIC_API_KEY or LLM_API_KEY must be set for the %s provider", LLMProviderAnthropic)
+		}
+	case LLMProviderOpenAICompatible:
+		// self-hosted servers usually ignore the key, so OPENAI_API_KEY is
+		// never sent to them
+		cfg.LLMAPIKey = strings.TrimSpace(os.Getenv("LLM_API_KEY"))
+		if cfg.LLMBaseURL == "" || cfg.LLMModel == "" {
+			return fmt.Errorf("LLM_BASE_URL and LLM_MODEL must be set for the %s provider", LLMProviderOpenAICompatible)
+		}
+	default:
+		return fmt.Errorf("LLM_PROVIDER must be %q, %q, or %q, got %q", LLMProviderOpenAI, LLMProviderOpenAICompatible, LLMProviderAnthropic, cfg.LLMProvider)
+	}
+
+	cfg.EnrichmentMode = strings.ToLower(getEnvOrDefault("ENRICHMENT_MODE", EnrichmentModeSync))
+	switch cfg.EnrichmentMode {
+	case EnrichmentModeSync:
+	case EnrichmentModeBatch:
+		if cfg.LLMProvider != LLMProviderOpenAI {
+			return fmt.Errorf("ENRICHMENT_MODE %q requires LLM_PROVIDER %q", EnrichmentModeBatch, LLMProviderOpenAI)
+		}
+	default:
+		return fmt.Errorf("ENRICHMENT_MODE must be %q or %q, got %q", EnrichmentModeSync, EnrichmentModeBatch, cfg.EnrichmentMode)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScrapeQuery is one entry of a scrape plan. Optional filters are passed
+// through to WorkSourceWA's initializeJobSearch; board APIs without server-side
+// search apply Query and Location client-side.
+type ScrapeQuery struct {
+	// Name labels the query in run stats and defaults to Query
+	Name      string   `json:"name,omitempty"`
+	Query     string   `json:"query"`
+	Location  string   `json:"location,omitempty"`
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	// RadiusMiles is sent as additionalFilters.radius
+	RadiusMiles       int            `json:"radiusMiles,omitempty"`
+	IndustryID        string         `json:"industryId,omitempty"`
+	CompanyID         string         `json:"companyId,omitempty"`
+	AdditionalFilters map[string]any `json:"additionalFilters,omitempty"`
+}
+
+func (q ScrapeQuery) Label() string {
+	if name := strings.TrimSpace(q.Name); name != "" {
+		return name
+	}
+	return strings.TrimSpace(q.Query)
+}
+
+// Key identifies the query and its filters. A query without filters keys on
+// its normalized text alone.
+func (q ScrapeQuery) Key() string {
+	parts := []string{strings.ToLower(strings.Join(strings.Fields(q.Query), " "))}
+	if location := strings.TrimSpace(q.Location); location != "" {
+		parts = append(parts, "location="+strings.ToLower(location))
+	}
+	if q.Latitude != nil && q.Longitude != nil {
+		parts = append(parts, "geo="+strconv.FormatFloat(*q.Latitude, 'f', -1, 64)+","+strconv.FormatFloat(*q.Longitude, 'f', -1, 64))
+	}
+	if q.RadiusMiles > 0 {
+		parts = append(parts, "radius="+strconv.Itoa(q.RadiusMiles))
+	}
+	if industry := strings.TrimSpace(q.IndustryID); industry != "" {
+		parts = append(parts, "industry="+industry)
+	}
+	if company := strings.TrimSpace(q.CompanyID); company != "" {
+		parts = append(parts, "company="+company)
+	}
+	if len(q.AdditionalFilters) > 0 {
+		names := make([]string, 0, len(q.AdditionalFilters))
+		for name := range q.AdditionalFilters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parts = append(parts, fmt.Sprintf("%s=%v", name, q.AdditionalFilters[name]))
+		}
+	}
+	return strings.Join(parts, "|")
+}
+
+// ScrapeQueries returns the configured plan, or a single query built from
+// Query when no plan is set.
+func (c Config) ScrapeQueries() []ScrapeQuery {
+	if len(c.ScrapePlan) > 0 {
+		return c.ScrapePlan
+	}
+	query := c.Query
+	if strings.TrimSpace(query) == "" {
+		query = c.DefaultQuery
+	}
+	return []ScrapeQuery{{Query: query}}
+}
+
+// loadScrapePlan reads SCRAPE_PLAN as inline JSON, falling back to the file
+// named by SCRAPE_PLAN_PATH.
+func loadScrapePlan() ([]ScrapeQuery, error) {
+	raw := strings.TrimSpace(os.Getenv("SCRAPE_PLAN"))
+	source := "SCRAPE_PLAN"
+	if raw == "" {
+		path := strings.TrimSpace(os.Getenv("SCRAPE_PLAN_PATH"))
+		if path == "" {
+			return nil, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read SCRAPE_PLAN_PATH: %w", err)
+		}
+		raw = string(data)
+		source = path
+	}
+	return parseScrapePlan(raw, source)
+}
+
+func parseScrapePlan(raw, source string) ([]ScrapeQuery, error) {
+	var plan []ScrapeQuery
+	if err := json.Unmarshal([]byte(raw), &plan); err != nil {
+		return nil, fmt.Errorf("decode scrape plan from %s: %w", source, err)
+	}
+
+	seen := make(map[string]bool, len(plan))
+	queries := make([]ScrapeQuery, 0, len(plan))
+	for i, query := range plan {
+		query.Query = strings.TrimSpace(query.Query)
+		if query.Query == "" {
+			return nil, fmt.Errorf("scrape plan entry %d in %s has no query", i, source)
+		}
+		if (query.Latitude == nil) != (query.Longitude == nil) {
+			return nil, fmt.Errorf("scrape plan entry %q must set both latitude and longitude", query.Label())
+		}
+		if seen[query.Key()] {
+			continue
+		}
+		seen[query.Key()] = true
+		queries = append(queries, query)
+	}
+	return queries, nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseScrapePlan(t *testing.T) {
+	plan, err := parseScrapePlan(`[
+		{"query": "software engineer"},
+		{"name": "SRE Seattle", "query": "site reliability", "location": "Seattle, WA", "latitude": 47.6, "longitude": -122.3, "radiusMiles": 25},
+		{"query": " Software  Engineer "},
+		{"query": "data engineer", "industryId": "54", "companyId": "001A", "additionalFilters": {"jobType": "Full Time"}}
+	]`, "test")
+	if err != nil {
+		t.Fatalf("parseScrapePlan returned error: %v", err)
+	}
+	if len(plan) != 3 {
+		t.Fatalf("expected duplicate query to be dropped, got %d entries", len(plan))
+	}
+	if plan[1].Label() != "SRE Seattle" || plan[2].Label() != "data engineer" {
+		t.Fatalf("unexpected labels: %q, %q", plan[1].Label(), plan[2].Label())
+	}
+	if plan[1].Key() != "site reliability|location=seattle, wa|geo=47.6,-122.3|radius=25" {
+		t.Fatalf("unexpected key: %q", plan[1].Key())
+	}
+	if plan[2].Key() != "data engineer|industry=54|company=001A|jobType=Full Time" {
+		t.Fatalf("unexpected key: %q", plan[2].Key())
+	}
+}
+
+func TestParseScrapePlanRejectsInvalidEntries(t *testing.T) {
+	cases := map[string]string{
+		"badJSON":       `{"query": "x"}`,
+		"missingQuery":  `[{"location": "Seattle"}]`,
+		"halfGeoFilter": `[{"query": "x", "latitude": 47.6}]`,
+	}
+	for name, raw := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parseScrapePlan(raw, "test"); err == nil {
+				t.Fatalf("expected error for %s", raw)
+			}
+		})
+	}
+}
+
+func TestScrapeQueriesFallsBackToQuery(t *testing.T) {
+	cfg := Config{Query: "backend engineer", DefaultQuery: "software engineer"}
+	if got := cfg.ScrapeQueries(); len(got) != 1 || got[0].Query != "backend engineer" {
+		t.Fatalf("expected single query plan, got %+v", got)
+	}
+
+	cfg.ScrapePlan = []ScrapeQuery{{Query: "sre"}, {Query: "data engineer"}}
+	if got := cfg.ScrapeQueries(); len(got) != 2 {
+		t.Fatalf("expected configured plan, got %+v", got)
+	}
+}
+
+func TestLoadReadsScrapePlanFile(t *testing.T) {
+	t.Setenv("AWS_LAMBDA_FUNCTION_NAME", "")
+	t.Setenv("LAMBDA_TASK_ROOT", "")
+	t.Setenv("API_DRY_RUN", "true")
+	t.Setenv("SCRAPE_PLAN", "")
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	if err := os.WriteFile(planPath, []byte(`[{"query":"sre"},{"query":"data engineer"}]`), 0o600); err != nil {
+		t.Fatalf("write plan: %v", err)
+	}
+	t.Setenv("SCRAPE_PLAN_PATH", planPath)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if len(cfg.ScrapePlan) != 2 || cfg.ScrapePlan[1].Query != "data engineer" {
+		t.Fatalf("expected plan from file, got %+v", cfg.ScrapePlan)
+	}
+
+	t.Setenv("SCRAPE_PLAN", `[{"query":""}]`)
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "has no query") {
+		t.Fatalf("expected invalid inline plan error, got %v", err)
+	}
+}
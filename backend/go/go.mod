@@ -15,6 +15,7 @@ require (
 	github.com/gocolly/colly/v2 v2.2.0
 	github.com/invopop/jsonschema v0.13.0
 	github.com/joho/godotenv v1.5.1
+	github.com/mattn/go-sqlite3 v1.14.32
 	github.com/openai/openai-go v1.10.1
 )
 
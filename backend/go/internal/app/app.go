@@ -17,6 +17,7 @@ import (
 	"gopher-source/services"
 	"gopher-source/utils"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
@@ -29,6 +30,24 @@ type RunResult struct {
 	JobsAddedToCache    int
 	JobCacheS3Bucket    string
 	JobCacheS3Key       string
+	QueryStats          []models.QueryStats
+	// ScrapeWatermarksEnabled reports whether searches stopped at stored watermarks
+	ScrapeWatermarksEnabled bool
+	// Interrupted reports that scraping stopped CheckpointReserve before the deadline
+	Interrupted           bool
+	ResumedFromCheckpoint bool
+	CheckpointSaved       bool
+	// JobsEnqueued counts jobs submitted for batch enrichment instead of parsed
+	JobsEnqueued       int
+	EnrichmentBatchIDs []string
+	// DeadLetters counts jobs whose enrichment failed and were queued for retry
+	DeadLetters int
+	// PostingsUpdated counts stored jobs given new posting history, of which
+	// PostingsClosed disappeared from search results
+	PostingsUpdated int
+	PostingsClosed  int
+	// PostingsFailed counts stored jobs whose history update failed
+	PostingsFailed int
 }
 
 // Run executes the shared scraping pipeline used by both local and scraper binaries.
@@ -39,18 +58,31 @@ func Run(ctx context.Context, cfg *config.Config) (*RunResult, error) {
 	}
 
 	// initialize clients
-	awsConfig, err := services.NewDynamoConfig(ctx, cfg.AWSRegion)
-	if err != nil {
-		return nil, fmt.Errorf("load aws config: %w", err)
+	replaying := cfg.HTTPFixtureMode == config.HTTPFixtureReplay
+	var store services.JobStore
+	var awsConfig aws.Config
+	if replaying {
+		utils.Debug(fmt.Sprintf("Replaying HTTP fixtures from %s; the job store and S3 are kept in memory", cfg.HTTPFixtureDir))
+		store = services.NewMemoryStore()
+	} else {
+		var err error
+		if store, awsConfig, err = openJobStore(ctx, cfg); err != nil {
+			return nil, err
+		}
 	}
-	dynamoService := services.NewDynamoService(awsConfig, cfg.DynamoTableName, cfg.DynamoEndpoint)
 
 	var s3Service services.S3Client
+	if cfg.UseS3JobIDFile && cfg.JobIDsBucket != "" && !replaying {
+		s3Service = services.NewS3Service(awsConfig)
+	}
+	var archive services.DescriptionArchive
+	if !replaying && cfg.ApiDryRun != "true" {
+		archive = newDescriptionArchive(cfg, awsConfig, s3Service)
+	}
 	if cfg.UseJobIDFile && cfg.UseS3JobIDFile {
-		if cfg.JobIDsBucket != "" && cfg.JobIDsS3Key != "" {
-			s3Service = services.NewS3Service(awsConfig)
+		if s3Service != nil && cfg.JobIDsS3Key != "" {
 			utils.Debug(fmt.Sprintf("Downloading job ID cache from s3://%s/%s", cfg.JobIDsBucket, cfg.JobIDsS3Key))
-			if err := downloadJobIDCache(ctx, s3Service, cfg.JobIDsBucket, cfg.JobIDsS3Key, cfg.Filename); err != nil {
+			if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.JobIDsS3Key, cfg.Filename, "job ID cache"); err != nil {
 				return nil, fmt.Errorf("sync job id cache: %w", err)
 			}
 		} else {
@@ -74,38 +106,149 @@ func Run(ctx context.Context, cfg *config.Config) (*RunResult, error) {
 		utils.Debug("Skipping job ID file cache for this run")
 	}
 
-	openaiService := services.NewOpenAIService()
-	parser := services.NewParserService(openaiService)
-	scraper := services.NewScraperWithKeyset(*cfg, true, keySet)
+	llmClient, err := services.NewLLMClient(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure LLM provider: %w", err)
+	}
+	stats := &models.JobStats{}
+	parser := services.NewParserService(llmClient, stats)
+	var enrichmentCache services.EnrichmentCache
+	batchMode := cfg.EnrichmentMode == config.EnrichmentModeBatch
+	if cfg.UseEnrichmentCache && cfg.ApiDryRun != "true" && !batchMode {
+		enrichmentCache, err = loadEnrichmentCache(ctx, cfg, s3Service)
+		if err != nil {
+			return nil, fmt.Errorf("load enrichment cache: %w", err)
+		}
+		parser = services.NewCachedParserService(llmClient, enrichmentCache, stats)
+		utils.Debug(fmt.Sprintf("Loaded %d cached enrichment response(s)", len(enrichmentCache.Entries())))
+	}
+	sources, err := services.NewJobSources(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure job sources: %w", err)
+	}
+	scraper := services.NewScraperWithSources(*cfg, true, keySet, sources)
+	if cfg.JobWriteMode == config.JobWriteUpsert && cfg.ApiDryRun != "true" {
+		scraper.SkipUnchangedPostings(store)
+	}
+	if cfg.UseScrapeWatermarks {
+		watermarks, err := loadScrapeWatermarks(ctx, cfg, s3Service)
+		if err != nil {
+			return nil, fmt.Errorf("load scrape watermarks: %w", err)
+		}
+		scraper.SetWatermarks(watermarks)
+		result.ScrapeWatermarksEnabled = true
+		utils.Debug(fmt.Sprintf("Loaded %d scrape watermark(s)", len(watermarks)))
+	}
+
+	if cfg.UseScrapeCheckpoints {
+		checkpoint, err := loadScrapeCheckpoint(ctx, cfg, s3Service)
+		if err != nil {
+			return nil, fmt.Errorf("load scrape checkpoint: %w", err)
+		}
+		if !checkpoint.IsEmpty() {
+			scraper.ResumeFrom(checkpoint)
+			result.ResumedFromCheckpoint = true
+			utils.Debug(fmt.Sprintf("Resuming checkpoint from %s: %d pending job(s), %d cursor(s), %d completed query(s)",
+				checkpoint.CreatedAt, len(checkpoint.PendingJobs), len(checkpoint.Cursors), len(checkpoint.CompletedQueries)))
+		}
+	}
+
+	// scraping stops early enough to drain in-flight jobs before the deadline
+	scrapeCtx, cancelScrape := withDrainDeadline(ctx, cfg.CheckpointReserve)
+	defer cancelScrape()
 
 	jobsChan := make(chan models.Job)
 	var processingWg sync.WaitGroup
 	processingWg.Add(1)
-	stats := &models.JobStats{}
+	var unprocessed, enqueued []models.Job
+	var failures []models.DeadLetter
 
 	go func() {
 		defer processingWg.Done()
-		processAndSendJobs(ctx, jobsChan, stats, *cfg, parser, dynamoService)
+		if batchMode {
+			for job := range jobsChan {
+				enqueued = append(enqueued, job)
+			}
+			return
+		}
+		unprocessed, failures = processAndSendJobs(ctx, scrapeCtx, jobsChan, stats, *cfg, parser, store, archive)
 	}()
 
 	// scrape
-	utils.Debug(fmt.Sprintf("🚀 Starting job scraping for '%s' with max pages set to %d", cfg.DefaultQuery, cfg.MaxPages))
-	scrapeErr := scraper.ScrapeJobs(ctx, cfg.DefaultQuery, jobsChan, stats)
+	plan := cfg.ScrapeQueries()
+	utils.Debug(fmt.Sprintf("🚀 Starting job scraping for %d query(s) across %d source(s) with max pages set to %d", len(plan), len(sources), cfg.MaxPages))
+	scrapeErr := scraper.ScrapeJobs(scrapeCtx, plan, jobsChan, stats)
 	processingWg.Wait()
 
+	result.Interrupted = scrapeCtx.Err() != nil
+	if result.Interrupted {
+		log.Printf("Stopped scraping ahead of the deadline; %d received job(s) left unprocessed", len(unprocessed))
+	}
+
+	if len(enqueued) > 0 {
+		if cfg.ApiDryRun == "true" {
+			utils.Debug(fmt.Sprintf("API_DRY_RUN enabled; skipping batch enrichment of %d job(s)", len(enqueued)))
+		} else {
+			for i := range enqueued {
+				archiveDescription(ctx, archive, &enqueued[i])
+			}
+			// the job ID cache is only saved once the jobs are in a batch
+			batches, err := enqueueEnrichmentBatches(ctx, cfg, s3Service, enqueued)
+			for _, batch := range batches {
+				result.JobsEnqueued += len(batch.Jobs)
+				result.EnrichmentBatchIDs = append(result.EnrichmentBatchIDs, batch.ID)
+			}
+			if err != nil {
+				return result, err
+			}
+			utils.Debug(fmt.Sprintf("Submitted %d job(s) in %d enrichment batch(es)", result.JobsEnqueued, len(batches)))
+		}
+	}
+
+	if len(failures) > 0 && cfg.UseDeadLetters {
+		if cfg.ApiDryRun == "true" {
+			utils.Debug(fmt.Sprintf("API_DRY_RUN enabled; skipping dead letters for %d job(s)", len(failures)))
+		} else {
+			// like batches, failures are queued before the job ID cache hides them
+			if err := recordDeadLetters(ctx, cfg, s3Service, failures); err != nil {
+				return result, err
+			}
+			result.DeadLetters = len(failures)
+			utils.Debug(fmt.Sprintf("Queued %d failed job(s) for retry", len(failures)))
+		}
+	}
+
+	if cfg.UsePostingHistory {
+		if cfg.ApiDryRun == "true" {
+			utils.Debug("API_DRY_RUN enabled; skipping posting history")
+		} else {
+			history, err := recordPostingHistory(ctx, cfg, s3Service, store, scraper.GetSearchCoverage(), time.Now())
+			if err != nil {
+				return result, err
+			}
+			result.PostingsUpdated = history.updated
+			result.PostingsClosed = history.closed
+			result.PostingsFailed = history.failed
+		}
+	}
+
 	if cfg.UseJobIDFile {
 		keySet = scraper.GetProcessedIDs()
+		// unprocessed jobs stay out of the cache so they are parsed once resumed
+		for _, job := range unprocessed {
+			delete(keySet, services.JobCacheKey(cfg.JobWriteMode, &job))
+		}
 		if cfg.ApiDryRun == "true" {
 			result.JobCacheFinalSize = keySetInitialSize
 			result.JobsAddedToCache = 0
 			utils.Debug("API_DRY_RUN enabled; skipping job ID cache upload")
 		} else {
-			if err := dynamoService.WriteJobIdsToFile(cfg.Filename, keySet); err != nil {
+			if err := services.WriteJobIDsFile(cfg.Filename, keySet); err != nil {
 				return nil, fmt.Errorf("write job ids: %w", err)
 			}
 			if cfg.UseS3JobIDFile && s3Service != nil {
 				utils.Debug(fmt.Sprintf("Uploading %d job IDs to s3://%s/%s", len(keySet), cfg.JobIDsBucket, cfg.JobIDsS3Key))
-				if err := uploadJobIDCache(ctx, s3Service, cfg.JobIDsBucket, cfg.JobIDsS3Key, cfg.Filename); err != nil {
+				if err := uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.JobIDsS3Key, cfg.Filename, "job ID cache"); err != nil {
 					return nil, err
 				}
 				result.JobCacheS3Bucket = cfg.JobIDsBucket
@@ -119,26 +262,123 @@ func Run(ctx context.Context, cfg *config.Config) (*RunResult, error) {
 		}
 	}
 
+	if cfg.UseScrapeWatermarks {
+		if cfg.ApiDryRun == "true" {
+			utils.Debug("API_DRY_RUN enabled; skipping scrape watermark save")
+		} else if err := saveScrapeWatermarks(ctx, cfg, s3Service, scraper.GetWatermarks()); err != nil {
+			return nil, fmt.Errorf("save scrape watermarks: %w", err)
+		}
+	}
+
+	if enrichmentCache != nil {
+		if err := saveEnrichmentCache(ctx, cfg, s3Service, enrichmentCache); err != nil {
+			return nil, fmt.Errorf("save enrichment cache: %w", err)
+		}
+	}
+
+	if cfg.ApiDryRun == "true" {
+		utils.Debug("API_DRY_RUN enabled; skipping skill review save")
+	} else if err := recordUnknownSkills(ctx, cfg, s3Service, stats.Snapshot().UnknownSkills); err != nil {
+		return nil, err
+	}
+
+	if cfg.UseScrapeCheckpoints {
+		checkpoint := scraper.Checkpoint()
+		if result.Interrupted {
+			checkpoint.PendingJobs = append(checkpoint.PendingJobs, unprocessed...)
+		} else {
+			// a finished run only carries the cursors of searches MaxPages cut short
+			checkpoint = models.ScrapeCheckpoint{Cursors: checkpoint.Cursors}
+		}
+		// an empty checkpoint is only saved to clear the one this run resumed
+		if result.Interrupted || result.ResumedFromCheckpoint || !checkpoint.IsEmpty() {
+			if cfg.ApiDryRun == "true" {
+				utils.Debug("API_DRY_RUN enabled; skipping scrape checkpoint save")
+			} else if err := saveScrapeCheckpoint(ctx, cfg, s3Service, checkpoint); err != nil {
+				return nil, fmt.Errorf("save scrape checkpoint: %w", err)
+			} else {
+				result.CheckpointSaved = !checkpoint.IsEmpty()
+			}
+		}
+	}
+
 	executionTime := time.Since(startTime)
 	// print stats
 	stats.PrintSummary(executionTime)
+	result.QueryStats = scraper.GetQueryStats()
+	models.PrintQueryStats(result.QueryStats)
 	result.ExecutionTime = executionTime
 	result.Stats = stats.Snapshot()
 
 	if scrapeErr != nil {
+		// a run stopped at the drain deadline resumes from its checkpoint
+		if result.Interrupted {
+			log.Printf("Scrape interrupted: %v", scrapeErr)
+			return result, nil
+		}
 		return result, fmt.Errorf("scrape jobs: %w", scrapeErr)
 	}
 	return result, nil
 }
 
-func processAndSendJobs(ctx context.Context, jobsChan <-chan models.Job, stats *models.JobStats, cfg config.Config,
-	parser services.ParserClient, dynamoService services.DynamoDBClient) {
+// openJobStore opens the configured job store. The AWS config is only loaded
+// when DynamoDB or S3 is in use, so local runs on SQLite or memory need no
+// AWS setup.
+func openJobStore(ctx context.Context, cfg *config.Config) (services.JobStore, aws.Config, error) {
+	var awsConfig aws.Config
+	usesS3 := (cfg.UseS3JobIDFile && cfg.JobIDsBucket != "") || (cfg.UseDescriptionArchive && cfg.DescriptionBucket != "")
+	if cfg.JobStore == config.JobStoreDynamoDB || usesS3 {
+		loaded, err := services.NewDynamoConfig(ctx, cfg.AWSRegion)
+		if err != nil {
+			return nil, aws.Config{}, fmt.Errorf("load aws config: %w", err)
+		}
+		awsConfig = loaded
+	}
+	store, err := services.NewJobStore(*cfg, awsConfig)
+	if err != nil {
+		return nil, aws.Config{}, fmt.Errorf("open job store: %w", err)
+	}
+	return store, awsConfig, nil
+}
+
+// processAndSendJobs parses and stores jobs until jobsChan closes. Jobs that
+// only get a worker after drainCtx ends are returned unprocessed; jobs already
+// being parsed finish under ctx. Jobs whose enrichment or write failed are
+// returned as dead letters. A nil archive skips archiving descriptions.
+// Inserts go through a batching JobWriter unless batch writes are off.
+func processAndSendJobs(ctx, drainCtx context.Context, jobsChan <-chan models.Job, stats *models.JobStats, cfg config.Config,
+	parser services.ParserClient, store services.JobStore, archive services.DescriptionArchive) ([]models.Job, []models.DeadLetter) {
 	sem := make(chan struct{}, cfg.MaxConcurrency)
 	var wg sync.WaitGroup
+	var unprocessed []models.Job
+	var failuresMu sync.Mutex
+	var failures []models.DeadLetter
+
+	var writes chan models.Job
+	var failedBatches []services.FailedBatch
+	writerDone := make(chan struct{})
+	// jobs handed to the writer, as scraped, in case their batch fails
+	type pendingWrite struct {
+		submitted models.Job
+		enriched  bool
+	}
+	pending := make(map[string]pendingWrite)
+	if cfg.UseBatchWrites && cfg.JobWriteMode != config.JobWriteUpsert {
+		writes = make(chan models.Job, cfg.MaxConcurrency)
+		go func() {
+			defer close(writerDone)
+			failedBatches = services.NewJobWriter(store, stats).Run(ctx, writes)
+		}()
+	}
 
 	for job := range jobsChan {
-		wg.Add(1)
 		sem <- struct{}{}
+		if drainCtx.Err() != nil {
+			<-sem
+			unprocessed = append(unprocessed, job)
+			continue
+		}
+		wg.Add(1)
 
 		go func(job models.Job) {
 			defer wg.Done()
@@ -150,22 +390,34 @@ func processAndSendJobs(ctx context.Context, jobsChan <-chan models.Job, stats *
 				return
 			}
 
-			enhancedJob, success := parser.ParseWithStats(ctx, &job)
-			if enhancedJob == nil {
+			archiveDescription(ctx, archive, &job)
+			enhancedJob, err := parser.ParseWithStats(ctx, &job)
+			if err != nil {
 				atomic.AddInt64(&stats.FailedJobs, 1)
-				return
-			}
-
-			if success {
-				atomic.AddInt64(&stats.SuccessfulJobs, 1)
+				failuresMu.Lock()
+				failures = append(failures, newDeadLetter(job, err, time.Now()))
+				failuresMu.Unlock()
 			} else {
-				atomic.AddInt64(&stats.FailedJobs, 1)
+				atomic.AddInt64(&stats.SuccessfulJobs, 1)
+			}
+			if enhancedJob == nil {
+				return
 			}
 			if !enhancedJob.IsSoftwareEngineerRelated {
 				atomic.AddInt64(&stats.UnrelatedJobs, 1)
 			}
-			if err := dynamoService.PutJob(ctx, enhancedJob); err != nil {
-				log.Printf("Failed to put job to DynamoDB: %v", err)
+			if writes != nil {
+				failuresMu.Lock()
+				pending[job.JobId] = pendingWrite{submitted: job, enriched: err == nil}
+				failuresMu.Unlock()
+				writes <- *enhancedJob
+			} else if writeErr := writeJob(ctx, store, cfg.JobWriteMode, enhancedJob); writeErr != nil {
+				log.Printf("Failed to put job to DynamoDB: %v", writeErr)
+				if letter, ok := storeFailure(job, err == nil, writeErr, stats, time.Now()); ok {
+					failuresMu.Lock()
+					failures = append(failures, letter)
+					failuresMu.Unlock()
+				}
 			}
 			if cfg.ApiDryRun == "true" {
 				mockPost(*enhancedJob)
@@ -173,6 +425,30 @@ func processAndSendJobs(ctx context.Context, jobsChan <-chan models.Job, stats *
 		}(job)
 	}
 	wg.Wait()
+	if writes != nil {
+		close(writes)
+		<-writerDone
+		now := time.Now()
+		for _, batch := range failedBatches {
+			for _, job := range batch.Jobs {
+				write := pending[job.JobId]
+				if letter, ok := storeFailure(write.submitted, write.enriched, batch.Err, stats, now); ok {
+					failures = append(failures, letter)
+				}
+			}
+		}
+	}
+	return unprocessed, failures
+}
+
+// writeJob stores a freshly parsed job, replacing a stored one whose posting
+// changed when mode is upsert.
+func writeJob(ctx context.Context, store services.JobStore, mode string, job *models.Job) error {
+	if mode != config.JobWriteUpsert {
+		return store.PutJob(ctx, job)
+	}
+	_, err := store.UpsertJob(ctx, job)
+	return err
 }
 
 func mockPost(job models.Job) {
@@ -227,23 +503,23 @@ func readKeysFromFile(filename string) (map[string]bool, error) {
 	return keySet, nil
 }
 
-func downloadJobIDCache(ctx context.Context, s3Client services.S3Client, bucket, key, filename string) error {
+func downloadCacheFile(ctx context.Context, s3Client services.S3Client, bucket, key, filename, label string) error {
 	if err := s3Client.DownloadFile(ctx, bucket, key, filename); err != nil {
 		var noKey *s3types.NoSuchKey
 		if errors.As(err, &noKey) {
-			utils.Debug(fmt.Sprintf("No existing job ID cache at s3://%s/%s; starting fresh", bucket, key))
+			utils.Debug(fmt.Sprintf("No existing %s at s3://%s/%s; starting fresh", label, bucket, key))
 			return nil
 		}
-		return fmt.Errorf("download job id cache: %w", err)
+		return fmt.Errorf("download %s: %w", label, err)
 	}
-	utils.Debug(fmt.Sprintf("Downloaded job ID cache from s3://%s/%s", bucket, key))
+	utils.Debug(fmt.Sprintf("Downloaded %s from s3://%s/%s", label, bucket, key))
 	return nil
 }
 
-func uploadJobIDCache(ctx context.Context, s3Client services.S3Client, bucket, key, filename string) error {
+func uploadCacheFile(ctx context.Context, s3Client services.S3Client, bucket, key, filename, label string) error {
 	if err := s3Client.UploadFile(ctx, bucket, key, filename); err != nil {
-		return fmt.Errorf("upload job id cache: %w", err)
+		return fmt.Errorf("upload %s: %w", label, err)
 	}
-	utils.Debug(fmt.Sprintf("Uploaded job ID cache to s3://%s/%s", bucket, key))
+	utils.Debug(fmt.Sprintf("Uploaded %s to s3://%s/%s", label, bucket, key))
 	return nil
 }
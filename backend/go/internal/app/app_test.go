@@ -2,34 +2,45 @@ package app
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"gopher-source/config"
 	"gopher-source/models"
+	"gopher-source/services"
 )
 
 type fakeParser struct {
 	mu        sync.Mutex
 	responses []*models.Job
-	successes []bool
+	errs      []error
 	callCount int
 }
 
-func (f *fakeParser) ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, bool) {
+func (f *fakeParser) ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
 	if f.callCount >= len(f.responses) {
-		return nil, false
+		return nil, errors.New("no response")
 	}
 	idx := f.callCount
 	f.callCount++
-	return f.responses[idx], f.successes[idx]
+	return f.responses[idx], f.errs[idx]
 }
 
 type fakeDynamo struct {
-	mu   sync.Mutex
-	jobs []*models.Job
+	mu      sync.Mutex
+	jobs    []*models.Job
+	updates []*models.Job
+	upserts []*models.Job
+	batches int
+	// putErr fails every PutJobs call
+	putErr error
 }
 
 func (f *fakeDynamo) PutJob(ctx context.Context, job *models.Job) error {
@@ -40,20 +51,66 @@ func (f *fakeDynamo) PutJob(ctx context.Context, job *models.Job) error {
 	return nil
 }
 
+func (f *fakeDynamo) PutJobs(ctx context.Context, jobs []models.Job) (services.WriteResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.putErr != nil {
+		return services.WriteResult{Calls: 1}, f.putErr
+	}
+	for i := range jobs {
+		copyJob := jobs[i]
+		f.jobs = append(f.jobs, &copyJob)
+	}
+	f.batches++
+	return services.WriteResult{Stored: len(jobs), Calls: 1}, nil
+}
+
+func (f *fakeDynamo) UpsertJob(ctx context.Context, job *models.Job) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copyJob := *job
+	f.upserts = append(f.upserts, &copyJob)
+	return true, nil
+}
+
+func (f *fakeDynamo) UpdateJobEnrichment(ctx context.Context, job *models.Job) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copyJob := *job
+	f.updates = append(f.updates, &copyJob)
+	return nil
+}
+
+func (f *fakeDynamo) UpdateJobHistory(ctx context.Context, job *models.Job) error {
+	return nil
+}
+
 func (f *fakeDynamo) QueryJobsByPostedDate(ctx context.Context, date string) ([]models.Job, error) {
-	return nil, nil
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var jobs []models.Job
+	for _, job := range f.jobs {
+		if job.PostedDate == date {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
 }
 
 func (f *fakeDynamo) QueryJobsByDateRange(ctx context.Context, startDate, endDate string) ([]models.Job, error) {
 	return nil, nil
 }
 
+func (f *fakeDynamo) QueryJobs(ctx context.Context, filter services.JobFilter) ([]models.Job, error) {
+	return nil, nil
+}
+
 func (f *fakeDynamo) GetAllJobIds(ctx context.Context) (map[string]bool, error) {
 	return map[string]bool{}, nil
 }
 
-func (f *fakeDynamo) WriteJobIdsToFile(filename string, keySet map[string]bool) error {
-	return nil
+func (f *fakeDynamo) SourceHashes(ctx context.Context, jobs []models.Job) ([]string, error) {
+	return make([]string, len(jobs)), nil
 }
 
 func TestProcessAndSendJobsParsesAndStoresJobs(t *testing.T) {
@@ -67,7 +124,7 @@ func TestProcessAndSendJobsParsesAndStoresJobs(t *testing.T) {
 			{JobId: "1", Title: "One", IsSoftwareEngineerRelated: true},
 			{JobId: "2", Title: "Two", IsSoftwareEngineerRelated: false},
 		},
-		successes: []bool{true, true},
+		errs: []error{nil, nil},
 	}
 	dynamo := &fakeDynamo{}
 	stats := &models.JobStats{}
@@ -77,7 +134,7 @@ func TestProcessAndSendJobsParsesAndStoresJobs(t *testing.T) {
 		ApiDryRun:      "false",
 	}
 
-	processAndSendJobs(context.Background(), jobsChan, stats, cfg, parser, dynamo)
+	processAndSendJobs(context.Background(), context.Background(), jobsChan, stats, cfg, parser, dynamo, nil)
 
 	snapshot := stats.Snapshot()
 	if snapshot.ProcessedJobs != 2 {
@@ -94,6 +151,134 @@ func TestProcessAndSendJobsParsesAndStoresJobs(t *testing.T) {
 	}
 }
 
+func TestProcessAndSendJobsUpsertsInUpsertMode(t *testing.T) {
+	jobsChan := make(chan models.Job, 1)
+	jobsChan <- models.Job{JobId: "1", Title: "One"}
+	close(jobsChan)
+
+	parser := &fakeParser{
+		responses: []*models.Job{{JobId: "1", Title: "One", IsSoftwareEngineerRelated: true}},
+		errs:      []error{nil},
+	}
+	dynamo := &fakeDynamo{}
+	cfg := config.Config{
+		MaxConcurrency: 1,
+		ApiDryRun:      "false",
+		JobWriteMode:   config.JobWriteUpsert,
+	}
+
+	processAndSendJobs(context.Background(), context.Background(), jobsChan, &models.JobStats{}, cfg, parser, dynamo, nil)
+
+	if len(dynamo.upserts) != 1 || len(dynamo.jobs) != 0 {
+		t.Fatalf("expected the job to be upserted rather than put, got %d upserts and %d puts", len(dynamo.upserts), len(dynamo.jobs))
+	}
+}
+
+func TestProcessAndSendJobsBatchesWrites(t *testing.T) {
+	jobsChan := make(chan models.Job, 3)
+	for _, id := range []string{"1", "2", "3"} {
+		jobsChan <- models.Job{JobId: id, Title: "Job " + id}
+	}
+	close(jobsChan)
+
+	parser := &fakeParser{
+		responses: []*models.Job{
+			{JobId: "1", Title: "Job 1", IsSoftwareEngineerRelated: true},
+			{JobId: "2", Title: "Job 2", IsSoftwareEngineerRelated: true},
+			{JobId: "3", Title: "Job 3", IsSoftwareEngineerRelated: true},
+		},
+		errs: []error{nil, nil, nil},
+	}
+	dynamo := &fakeDynamo{}
+	stats := &models.JobStats{}
+	cfg := config.Config{
+		MaxConcurrency: 2,
+		ApiDryRun:      "false",
+		UseBatchWrites: true,
+	}
+
+	processAndSendJobs(context.Background(), context.Background(), jobsChan, stats, cfg, parser, dynamo, nil)
+
+	if len(dynamo.jobs) != 3 || dynamo.batches != 1 {
+		t.Fatalf("expected 3 jobs stored in one batch, got %d jobs in %d batches", len(dynamo.jobs), dynamo.batches)
+	}
+	if snapshot := stats.Snapshot(); snapshot.StoredJobs != 3 || snapshot.StoreCalls != 1 {
+		t.Fatalf("expected the batch to be recorded in stats, got %+v", snapshot)
+	}
+}
+
+func TestProcessAndSendJobsDeadLettersFailedBatches(t *testing.T) {
+	jobsChan := make(chan models.Job, 2)
+	jobsChan <- models.Job{JobId: "1", Title: "Job 1", Description: "raw 1"}
+	jobsChan <- models.Job{JobId: "2", Title: "Job 2", Description: "raw 2"}
+	close(jobsChan)
+
+	parser := &fakeParser{
+		responses: []*models.Job{
+			{JobId: "1", Title: "Job 1", IsSoftwareEngineerRelated: true},
+			{JobId: "2", Title: "Job 2", IsSoftwareEngineerRelated: true},
+		},
+		errs: []error{nil, nil},
+	}
+	dynamo := &fakeDynamo{putErr: errors.New("AccessDeniedException")}
+	stats := &models.JobStats{}
+	cfg := config.Config{
+		MaxConcurrency: 2,
+		ApiDryRun:      "false",
+		UseBatchWrites: true,
+	}
+
+	_, failures := processAndSendJobs(context.Background(), context.Background(), jobsChan, stats, cfg, parser, dynamo, nil)
+
+	if len(failures) != 2 {
+		t.Fatalf("expected both jobs dead-lettered, got %+v", failures)
+	}
+	for _, letter := range failures {
+		if letter.Job.Description == "" || !strings.Contains(letter.Error, "AccessDeniedException") {
+			t.Fatalf("expected the scraped job and the write error, got %+v", letter)
+		}
+	}
+	snapshot := stats.Snapshot()
+	if snapshot.StoreFailures != 2 || snapshot.FailedJobs != 2 || snapshot.SuccessfulJobs != 0 {
+		t.Fatalf("expected the jobs counted as failed, got %+v", snapshot)
+	}
+}
+
+func TestReplaceFallbackRewritesStoredJob(t *testing.T) {
+	ctx := context.Background()
+	store := services.NewMemoryStore()
+	fallback := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-16", Domain: "Other", LowConfidence: true}
+	if err := store.PutJob(ctx, &fallback); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+
+	parsed := fallback
+	parsed.Domain = "Backend"
+	parsed.LowConfidence = false
+	if err := replaceFallback(ctx, store, &parsed); err != nil {
+		t.Fatalf("replaceFallback returned error: %v", err)
+	}
+	fresh := models.Job{JobId: "2", Title: "SRE", PostedDate: "2026-10-16", Domain: "DevOps"}
+	if err := replaceFallback(ctx, store, &fresh); err != nil {
+		t.Fatalf("replaceFallback returned error for an unstored job: %v", err)
+	}
+
+	jobs, err := store.QueryJobsByPostedDate(ctx, "2026-10-16")
+	if err != nil {
+		t.Fatalf("QueryJobsByPostedDate returned error: %v", err)
+	}
+	domains := make(map[string]string)
+	for _, job := range jobs {
+		if job.LowConfidence {
+			t.Fatalf("expected the fallback record to be replaced, got %+v", job)
+		}
+		domains[job.JobId] = job.Domain
+	}
+	if domains["1"] != "Backend" || domains["2"] != "DevOps" {
+		t.Fatalf("expected both jobs stored with their parsed domain, got %v", domains)
+	}
+}
+
 func TestProcessAndSendJobsHandlesParserFailure(t *testing.T) {
 	jobsChan := make(chan models.Job, 1)
 	jobsChan <- models.Job{JobId: "err", Title: "Err"}
@@ -101,7 +286,7 @@ func TestProcessAndSendJobsHandlesParserFailure(t *testing.T) {
 
 	parser := &fakeParser{
 		responses: []*models.Job{nil},
-		successes: []bool{false},
+		errs:      []error{errors.New("llm unavailable")},
 	}
 	dynamo := &fakeDynamo{}
 	stats := &models.JobStats{}
@@ -111,7 +296,7 @@ func TestProcessAndSendJobsHandlesParserFailure(t *testing.T) {
 		ApiDryRun:      "false",
 	}
 
-	processAndSendJobs(context.Background(), jobsChan, stats, cfg, parser, dynamo)
+	_, failures := processAndSendJobs(context.Background(), context.Background(), jobsChan, stats, cfg, parser, dynamo, nil)
 
 	snapshot := stats.Snapshot()
 	if snapshot.FailedJobs != 1 {
@@ -120,4 +305,615 @@ func TestProcessAndSendJobsHandlesParserFailure(t *testing.T) {
 	if len(dynamo.jobs) != 0 {
 		t.Fatalf("expected no jobs persisted when parser fails, got %d", len(dynamo.jobs))
 	}
+	if len(failures) != 1 || failures[0].Job.JobId != "err" || failures[0].Error != "llm unavailable" || failures[0].Attempts != 1 {
+		t.Fatalf("expected the failure as a dead letter, got %+v", failures)
+	}
+}
+
+func TestProcessAndSendJobsReturnsJobsReceivedAfterDrain(t *testing.T) {
+	jobsChan := make(chan models.Job, 2)
+	jobsChan <- models.Job{JobId: "late-1"}
+	jobsChan <- models.Job{JobId: "late-2"}
+	close(jobsChan)
+
+	drainCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	parser := &fakeParser{}
+	dynamo := &fakeDynamo{}
+	stats := &models.JobStats{}
+
+	unprocessed, _ := processAndSendJobs(context.Background(), drainCtx, jobsChan, stats, config.Config{MaxConcurrency: 1, ApiDryRun: "false"}, parser, dynamo, nil)
+
+	if len(unprocessed) != 2 || unprocessed[0].JobId != "late-1" {
+		t.Fatalf("expected both jobs back unprocessed, got %+v", unprocessed)
+	}
+	if parser.callCount != 0 || len(dynamo.jobs) != 0 || stats.Snapshot().ProcessedJobs != 0 {
+		t.Fatalf("expected no parsing after the drain deadline")
+	}
+}
+
+func TestWithDrainDeadlineReservesTimeBeforeDeadline(t *testing.T) {
+	deadline := time.Now().Add(10 * time.Minute)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	drainCtx, cancelDrain := withDrainDeadline(ctx, 90*time.Second)
+	defer cancelDrain()
+	got, ok := drainCtx.Deadline()
+	if !ok || !got.Equal(deadline.Add(-90*time.Second)) {
+		t.Fatalf("expected drain deadline 90s early, got %v (set=%t)", got, ok)
+	}
+
+	localCtx, cancelLocal := withDrainDeadline(context.Background(), 90*time.Second)
+	defer cancelLocal()
+	if _, ok := localCtx.Deadline(); ok {
+		t.Fatal("expected no drain deadline without an invocation deadline")
+	}
+}
+
+func TestCheckpointFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "scrape-checkpoint.json")
+
+	empty, err := readCheckpointFile(filename)
+	if err != nil {
+		t.Fatalf("readCheckpointFile on missing file returned error: %v", err)
+	}
+	if !empty.IsEmpty() {
+		t.Fatalf("expected empty checkpoint, got %+v", empty)
+	}
+
+	want := models.ScrapeCheckpoint{
+		CompletedQueries: []string{"software engineer"},
+		Cursors: map[string]models.ScrapeCursor{
+			"worksourcewa|sre": {Newest: models.ScrapeWatermark{LastRecordID: "record-1"}, State: []byte(`{"lastRecordId":"record-2"}`)},
+		},
+		PendingJobs: []models.Job{{JobId: "pending-1", Title: "Pending"}},
+	}
+	if err := writeCheckpointFile(filename, want); err != nil {
+		t.Fatalf("writeCheckpointFile returned error: %v", err)
+	}
+	got, err := readCheckpointFile(filename)
+	if err != nil {
+		t.Fatalf("readCheckpointFile returned error: %v", err)
+	}
+	cursor := got.Cursors["worksourcewa|sre"]
+	if len(got.PendingJobs) != 1 || got.PendingJobs[0].Title != "Pending" || string(cursor.State) != `{"lastRecordId":"record-2"}` || cursor.Newest.LastRecordID != "record-1" {
+		t.Fatalf("checkpoint mismatch: got %+v", got)
+	}
+
+	if err := writeCheckpointFile(filename, models.ScrapeCheckpoint{}); err != nil {
+		t.Fatalf("clearing checkpoint returned error: %v", err)
+	}
+	if cleared, err := readCheckpointFile(filename); err != nil || !cleared.IsEmpty() {
+		t.Fatalf("expected cleared checkpoint, got %+v (%v)", cleared, err)
+	}
+}
+
+func TestWatermarksFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "scrape-watermarks.json")
+
+	empty, err := readWatermarksFile(filename)
+	if err != nil {
+		t.Fatalf("readWatermarksFile on missing file returned error: %v", err)
+	}
+	if empty == nil || len(empty) != 0 {
+		t.Fatalf("expected empty non-nil watermarks, got %+v", empty)
+	}
+
+	want := map[string]models.ScrapeWatermark{
+		"worksourcewa|software engineer": {LastPostingDate: "2026-08-18T01:40:56.000Z", LastRecordID: "record-123", UpdatedAt: "2026-08-18T02:00:00Z"},
+	}
+	if err := writeWatermarksFile(filename, want); err != nil {
+		t.Fatalf("writeWatermarksFile returned error: %v", err)
+	}
+	got, err := readWatermarksFile(filename)
+	if err != nil {
+		t.Fatalf("readWatermarksFile returned error: %v", err)
+	}
+	if len(got) != 1 || got["worksourcewa|software engineer"] != want["worksourcewa|software engineer"] {
+		t.Fatalf("watermarks mismatch: got %+v want %+v", got, want)
+	}
+}
+
+func TestEnrichmentCacheFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "enrichment-cache.json")
+
+	empty, err := readEnrichmentCacheFile(filename)
+	if err != nil {
+		t.Fatalf("readEnrichmentCacheFile on missing file returned error: %v", err)
+	}
+	if empty == nil || len(empty) != 0 {
+		t.Fatalf("expected empty non-nil cache, got %+v", empty)
+	}
+
+	scope := services.EnrichmentCacheScope(config.Config{})
+	key := services.EnrichmentCacheKey(scope, "Backend Engineer", "Build Go services.")
+	want := map[string]services.EnrichmentCacheEntry{
+		key: {
+			Response: models.OpenAIJobParsingResponse{ParsedDescription: "Build Go services", Domain: "Backend", Languages: []string{"Go"}, IsSoftwareEngineerRelated: true},
+			UsedAt:   time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+		},
+	}
+	if err := writeEnrichmentCacheFile(filename, want); err != nil {
+		t.Fatalf("writeEnrichmentCacheFile returned error: %v", err)
+	}
+	got, err := readEnrichmentCacheFile(filename)
+	if err != nil {
+		t.Fatalf("readEnrichmentCacheFile returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("enrichment cache mismatch: got %+v want %+v", got, want)
+	}
+	if cached, ok := services.NewEnrichmentCache(scope, got).Get(key); !ok || cached.Response.Domain != "Backend" {
+		t.Fatalf("expected loaded entry to be served from cache, got %+v (%v)", cached, ok)
+	}
+}
+
+func TestStoreBatchResultsCountsAndPersists(t *testing.T) {
+	dynamo := &fakeDynamo{}
+	stats := &models.JobStats{}
+	results := []services.BatchJobResult{
+		{JobID: "1", Job: &models.Job{JobId: "1", IsSoftwareEngineerRelated: true}},
+		{JobID: "2", Job: &models.Job{JobId: "2"}},
+		{JobID: "3", Err: errors.New("no result in batch")},
+		{JobID: "4", Err: errors.New("batch expired"), Job: &models.Job{JobId: "4", LowConfidence: true, IsSoftwareEngineerRelated: true}},
+	}
+
+	submitted := []models.Job{{JobId: "3", Description: "Build Go services."}, {JobId: "4", Description: "Run Kubernetes."}}
+	failures := storeBatchResults(context.Background(), submitted, results, stats, dynamo)
+
+	snapshot := stats.Snapshot()
+	if snapshot.ProcessedJobs != 4 || snapshot.SuccessfulJobs != 2 || snapshot.FailedJobs != 2 || snapshot.FallbackJobs != 2 || snapshot.UnrelatedJobs != 1 {
+		t.Fatalf("unexpected stats: %+v", snapshot)
+	}
+	if len(dynamo.jobs) != 3 || dynamo.jobs[0].JobId != "1" || dynamo.jobs[1].JobId != "2" || !dynamo.jobs[2].LowConfidence {
+		t.Fatalf("expected enriched and fallback jobs to be stored, got %+v", dynamo.jobs)
+	}
+	if len(failures) != 2 || failures[0].Job.Description != "Build Go services." || failures[1].Job.LowConfidence {
+		t.Fatalf("expected dead letters holding the submitted jobs, got %+v", failures)
+	}
+}
+
+func TestEnrichmentBatchesFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "enrichment-batches.json")
+
+	empty, err := readEnrichmentBatchesFile(filename)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no batches from a missing file, got %+v (%v)", empty, err)
+	}
+
+	want := []models.EnrichmentBatch{{
+		ID:          "batch_1",
+		InputFileID: "file-in",
+		Status:      "in_progress",
+		SubmittedAt: "2026-10-16T08:00:00Z",
+		Jobs:        []models.Job{{JobId: "1", Title: "Go Developer", Description: "Build Go services."}},
+	}}
+	if err := writeEnrichmentBatchesFile(filename, want); err != nil {
+		t.Fatalf("writeEnrichmentBatchesFile returned error: %v", err)
+	}
+	got, err := readEnrichmentBatchesFile(filename)
+	if err != nil {
+		t.Fatalf("readEnrichmentBatchesFile returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("batches mismatch: got %+v want %+v", got, want)
+	}
+}
+
+func TestDeadLettersFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "dead-letters.jsonl")
+
+	empty, err := readDeadLettersFile(filename)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no dead letters from a missing file, got %+v (%v)", empty, err)
+	}
+
+	want := []models.DeadLetter{
+		newDeadLetter(models.Job{JobId: "1", Title: "Go Developer", Description: "Build Go services.\nOn call."}, errors.New("rate limited"), time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)),
+		newDeadLetter(models.Job{JobId: "2"}, errors.New("empty response from LLM"), time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)),
+	}
+	if err := writeDeadLettersFile(filename, want); err != nil {
+		t.Fatalf("writeDeadLettersFile returned error: %v", err)
+	}
+	got, err := readDeadLettersFile(filename)
+	if err != nil {
+		t.Fatalf("readDeadLettersFile returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("dead letters mismatch: got %+v want %+v", got, want)
+	}
+}
+
+func TestMergeDeadLettersSumsAttempts(t *testing.T) {
+	first := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	letters := []models.DeadLetter{newDeadLetter(models.Job{JobId: "1"}, errors.New("timeout"), first)}
+	failures := []models.DeadLetter{
+		newDeadLetter(models.Job{JobId: "1"}, errors.New("rate limited"), first.Add(time.Hour)),
+		newDeadLetter(models.Job{JobId: "2"}, errors.New("timeout"), first.Add(time.Hour)),
+	}
+
+	merged := mergeDeadLetters(letters, failures)
+
+	if len(merged) != 2 {
+		t.Fatalf("expected one entry per job, got %+v", merged)
+	}
+	if merged[0].Attempts != 2 || merged[0].Error != "rate limited" || merged[0].FirstFailedAt != "2026-10-16T08:00:00Z" || merged[0].LastFailedAt != "2026-10-16T09:00:00Z" {
+		t.Fatalf("expected the repeat failure folded into the first entry, got %+v", merged[0])
+	}
+	if merged[1].Job.JobId != "2" || merged[1].Attempts != 1 {
+		t.Fatalf("expected a new entry for job 2, got %+v", merged[1])
+	}
+}
+
+func TestDeadLetterDueBacksOff(t *testing.T) {
+	failedAt := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	letter := newDeadLetter(models.Job{JobId: "1"}, errors.New("timeout"), failedAt)
+	backoff := 15 * time.Minute
+
+	if deadLetterDue(letter, failedAt.Add(14*time.Minute), backoff, 5) {
+		t.Fatal("expected the first retry to wait out the backoff")
+	}
+	if !deadLetterDue(letter, failedAt.Add(15*time.Minute), backoff, 5) {
+		t.Fatal("expected the first retry once the backoff passed")
+	}
+	letter.Attempts = 3
+	if deadLetterDue(letter, failedAt.Add(59*time.Minute), backoff, 5) || !deadLetterDue(letter, failedAt.Add(time.Hour), backoff, 5) {
+		t.Fatal("expected the backoff to double with each attempt")
+	}
+	letter.Attempts = 5
+	if deadLetterDue(letter, failedAt.Add(48*time.Hour), backoff, 5) {
+		t.Fatal("expected no retry after the attempt limit")
+	}
+}
+
+type fakeArchive struct {
+	descriptions map[string]string
+}
+
+func (f *fakeArchive) Put(ctx context.Context, job *models.Job) (string, error) {
+	pointer := "s3://bucket/descriptions/" + job.JobId + ".txt"
+	f.descriptions[pointer] = job.Description
+	return pointer, nil
+}
+
+func (f *fakeArchive) Get(ctx context.Context, pointer string) (string, error) {
+	description, ok := f.descriptions[pointer]
+	if !ok {
+		return "", errors.New("not archived")
+	}
+	return description, nil
+}
+
+func TestReparseJobsUpdatesFromArchive(t *testing.T) {
+	archive := &fakeArchive{descriptions: map[string]string{"s3://bucket/descriptions/1.txt": "Build Go services."}}
+	jobs := []models.Job{
+		{JobId: "1", S3Pointer: "s3://bucket/descriptions/1.txt", ExtractionVersion: "v1"},
+		{JobId: "2", Description: "Fallback row.", ExtractionVersion: services.FallbackExtractionVersion},
+		{JobId: "3", ExtractionVersion: "v1"},
+	}
+	parser := &fakeParser{
+		responses: []*models.Job{{JobId: "1", Domain: "Backend"}, {JobId: "2"}},
+		errs:      []error{nil, errors.New("llm unavailable")},
+	}
+	dynamo := &fakeDynamo{}
+	stats := &models.JobStats{}
+	result := &ReparseResult{}
+
+	reparseJobs(context.Background(), jobs, parser, archive, dynamo, stats, 1, result)
+
+	if result.Updated != 1 || result.Skipped != 1 || stats.FailedJobs != 1 {
+		t.Fatalf("expected 1 update, 1 skip and 1 failure, got %+v and %d failed", result, stats.FailedJobs)
+	}
+	if len(dynamo.updates) != 1 || dynamo.updates[0].JobId != "1" || dynamo.updates[0].Domain != "Backend" {
+		t.Fatalf("expected only job 1 to be updated, got %+v", dynamo.updates)
+	}
+	if _, ok := archive.descriptions["s3://bucket/descriptions/2.txt"]; !ok {
+		t.Fatal("expected the fallback row's description to be archived")
+	}
+}
+
+func TestSelectForReparse(t *testing.T) {
+	current := models.Job{ExtractionVersion: services.EnrichmentPromptVersion()}
+	fallback := models.Job{ExtractionVersion: services.FallbackExtractionVersion}
+	legacy := models.Job{}
+
+	if selectForReparse(current, ReparseOptions{Outdated: true}) || !selectForReparse(legacy, ReparseOptions{Outdated: true}) {
+		t.Fatal("expected outdated selection to skip only the current version")
+	}
+	versions := ReparseOptions{Versions: []string{services.FallbackExtractionVersion, ""}}
+	if !selectForReparse(fallback, versions) || !selectForReparse(legacy, versions) || selectForReparse(current, versions) {
+		t.Fatal("expected version selection to match listed versions")
+	}
+}
+
+func TestMergeSkillReviewSumsCounts(t *testing.T) {
+	first := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	reviews := []models.SkillReview{
+		{Term: "Gleam", Count: 2, FirstSeen: "2026-10-15T08:00:00Z", LastSeen: "2026-10-15T08:00:00Z"},
+		// learned by the taxonomy since it was queued
+		{Term: "Golang", Count: 9, FirstSeen: "2026-10-15T08:00:00Z", LastSeen: "2026-10-15T08:00:00Z"},
+	}
+
+	merged := mergeSkillReview(reviews, map[string]int64{"Gleam": 1, "Bazel Remote": 3, "Acme SDK": 3}, first.Add(24*time.Hour))
+
+	want := []models.SkillReview{
+		{Term: "Acme SDK", Count: 3, FirstSeen: "2026-10-16T08:00:00Z", LastSeen: "2026-10-16T08:00:00Z"},
+		{Term: "Bazel Remote", Count: 3, FirstSeen: "2026-10-16T08:00:00Z", LastSeen: "2026-10-16T08:00:00Z"},
+		{Term: "Gleam", Count: 3, FirstSeen: "2026-10-15T08:00:00Z", LastSeen: "2026-10-16T08:00:00Z"},
+	}
+	if !reflect.DeepEqual(merged, want) {
+		t.Fatalf("merged reviews mismatch: got %+v want %+v", merged, want)
+	}
+}
+
+func TestSkillReviewFileRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "skill-review.json")
+
+	empty, err := readSkillReviewFile(filename)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("expected no reviews from a missing file, got %+v (%v)", empty, err)
+	}
+
+	want := []models.SkillReview{{Term: "Gleam", Count: 4, FirstSeen: "2026-10-15T08:00:00Z", LastSeen: "2026-10-16T08:00:00Z"}}
+	if err := writeSkillReviewFile(filename, want); err != nil {
+		t.Fatalf("writeSkillReviewFile returned error: %v", err)
+	}
+	got, err := readSkillReviewFile(filename)
+	if err != nil {
+		t.Fatalf("readSkillReviewFile returned error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("skill review mismatch: got %+v want %+v", got, want)
+	}
+}
+
+func TestTrackPostingsRecordsChangesAndClosures(t *testing.T) {
+	first := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	listing := func(id, posted, closing, salary string) models.Job {
+		return models.Job{JobId: id, Source: "worksourcewa", Title: "Engineer " + id, PostedDate: posted, ExpiresDate: closing, Salary: salary}
+	}
+	tracked := make(map[string]models.TrackedPosting)
+	changed := trackPostings(tracked, []models.SearchCoverage{{Source: "worksourcewa", Query: "software engineer", Jobs: []models.Job{
+		listing("edited", "2026-10-14", "2026-11-01", "$50/hour"),
+		listing("pulled", "2026-10-13", "", ""),
+		listing("old", "2026-10-01", "", ""),
+	}}}, first)
+	if len(changed) != 0 || len(tracked) != 3 {
+		t.Fatalf("expected three new postings without events, got %v and %+v", changed, tracked)
+	}
+
+	second := first.Add(24 * time.Hour)
+	changed = trackPostings(tracked, []models.SearchCoverage{{Source: "worksourcewa", Query: "software engineer", Jobs: []models.Job{
+		listing("new", "2026-10-16", "", ""),
+		listing("edited", "2026-10-14", "2026-12-01", "$55/hour"),
+		// the search stopped at this page, before reaching "old"
+		listing("floor", "2026-10-10", "", ""),
+	}}}, second)
+	if !reflect.DeepEqual(changed, []string{"edited", "pulled"}) {
+		t.Fatalf("expected the edited and pulled postings to change, got %v", changed)
+	}
+
+	edited := tracked["edited"].History
+	wantEdited := []models.PostingEvent{
+		{Type: models.PostingEventClosingDateChanged, At: "2026-10-16T08:00:00Z", Field: "closingDate", Old: "2026-11-01", New: "2026-12-01"},
+		{Type: models.PostingEventChanged, At: "2026-10-16T08:00:00Z", Field: "salary", Old: "$50/hour", New: "$55/hour"},
+	}
+	if !reflect.DeepEqual(edited, wantEdited) || tracked["edited"].Salary != "$55/hour" || tracked["edited"].FirstSeen != "2026-10-15T08:00:00Z" {
+		t.Fatalf("unexpected edited posting: %+v", tracked["edited"])
+	}
+	if pulled := tracked["pulled"]; pulled.ClosedAt != "2026-10-16T08:00:00Z" || pulled.History[0].Type != models.PostingEventDisappeared {
+		t.Fatalf("expected the pulled posting to be closed, got %+v", pulled)
+	}
+	if tracked["old"].ClosedAt != "" {
+		t.Fatalf("expected a posting older than the search reached to stay open, got %+v", tracked["old"])
+	}
+
+	changed = trackPostings(tracked, []models.SearchCoverage{{Source: "worksourcewa", Query: "data engineer", Jobs: []models.Job{
+		listing("pulled", "2026-10-13", "", ""),
+	}}}, second.Add(time.Hour))
+	if !reflect.DeepEqual(changed, []string{"pulled"}) || tracked["pulled"].ClosedAt != "" {
+		t.Fatalf("expected the pulled posting to reopen, got %v and %+v", changed, tracked["pulled"])
+	}
+	if history := tracked["pulled"].History; history[len(history)-1].Type != models.PostingEventReappeared {
+		t.Fatalf("expected a reappeared event, got %+v", history)
+	}
+
+	tracked["pulled"] = models.TrackedPosting{JobId: "pulled", ClosedAt: "2026-01-01T00:00:00Z"}
+	trackPostings(tracked, nil, second)
+	if _, kept := tracked["pulled"]; kept {
+		t.Fatal("expected a posting closed past the retention window to be dropped")
+	}
+}
+
+// failingHistoryStore fails UpdateJobHistory with err until err is cleared.
+type failingHistoryStore struct {
+	services.JobStore
+	err error
+}
+
+func (f *failingHistoryStore) UpdateJobHistory(ctx context.Context, job *models.Job) error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.JobStore.UpdateJobHistory(ctx, job)
+}
+
+// newestFirstSource lists its postings newest first and, like WorkSourceWA,
+// returns the ones the watermark covers along with the new ones.
+type newestFirstSource struct {
+	jobs []models.Job
+}
+
+func (s *newestFirstSource) Name() string {
+	return services.WorkSourceSourceName
+}
+
+func (s *newestFirstSource) SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error) {
+	return s.jobs, nil
+}
+
+func (s *newestFirstSource) SearchJobsSince(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark) ([]models.Job, models.ScrapeWatermark, error) {
+	newest := since
+	if len(s.jobs) > 0 && !since.Covers(s.jobs[0].PostedTime, s.jobs[0].JobId) {
+		newest = models.ScrapeWatermark{LastPostingDate: s.jobs[0].PostedTime, LastRecordID: s.jobs[0].JobId}
+	}
+	return s.jobs, newest, nil
+}
+
+func TestPostingHistorySeesListingsBehindWatermarks(t *testing.T) {
+	ctx := context.Background()
+	cfg := config.Config{
+		UseScrapeWatermarks: true,
+		UsePostingHistory:   true,
+		PostingHistoryFile:  filepath.Join(t.TempDir(), "posting-history.json"),
+	}
+	store := services.NewMemoryStore()
+	posting := func(id, date string) models.Job {
+		return models.Job{JobId: id, Title: "Engineer " + id, PostedDate: date, PostedTime: date + "T08:00:00Z"}
+	}
+	a, b, c, d := posting("a", "2026-10-03"), posting("b", "2026-10-02"), posting("c", "2026-10-01"), posting("d", "2026-10-04")
+
+	watermarks := map[string]models.ScrapeWatermark{}
+	processed := map[string]bool{}
+	now := time.Date(2026, 10, 5, 8, 0, 0, 0, time.UTC)
+	// run scrapes listings the way Run does, storing what it sends and
+	// folding the coverage into the posting history
+	run := func(listings ...models.Job) []string {
+		t.Helper()
+		scraper := services.NewScraperWithSources(cfg, false, processed, []services.JobSource{&newestFirstSource{jobs: listings}})
+		scraper.SetWatermarks(watermarks)
+		jobsChan := make(chan models.Job)
+		var sent []string
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for job := range jobsChan {
+				sent = append(sent, job.JobId)
+				if err := store.PutJob(ctx, &job); err != nil {
+					t.Errorf("PutJob returned error: %v", err)
+				}
+			}
+		}()
+		if err := scraper.ScrapeJobs(ctx, []config.ScrapeQuery{{Query: "software engineer"}}, jobsChan, &models.JobStats{}); err != nil {
+			t.Fatalf("ScrapeJobs returned error: %v", err)
+		}
+		<-done
+		if _, err := recordPostingHistory(ctx, &cfg, nil, store, scraper.GetSearchCoverage(), now); err != nil {
+			t.Fatalf("recordPostingHistory returned error: %v", err)
+		}
+		watermarks, processed = scraper.GetWatermarks(), scraper.GetProcessedIDs()
+		now = now.Add(24 * time.Hour)
+		return sent
+	}
+
+	if sent := run(a, b, c); strings.Join(sent, ",") != "a,b,c" {
+		t.Fatalf("expected every posting on the first run, got %v", sent)
+	}
+	if sent := run(d, a, c); strings.Join(sent, ",") != "d" {
+		t.Fatalf("expected only the posting newer than the watermark to be sent, got %v", sent)
+	}
+	tracked, err := readPostingHistoryFile(cfg.PostingHistoryFile)
+	if err != nil {
+		t.Fatalf("read posting history: %v", err)
+	}
+	if tracked["b"].ClosedAt == "" || tracked["a"].ClosedAt != "" || tracked["c"].ClosedAt != "" || tracked["d"].ClosedAt != "" {
+		t.Fatalf("expected only the missing posting behind the watermark to close, got %+v", tracked)
+	}
+
+	if sent := run(d, b, a, c); len(sent) != 0 {
+		t.Fatalf("expected nothing new to be sent, got %v", sent)
+	}
+	tracked, _ = readPostingHistoryFile(cfg.PostingHistoryFile)
+	history := tracked["b"].History
+	if tracked["b"].ClosedAt != "" || len(history) != 2 || history[1].Type != models.PostingEventReappeared {
+		t.Fatalf("expected the closed posting to reappear, got %+v", tracked["b"])
+	}
+	stored, _ := store.QueryJobsByPostedDate(ctx, "2026-10-02")
+	if len(stored) != 1 || len(stored[0].History) != 2 {
+		t.Fatalf("expected the stored job to carry both events, got %+v", stored)
+	}
+}
+
+func TestRecordPostingHistoryRetriesFailedUpdates(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{PostingHistoryFile: filepath.Join(t.TempDir(), "posting-history.json")}
+	store := &failingHistoryStore{JobStore: services.NewMemoryStore(), err: errors.New("AccessDeniedException")}
+	job := models.Job{JobId: "1", Source: "worksourcewa", Title: "Go Developer", PostedDate: "2026-10-15"}
+	if err := store.PutJob(ctx, &job); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+	coverage := []models.SearchCoverage{{Source: "worksourcewa", Query: "software engineer", Jobs: []models.Job{job}}}
+	first := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	if _, err := recordPostingHistory(ctx, cfg, nil, store, coverage, first); err != nil {
+		t.Fatalf("recordPostingHistory returned error: %v", err)
+	}
+
+	retitled := job
+	retitled.Title = "Senior Go Developer"
+	coverage[0].Jobs = []models.Job{retitled}
+	result, err := recordPostingHistory(ctx, cfg, nil, store, coverage, first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("recordPostingHistory returned error: %v", err)
+	}
+	if result.failed != 1 || result.updated != 0 {
+		t.Fatalf("expected the failed update to be counted, got %+v", result)
+	}
+	if tracked, _ := readPostingHistoryFile(cfg.PostingHistoryFile); !tracked["1"].Unsynced {
+		t.Fatalf("expected the posting to be marked unsynced, got %+v", tracked["1"])
+	}
+
+	store.err = nil
+	result, err = recordPostingHistory(ctx, cfg, nil, store, coverage, first.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("recordPostingHistory returned error: %v", err)
+	}
+	if result.failed != 0 || result.updated != 1 {
+		t.Fatalf("expected the unsynced posting to be retried, got %+v", result)
+	}
+	stored, _ := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+	if len(stored) != 1 || len(stored[0].History) != 1 {
+		t.Fatalf("expected the stored job to receive the title change, got %+v", stored)
+	}
+	if tracked, _ := readPostingHistoryFile(cfg.PostingHistoryFile); tracked["1"].Unsynced {
+		t.Fatalf("expected the posting to be synced, got %+v", tracked["1"])
+	}
+}
+
+func TestRecordPostingHistoryUpdatesStoredJobs(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{PostingHistoryFile: filepath.Join(t.TempDir(), "posting-history.json")}
+	store := services.NewMemoryStore()
+	job := models.Job{JobId: "1", Source: "worksourcewa", Title: "Go Developer", PostedDate: "2026-10-15", Domain: "Backend"}
+	if err := store.PutJob(ctx, &job); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+	coverage := func(jobs ...models.Job) []models.SearchCoverage {
+		return []models.SearchCoverage{{Source: "worksourcewa", Query: "software engineer", Jobs: jobs}}
+	}
+	first := time.Date(2026, 10, 15, 8, 0, 0, 0, time.UTC)
+	if _, err := recordPostingHistory(ctx, cfg, nil, store, coverage(job), first); err != nil {
+		t.Fatalf("recordPostingHistory returned error: %v", err)
+	}
+
+	older := models.Job{JobId: "2", Source: "worksourcewa", PostedDate: "2026-10-01"}
+	result, err := recordPostingHistory(ctx, cfg, nil, store, coverage(older), first.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("recordPostingHistory returned error: %v", err)
+	}
+	if result.updated != 1 || result.closed != 1 || result.tracked != 2 {
+		t.Fatalf("unexpected history result: %+v", result)
+	}
+	stored, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+	if err != nil || len(stored) != 1 {
+		t.Fatalf("expected the stored job, got %+v (%v)", stored, err)
+	}
+	if stored[0].ClosedAt != "2026-10-16T08:00:00Z" || len(stored[0].History) != 1 || stored[0].Domain != "Backend" {
+		t.Fatalf("expected the stored job to be closed, got %+v", stored[0])
+	}
+
+	tracked, err := readPostingHistoryFile(cfg.PostingHistoryFile)
+	if err != nil || len(tracked) != 2 || tracked["1"].ClosedAt == "" {
+		t.Fatalf("expected the tracker to be saved, got %+v (%v)", tracked, err)
+	}
 }
@@ -0,0 +1,230 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const enrichmentBatchesLabel = "enrichment batches"
+
+// BatchApplyResult summarizes one pass over the submitted enrichment batches.
+type BatchApplyResult struct {
+	Stats          models.JobStats
+	BatchesApplied int
+	BatchesPending int
+	DeadLetters    int
+}
+
+// ApplyEnrichmentBatches stores the results of every finished batch and keeps
+// the rest for a later pass. Jobs that failed or are missing from a failed or
+// expired batch are queued as dead letters.
+func ApplyEnrichmentBatches(ctx context.Context, cfg *config.Config) (*BatchApplyResult, error) {
+	store, awsConfig, err := openJobStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var s3Service services.S3Client
+	if cfg.UseS3JobIDFile && cfg.JobIDsBucket != "" {
+		s3Service = services.NewS3Service(awsConfig)
+	}
+	batchClient, err := services.NewBatchEnrichmentClient(*cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := loadEnrichmentBatches(ctx, cfg, s3Service)
+	if err != nil {
+		return nil, fmt.Errorf("load enrichment batches: %w", err)
+	}
+	utils.Debug(fmt.Sprintf("Checking %d enrichment batch(es)", len(batches)))
+
+	result := &BatchApplyResult{}
+	stats := &models.JobStats{}
+	finished := make(map[string]bool)
+	var failures []models.DeadLetter
+	for i := range batches {
+		batch := &batches[i]
+		if err := batchClient.Refresh(ctx, batch); err != nil {
+			log.Printf("Failed to refresh enrichment batch %s: %v", batch.ID, err)
+			continue
+		}
+		if !services.EnrichmentBatchFinished(batch.Status) {
+			utils.Debug(fmt.Sprintf("Enrichment batch %s is %s", batch.ID, batch.Status))
+			continue
+		}
+		jobResults, err := batchClient.Results(ctx, *batch)
+		if err != nil {
+			log.Printf("Failed to read results of enrichment batch %s: %v", batch.ID, err)
+			continue
+		}
+		failures = append(failures, storeBatchResults(ctx, batch.Jobs, jobResults, stats, store)...)
+		finished[batch.ID] = true
+		utils.Debug(fmt.Sprintf("Applied enrichment batch %s (%s) with %d job(s)", batch.ID, batch.Status, len(batch.Jobs)))
+	}
+
+	if len(failures) > 0 && cfg.UseDeadLetters {
+		if err := recordDeadLetters(ctx, cfg, s3Service, failures); err != nil {
+			return nil, err
+		}
+		result.DeadLetters = len(failures)
+	}
+	if err := recordUnknownSkills(ctx, cfg, s3Service, stats.Snapshot().UnknownSkills); err != nil {
+		return nil, err
+	}
+
+	pending := len(batches) - len(finished)
+	if len(finished) > 0 {
+		// the scraper may have queued batches since this pass started, so
+		// only the finished ones are removed from the latest state
+		latest, err := loadEnrichmentBatches(ctx, cfg, s3Service)
+		if err != nil {
+			return nil, fmt.Errorf("reload enrichment batches: %w", err)
+		}
+		remaining := latest[:0]
+		for _, batch := range latest {
+			if !finished[batch.ID] {
+				remaining = append(remaining, batch)
+			}
+		}
+		if err := saveEnrichmentBatches(ctx, cfg, s3Service, remaining); err != nil {
+			return nil, fmt.Errorf("save enrichment batches: %w", err)
+		}
+		pending = len(remaining)
+	}
+
+	result.Stats = stats.Snapshot()
+	result.BatchesApplied = len(finished)
+	result.BatchesPending = pending
+	return result, nil
+}
+
+// storeBatchResults stores every result in batched writes and returns the
+// failed ones, enrichment or write, as dead letters holding the submitted
+// job, description included.
+func storeBatchResults(ctx context.Context, submitted []models.Job, jobResults []services.BatchJobResult, stats *models.JobStats, store services.JobStore) []models.DeadLetter {
+	byID := make(map[string]models.Job, len(submitted))
+	for _, job := range submitted {
+		byID[job.JobId] = job
+	}
+	var failures []models.DeadLetter
+	var parsed []models.Job
+	enriched := make(map[string]bool, len(jobResults))
+	now := time.Now()
+	for _, jobResult := range jobResults {
+		atomic.AddInt64(&stats.ProcessedJobs, 1)
+		stats.AddUsage(jobResult.Usage)
+		if jobResult.Err != nil {
+			log.Printf("Batch enrichment failed for job %s, using fallback rules: %v", jobResult.JobID, jobResult.Err)
+			atomic.AddInt64(&stats.FailedJobs, 1)
+			atomic.AddInt64(&stats.FallbackJobs, 1)
+			failures = append(failures, newDeadLetter(byID[jobResult.JobID], jobResult.Err, now))
+		} else {
+			atomic.AddInt64(&stats.SuccessfulJobs, 1)
+			stats.AddUnknownSkills(services.UnknownSkills(jobResult.Job))
+			enriched[jobResult.JobID] = true
+		}
+		if jobResult.Job == nil {
+			continue
+		}
+		if !jobResult.Job.IsSoftwareEngineerRelated {
+			atomic.AddInt64(&stats.UnrelatedJobs, 1)
+		}
+		parsed = append(parsed, *jobResult.Job)
+	}
+	start := time.Now()
+	written, err := store.PutJobs(ctx, parsed)
+	stats.RecordWrites(written.Stored, written.Calls, written.Retries, time.Since(start))
+	if err != nil {
+		// which jobs were stored is unknown, and inserts skip stored jobs,
+		// so every one is queued for retry
+		log.Printf("Failed to put jobs to DynamoDB: %v", err)
+		for _, job := range parsed {
+			if letter, ok := storeFailure(byID[job.JobId], enriched[job.JobId], err, stats, now); ok {
+				failures = append(failures, letter)
+			}
+		}
+	}
+	return failures
+}
+
+// enqueueEnrichmentBatches submits jobs to the Batch API and records the
+// batches for ApplyEnrichmentBatches.
+func enqueueEnrichmentBatches(ctx context.Context, cfg *config.Config, s3Service services.S3Client, jobs []models.Job) ([]models.EnrichmentBatch, error) {
+	batchClient, err := services.NewBatchEnrichmentClient(*cfg)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := loadEnrichmentBatches(ctx, cfg, s3Service)
+	if err != nil {
+		return nil, fmt.Errorf("load enrichment batches: %w", err)
+	}
+	submitted, submitErr := batchClient.Submit(ctx, jobs)
+	if len(submitted) > 0 {
+		if err := saveEnrichmentBatches(ctx, cfg, s3Service, append(existing, submitted...)); err != nil {
+			return submitted, fmt.Errorf("save enrichment batches: %w", err)
+		}
+	}
+	if submitErr != nil {
+		return submitted, fmt.Errorf("submit enrichment batch: %w", submitErr)
+	}
+	return submitted, nil
+}
+
+func loadEnrichmentBatches(ctx context.Context, cfg *config.Config, s3Service services.S3Client) ([]models.EnrichmentBatch, error) {
+	if s3Service != nil && cfg.EnrichmentBatchS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.EnrichmentBatchS3Key, cfg.EnrichmentBatchFile, enrichmentBatchesLabel); err != nil {
+			return nil, err
+		}
+	}
+	return readEnrichmentBatchesFile(cfg.EnrichmentBatchFile)
+}
+
+func saveEnrichmentBatches(ctx context.Context, cfg *config.Config, s3Service services.S3Client, batches []models.EnrichmentBatch) error {
+	if err := writeEnrichmentBatchesFile(cfg.EnrichmentBatchFile, batches); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d enrichment batch(es) to %s", len(batches), cfg.EnrichmentBatchFile))
+	if s3Service != nil && cfg.EnrichmentBatchS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.EnrichmentBatchS3Key, cfg.EnrichmentBatchFile, enrichmentBatchesLabel)
+	}
+	return nil
+}
+
+func readEnrichmentBatchesFile(filename string) ([]models.EnrichmentBatch, error) {
+	var batches []models.EnrichmentBatch
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return batches, nil
+		}
+		return nil, fmt.Errorf("read enrichment batches file: %w", err)
+	}
+	if len(data) == 0 {
+		return batches, nil
+	}
+	if err := json.Unmarshal(data, &batches); err != nil {
+		return nil, fmt.Errorf("decode enrichment batches file: %w", err)
+	}
+	return batches, nil
+}
+
+func writeEnrichmentBatchesFile(filename string, batches []models.EnrichmentBatch) error {
+	data, err := json.Marshal(batches)
+	if err != nil {
+		return fmt.Errorf("encode enrichment batches: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write enrichment batches file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const scrapeCheckpointLabel = "scrape checkpoint"
+
+// withDrainDeadline returns a context that ends reserve before ctx's deadline,
+// leaving time to finish in-flight jobs and save caches. Without a deadline,
+// as in local runs, it only follows ctx.
+func withDrainDeadline(ctx context.Context, reserve time.Duration) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok || reserve <= 0 {
+		return context.WithCancel(ctx)
+	}
+	utils.Debug(fmt.Sprintf("Scraping until %s, %s before the invocation deadline", deadline.Add(-reserve).Format(time.RFC3339), reserve))
+	return context.WithDeadline(ctx, deadline.Add(-reserve))
+}
+
+func loadScrapeCheckpoint(ctx context.Context, cfg *config.Config, s3Service services.S3Client) (models.ScrapeCheckpoint, error) {
+	if s3Service != nil && cfg.CheckpointS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.CheckpointS3Key, cfg.CheckpointFilename, scrapeCheckpointLabel); err != nil {
+			return models.ScrapeCheckpoint{}, err
+		}
+	}
+	return readCheckpointFile(cfg.CheckpointFilename)
+}
+
+// saveScrapeCheckpoint writes checkpoint locally and to S3. An empty
+// checkpoint clears the one a resumed run started from.
+func saveScrapeCheckpoint(ctx context.Context, cfg *config.Config, s3Service services.S3Client, checkpoint models.ScrapeCheckpoint) error {
+	if !checkpoint.IsEmpty() {
+		checkpoint.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+		if lc, ok := lambdacontext.FromContext(ctx); ok {
+			checkpoint.RequestID = lc.AwsRequestID
+		}
+	}
+	if err := writeCheckpointFile(cfg.CheckpointFilename, checkpoint); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved scrape checkpoint with %d pending job(s) and %d cursor(s) to %s", len(checkpoint.PendingJobs), len(checkpoint.Cursors), cfg.CheckpointFilename))
+	if s3Service != nil && cfg.CheckpointS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.CheckpointS3Key, cfg.CheckpointFilename, scrapeCheckpointLabel)
+	}
+	return nil
+}
+
+func readCheckpointFile(filename string) (models.ScrapeCheckpoint, error) {
+	var checkpoint models.ScrapeCheckpoint
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return checkpoint, nil
+		}
+		return checkpoint, fmt.Errorf("read checkpoint file: %w", err)
+	}
+	if len(data) == 0 {
+		return checkpoint, nil
+	}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return models.ScrapeCheckpoint{}, fmt.Errorf("decode checkpoint file: %w", err)
+	}
+	return checkpoint, nil
+}
+
+func writeCheckpointFile(filename string, checkpoint models.ScrapeCheckpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write checkpoint file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const (
+	deadLettersLabel = "dead letters"
+	// retries of a job are never spaced further apart than this
+	deadLetterMaxBackoff = 24 * time.Hour
+)
+
+// RetryResult summarizes one pass over the dead-letter queue.
+type RetryResult struct {
+	Stats     models.JobStats
+	Retried   int
+	Recovered int
+	// Remaining counts jobs still queued, including those waiting out their backoff
+	Remaining int
+	// Exhausted counts queued jobs that reached DeadLetterMaxAttempts
+	Exhausted int
+}
+
+// RetryFailedJobs parses every queued job whose backoff has passed again and
+// stores the ones that succeed, replacing their low-confidence fallback
+// records. force ignores the backoff and the attempt limit.
+func RetryFailedJobs(ctx context.Context, cfg *config.Config, force bool) (*RetryResult, error) {
+	store, awsConfig, err := openJobStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var s3Service services.S3Client
+	if cfg.UseS3JobIDFile && cfg.JobIDsBucket != "" {
+		s3Service = services.NewS3Service(awsConfig)
+	}
+	llmClient, err := services.NewLLMClient(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure LLM provider: %w", err)
+	}
+	stats := &models.JobStats{}
+	parser := services.NewParserService(llmClient, stats)
+
+	letters, err := loadDeadLetters(ctx, cfg, s3Service)
+	if err != nil {
+		return nil, fmt.Errorf("load dead letters: %w", err)
+	}
+	utils.Debug(fmt.Sprintf("Dead-letter queue holds %d job(s)", len(letters)))
+
+	result := &RetryResult{}
+	now := time.Now().UTC()
+	recovered := make(map[string]bool)
+	var failures []models.DeadLetter
+	for _, letter := range letters {
+		if ctx.Err() != nil {
+			break
+		}
+		if !force && !deadLetterDue(letter, now, cfg.DeadLetterBackoff, cfg.DeadLetterMaxAttempts) {
+			continue
+		}
+		result.Retried++
+		atomic.AddInt64(&stats.ProcessedJobs, 1)
+		job := letter.Job
+		enhancedJob, err := parser.ParseWithStats(ctx, &job)
+		if err == nil {
+			err = replaceFallback(ctx, store, enhancedJob)
+		}
+		if err != nil {
+			log.Printf("Retry %d failed for job %s: %v", letter.Attempts+1, job.JobId, err)
+			atomic.AddInt64(&stats.FailedJobs, 1)
+			failures = append(failures, newDeadLetter(letter.Job, err, now))
+			continue
+		}
+		atomic.AddInt64(&stats.SuccessfulJobs, 1)
+		if !enhancedJob.IsSoftwareEngineerRelated {
+			atomic.AddInt64(&stats.UnrelatedJobs, 1)
+		}
+		recovered[job.JobId] = true
+	}
+
+	remaining := letters
+	if result.Retried > 0 {
+		// the scraper may have queued failures since this pass started, so
+		// the outcome is applied to the latest queue
+		latest, err := loadDeadLetters(ctx, cfg, s3Service)
+		if err != nil {
+			return nil, fmt.Errorf("reload dead letters: %w", err)
+		}
+		remaining = latest[:0]
+		for _, letter := range latest {
+			if !recovered[letter.Job.JobId] {
+				remaining = append(remaining, letter)
+			}
+		}
+		remaining = mergeDeadLetters(remaining, failures)
+		if err := saveDeadLetters(ctx, cfg, s3Service, remaining); err != nil {
+			return nil, fmt.Errorf("save dead letters: %w", err)
+		}
+	}
+	if err := recordUnknownSkills(ctx, cfg, s3Service, stats.Snapshot().UnknownSkills); err != nil {
+		return nil, err
+	}
+
+	result.Stats = stats.Snapshot()
+	result.Recovered = len(recovered)
+	result.Remaining = len(remaining)
+	for _, letter := range remaining {
+		if letter.Attempts >= cfg.DeadLetterMaxAttempts {
+			result.Exhausted++
+		}
+	}
+	return result, nil
+}
+
+// replaceFallback rewrites the enriched fields of the fallback record a failed
+// parse left behind, and stores job outright when there is none.
+func replaceFallback(ctx context.Context, store services.JobStore, job *models.Job) error {
+	err := store.UpdateJobEnrichment(ctx, job)
+	if errors.Is(err, services.ErrJobNotStored) {
+		return store.PutJob(ctx, job)
+	}
+	return err
+}
+
+// recordDeadLetters adds failures to the stored queue so their jobs are
+// retried even though their IDs are now in the job ID cache.
+func recordDeadLetters(ctx context.Context, cfg *config.Config, s3Service services.S3Client, failures []models.DeadLetter) error {
+	letters, err := loadDeadLetters(ctx, cfg, s3Service)
+	if err != nil {
+		return fmt.Errorf("load dead letters: %w", err)
+	}
+	if err := saveDeadLetters(ctx, cfg, s3Service, mergeDeadLetters(letters, failures)); err != nil {
+		return fmt.Errorf("save dead letters: %w", err)
+	}
+	return nil
+}
+
+func newDeadLetter(job models.Job, err error, failedAt time.Time) models.DeadLetter {
+	timestamp := failedAt.UTC().Format(time.RFC3339)
+	return models.DeadLetter{
+		Job:           job,
+		Error:         err.Error(),
+		Attempts:      1,
+		FirstFailedAt: timestamp,
+		LastFailedAt:  timestamp,
+	}
+}
+
+// storeFailure records an enriched job that could not be stored, returning a
+// dead letter that holds submitted, the job as scraped, so a retry parses it
+// again. A job whose enrichment failed already has one, so false is returned.
+func storeFailure(submitted models.Job, enriched bool, err error, stats *models.JobStats, failedAt time.Time) (models.DeadLetter, bool) {
+	atomic.AddInt64(&stats.StoreFailures, 1)
+	if !enriched {
+		return models.DeadLetter{}, false
+	}
+	atomic.AddInt64(&stats.SuccessfulJobs, -1)
+	atomic.AddInt64(&stats.FailedJobs, 1)
+	return newDeadLetter(submitted, err, failedAt), true
+}
+
+// mergeDeadLetters keeps one entry per job: a job that failed again has its
+// attempts added up and its latest error and time recorded.
+func mergeDeadLetters(letters, failures []models.DeadLetter) []models.DeadLetter {
+	index := make(map[string]int, len(letters))
+	for i, letter := range letters {
+		index[letter.Job.JobId] = i
+	}
+	for _, failure := range failures {
+		i, ok := index[failure.Job.JobId]
+		if !ok {
+			index[failure.Job.JobId] = len(letters)
+			letters = append(letters, failure)
+			continue
+		}
+		letters[i].Job = failure.Job
+		letters[i].Error = failure.Error
+		letters[i].Attempts += failure.Attempts
+		letters[i].LastFailedAt = failure.LastFailedAt
+	}
+	return letters
+}
+
+// deadLetterDue reports whether a job has attempts left and has waited
+// backoff after its first failure, doubling with each further attempt.
+func deadLetterDue(letter models.DeadLetter, now time.Time, backoff time.Duration, maxAttempts int) bool {
+	if letter.Attempts >= maxAttempts {
+		return false
+	}
+	lastFailed, err := time.Parse(time.RFC3339, letter.LastFailedAt)
+	if err != nil {
+		return true
+	}
+	wait := deadLetterMaxBackoff
+	if shift := letter.Attempts - 1; shift < 16 {
+		wait = min(backoff<<max(shift, 0), deadLetterMaxBackoff)
+	}
+	return !now.Before(lastFailed.Add(wait))
+}
+
+func loadDeadLetters(ctx context.Context, cfg *config.Config, s3Service services.S3Client) ([]models.DeadLetter, error) {
+	if s3Service != nil && cfg.DeadLetterS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.DeadLetterS3Key, cfg.DeadLetterFile, deadLettersLabel); err != nil {
+			return nil, err
+		}
+	}
+	return readDeadLettersFile(cfg.DeadLetterFile)
+}
+
+func saveDeadLetters(ctx context.Context, cfg *config.Config, s3Service services.S3Client, letters []models.DeadLetter) error {
+	if err := writeDeadLettersFile(cfg.DeadLetterFile, letters); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d dead letter(s) to %s", len(letters), cfg.DeadLetterFile))
+	if s3Service != nil && cfg.DeadLetterS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.DeadLetterS3Key, cfg.DeadLetterFile, deadLettersLabel)
+	}
+	return nil
+}
+
+// readDeadLettersFile reads one JSON dead letter per line.
+func readDeadLettersFile(filename string) ([]models.DeadLetter, error) {
+	var letters []models.DeadLetter
+	file, err := os.Open(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return letters, nil
+		}
+		return nil, fmt.Errorf("read dead letters file: %w", err)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	for {
+		var letter models.DeadLetter
+		if err := decoder.Decode(&letter); err != nil {
+			if errors.Is(err, io.EOF) {
+				return letters, nil
+			}
+			return nil, fmt.Errorf("decode dead letters file: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+}
+
+func writeDeadLettersFile(filename string, letters []models.DeadLetter) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("write dead letters file: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	for _, letter := range letters {
+		if err := encoder.Encode(letter); err != nil {
+			file.Close()
+			return fmt.Errorf("encode dead letter for job %s: %w", letter.Job.JobId, err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("write dead letters file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const enrichmentCacheLabel = "enrichment cache"
+
+// loadEnrichmentCache syncs the enrichment cache from S3 when the job ID
+// cache bucket is configured, then reads it from disk.
+func loadEnrichmentCache(ctx context.Context, cfg *config.Config, s3Service services.S3Client) (services.EnrichmentCache, error) {
+	if s3Service != nil && cfg.EnrichmentCacheS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.EnrichmentCacheS3Key, cfg.EnrichmentCacheFile, enrichmentCacheLabel); err != nil {
+			return nil, err
+		}
+	}
+	entries, err := readEnrichmentCacheFile(cfg.EnrichmentCacheFile)
+	if err != nil {
+		return nil, err
+	}
+	return services.NewEnrichmentCache(services.EnrichmentCacheScope(*cfg), entries), nil
+}
+
+// saveEnrichmentCache evicts stale entries first so the file, which every run
+// downloads and uploads, stays bounded.
+func saveEnrichmentCache(ctx context.Context, cfg *config.Config, s3Service services.S3Client, cache services.EnrichmentCache) error {
+	if evicted := cache.Evict(time.Now(), cfg.EnrichmentCacheMaxAge, cfg.EnrichmentCacheSize); evicted > 0 {
+		utils.Debug(fmt.Sprintf("Evicted %d cached enrichment response(s)", evicted))
+	}
+	entries := cache.Entries()
+	if err := writeEnrichmentCacheFile(cfg.EnrichmentCacheFile, entries); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d cached enrichment response(s) to %s", len(entries), cfg.EnrichmentCacheFile))
+	if s3Service != nil && cfg.EnrichmentCacheS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.EnrichmentCacheS3Key, cfg.EnrichmentCacheFile, enrichmentCacheLabel)
+	}
+	return nil
+}
+
+func readEnrichmentCacheFile(filename string) (map[string]services.EnrichmentCacheEntry, error) {
+	entries := make(map[string]services.EnrichmentCacheEntry)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entries, nil
+		}
+		return nil, fmt.Errorf("read enrichment cache file: %w", err)
+	}
+	if len(data) == 0 {
+		return entries, nil
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("decode enrichment cache file: %w", err)
+	}
+	return entries, nil
+}
+
+func writeEnrichmentCacheFile(filename string, entries map[string]services.EnrichmentCacheEntry) error {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("encode enrichment cache: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write enrichment cache file: %w", err)
+	}
+	return nil
+}
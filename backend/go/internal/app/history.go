@@ -0,0 +1,256 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const (
+	postingHistoryLabel = "posting history"
+	// closed postings are kept this long in case they reappear
+	closedPostingRetention = 90 * 24 * time.Hour
+)
+
+// historyResult counts what one run changed in the tracked postings.
+type historyResult struct {
+	tracked int
+	// updated counts stored jobs whose ClosedAt or History was rewritten
+	updated int
+	closed  int
+	// failed counts stored jobs whose update failed; they are retried next run
+	failed int
+}
+
+// recordPostingHistory compares the listings a run's searches returned with
+// the tracked postings, then saves the tracker and copies the new events to
+// the stored jobs. Postings whose earlier update failed are copied again.
+func recordPostingHistory(ctx context.Context, cfg *config.Config, s3Service services.S3Client, store services.JobStore, coverage []models.SearchCoverage, now time.Time) (historyResult, error) {
+	var result historyResult
+	tracked, err := loadPostingHistory(ctx, cfg, s3Service)
+	if err != nil {
+		return result, fmt.Errorf("load posting history: %w", err)
+	}
+	changed := trackPostings(tracked, coverage, now)
+	result.tracked = len(tracked)
+
+	isChanged := make(map[string]bool, len(changed))
+	for _, jobID := range changed {
+		isChanged[jobID] = true
+	}
+	pending := append([]string(nil), changed...)
+	for jobID, posting := range tracked {
+		if posting.Unsynced && !isChanged[jobID] {
+			pending = append(pending, jobID)
+		}
+	}
+	sort.Strings(pending)
+
+	for _, jobID := range pending {
+		posting := tracked[jobID]
+		if isChanged[jobID] && posting.ClosedAt != "" {
+			result.closed++
+		}
+		job := models.Job{JobId: posting.JobId, PostedDate: posting.PostedDate, Title: posting.Title, ClosedAt: posting.ClosedAt, History: posting.History}
+		err := store.UpdateJobHistory(ctx, &job)
+		switch {
+		case err == nil:
+			posting.Unsynced = false
+			result.updated++
+		case errors.Is(err, services.ErrJobNotStored):
+			utils.Debug(fmt.Sprintf("Posting %s changed but is not stored; keeping its history in the tracker only", jobID))
+			posting.Unsynced = false
+		default:
+			log.Printf("Failed to update posting history: %v", err)
+			posting.Unsynced = true
+			result.failed++
+		}
+		tracked[jobID] = posting
+	}
+
+	if err := savePostingHistory(ctx, cfg, s3Service, tracked); err != nil {
+		return result, fmt.Errorf("save posting history: %w", err)
+	}
+	utils.Debug(fmt.Sprintf("Tracking %d posting(s); %d changed, %d closed this run", result.tracked, len(changed), result.closed))
+	if result.failed > 0 {
+		log.Printf("Posting history: %d stored job update(s) failed and will be retried next run", result.failed)
+	}
+	return result, nil
+}
+
+// trackPostings folds a run's coverage into tracked and returns the IDs of
+// postings that gained events. A posting missing from a search that found it
+// before is only closed when the search reached past its posted date, since
+// watermarks and page limits cut searches short.
+func trackPostings(tracked map[string]models.TrackedPosting, coverage []models.SearchCoverage, now time.Time) []string {
+	timestamp := now.UTC().Format(time.RFC3339)
+	seen := make(map[string]bool)
+	// oldest posted date each source and query search reached
+	reached := make(map[string]string)
+	changed := make(map[string]bool)
+
+	for _, search := range coverage {
+		key := search.Source + "\x00" + search.Query
+		for _, job := range search.Jobs {
+			if job.JobId == "" {
+				continue
+			}
+			if oldest, ok := reached[key]; !ok || job.PostedDate < oldest {
+				reached[key] = job.PostedDate
+			}
+			if seen[job.JobId] {
+				continue
+			}
+			seen[job.JobId] = true
+
+			posting, exists := tracked[job.JobId]
+			if !exists {
+				tracked[job.JobId] = newTrackedPosting(job, search.Query, timestamp)
+				continue
+			}
+			events := postingChanges(posting, job, timestamp)
+			if posting.ClosedAt != "" {
+				events = append(events, models.PostingEvent{Type: models.PostingEventReappeared, At: timestamp})
+				posting.ClosedAt = ""
+			}
+			if len(events) > 0 {
+				posting.History = append(posting.History, events...)
+				changed[job.JobId] = true
+			}
+			updated := newTrackedPosting(job, posting.Query, posting.FirstSeen)
+			updated.LastSeen = timestamp
+			updated.History = posting.History
+			updated.Unsynced = posting.Unsynced
+			tracked[job.JobId] = updated
+		}
+	}
+
+	for jobID, posting := range tracked {
+		if seen[jobID] {
+			continue
+		}
+		if posting.ClosedAt != "" {
+			if closedAt, err := time.Parse(time.RFC3339, posting.ClosedAt); err == nil && now.Sub(closedAt) > closedPostingRetention {
+				delete(tracked, jobID)
+			}
+			continue
+		}
+		oldest, searched := reached[posting.Source+"\x00"+posting.Query]
+		// postings dated on the oldest day may sit on a page the search never reached
+		if !searched || posting.PostedDate <= oldest {
+			continue
+		}
+		posting.ClosedAt = timestamp
+		posting.History = append(posting.History, models.PostingEvent{Type: models.PostingEventDisappeared, At: timestamp})
+		tracked[jobID] = posting
+		changed[jobID] = true
+	}
+
+	ids := make([]string, 0, len(changed))
+	for jobID := range changed {
+		ids = append(ids, jobID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func newTrackedPosting(job models.Job, query, firstSeen string) models.TrackedPosting {
+	return models.TrackedPosting{
+		JobId:       job.JobId,
+		Source:      job.Source,
+		Query:       query,
+		PostedDate:  job.PostedDate,
+		Title:       job.Title,
+		Company:     job.Company,
+		Location:    job.Location,
+		Salary:      job.Salary,
+		ClosingDate: job.ExpiresDate,
+		URL:         job.URL,
+		FirstSeen:   firstSeen,
+		LastSeen:    firstSeen,
+	}
+}
+
+// postingChanges lists the fields of job that differ from the tracked
+// posting, with closing-date changes called out as their own event type.
+func postingChanges(posting models.TrackedPosting, job models.Job, timestamp string) []models.PostingEvent {
+	var events []models.PostingEvent
+	if posting.ClosingDate != job.ExpiresDate {
+		events = append(events, models.PostingEvent{Type: models.PostingEventClosingDateChanged, At: timestamp, Field: "closingDate", Old: posting.ClosingDate, New: job.ExpiresDate})
+	}
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"title", posting.Title, job.Title},
+		{"company", posting.Company, job.Company},
+		{"location", posting.Location, job.Location},
+		{"salary", posting.Salary, job.Salary},
+		{"url", posting.URL, job.URL},
+	}
+	for _, field := range fields {
+		if field.old != field.new {
+			events = append(events, models.PostingEvent{Type: models.PostingEventChanged, At: timestamp, Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+	return events
+}
+
+func loadPostingHistory(ctx context.Context, cfg *config.Config, s3Service services.S3Client) (map[string]models.TrackedPosting, error) {
+	if s3Service != nil && cfg.PostingHistoryS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.PostingHistoryS3Key, cfg.PostingHistoryFile, postingHistoryLabel); err != nil {
+			return nil, err
+		}
+	}
+	return readPostingHistoryFile(cfg.PostingHistoryFile)
+}
+
+func savePostingHistory(ctx context.Context, cfg *config.Config, s3Service services.S3Client, tracked map[string]models.TrackedPosting) error {
+	if err := writePostingHistoryFile(cfg.PostingHistoryFile, tracked); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d tracked posting(s) to %s", len(tracked), cfg.PostingHistoryFile))
+	if s3Service != nil && cfg.PostingHistoryS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.PostingHistoryS3Key, cfg.PostingHistoryFile, postingHistoryLabel)
+	}
+	return nil
+}
+
+func readPostingHistoryFile(filename string) (map[string]models.TrackedPosting, error) {
+	tracked := make(map[string]models.TrackedPosting)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tracked, nil
+		}
+		return nil, fmt.Errorf("read posting history file: %w", err)
+	}
+	if len(data) == 0 {
+		return tracked, nil
+	}
+	if err := json.Unmarshal(data, &tracked); err != nil {
+		return nil, fmt.Errorf("decode posting history file: %w", err)
+	}
+	return tracked, nil
+}
+
+func writePostingHistoryFile(filename string, tracked map[string]models.TrackedPosting) error {
+	data, err := json.MarshalIndent(tracked, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode posting history: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write posting history file: %w", err)
+	}
+	return nil
+}
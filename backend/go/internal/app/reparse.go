@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+// ReparseOptions selects stored jobs to enrich again. StartDate and EndDate
+// bound PostedDate, inclusive, as YYYY-MM-DD.
+type ReparseOptions struct {
+	StartDate string
+	EndDate   string
+	// Versions keeps only jobs whose ExtractionVersion is listed; "" matches
+	// jobs stored before versions were recorded
+	Versions []string
+	// Outdated keeps only jobs not extracted by the current prompt
+	Outdated bool
+	DryRun   bool
+}
+
+// ReparseResult summarizes a reparse run.
+type ReparseResult struct {
+	Stats    models.JobStats
+	Scanned  int
+	Selected int
+	Updated  int64
+	// Skipped counts selected jobs with neither an archived nor a stored description
+	Skipped int64
+}
+
+// Reparse enriches the selected jobs again from their archived descriptions
+// and rewrites their enriched fields in place. A job whose parse fails keeps
+// its stored fields.
+func Reparse(ctx context.Context, cfg *config.Config, opts ReparseOptions) (*ReparseResult, error) {
+	store, awsConfig, err := openJobStore(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	jobs, err := store.QueryJobsByDateRange(ctx, opts.StartDate, opts.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("query jobs from %s to %s: %w", opts.StartDate, opts.EndDate, err)
+	}
+	var s3Service services.S3Client
+	if cfg.UseS3JobIDFile && cfg.JobIDsBucket != "" {
+		s3Service = services.NewS3Service(awsConfig)
+	}
+	archive := newDescriptionArchive(cfg, awsConfig, s3Service)
+	if archive == nil {
+		return nil, fmt.Errorf("reparse requires the description archive (USE_DESCRIPTION_ARCHIVE and DESCRIPTION_ARCHIVE_BUCKET)")
+	}
+	llmClient, err := services.NewLLMClient(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("configure LLM provider: %w", err)
+	}
+	stats := &models.JobStats{}
+	parser := services.NewParserService(llmClient, stats)
+
+	result := &ReparseResult{Scanned: len(jobs)}
+	var selected []models.Job
+	for _, job := range jobs {
+		if selectForReparse(job, opts) {
+			selected = append(selected, job)
+		}
+	}
+	result.Selected = len(selected)
+	utils.Debug(fmt.Sprintf("Selected %d of %d job(s) posted %s to %s for reparsing", result.Selected, result.Scanned, opts.StartDate, opts.EndDate))
+	if opts.DryRun {
+		return result, nil
+	}
+
+	reparseJobs(ctx, selected, parser, archive, store, stats, cfg.MaxConcurrency, result)
+	if err := recordUnknownSkills(ctx, cfg, s3Service, stats.Snapshot().UnknownSkills); err != nil {
+		return nil, err
+	}
+	result.Stats = stats.Snapshot()
+	return result, nil
+}
+
+// reparseJobs parses each job again, at most concurrency at a time, and
+// updates the ones that succeed.
+func reparseJobs(ctx context.Context, jobs []models.Job, parser services.ParserClient, archive services.DescriptionArchive,
+	store services.JobStore, stats *models.JobStats, concurrency int, result *ReparseResult) {
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(job models.Job) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			input, err := reparseInput(ctx, archive, job)
+			if err != nil {
+				log.Printf("Skipping reparse of job %s: %v", job.JobId, err)
+				atomic.AddInt64(&result.Skipped, 1)
+				return
+			}
+			atomic.AddInt64(&stats.ProcessedJobs, 1)
+			enhancedJob, err := parser.ParseWithStats(ctx, &input)
+			if err != nil {
+				log.Printf("Reparse failed for job %s; keeping stored fields: %v", job.JobId, err)
+				atomic.AddInt64(&stats.FailedJobs, 1)
+				return
+			}
+			atomic.AddInt64(&stats.SuccessfulJobs, 1)
+			if err := store.UpdateJobEnrichment(ctx, enhancedJob); err != nil {
+				log.Printf("Failed to update job %s: %v", job.JobId, err)
+				return
+			}
+			atomic.AddInt64(&result.Updated, 1)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// reparseInput restores the raw description of a stored job. Fallback rows
+// still hold theirs and are archived first so the update keeps a pointer.
+func reparseInput(ctx context.Context, archive services.DescriptionArchive, job models.Job) (models.Job, error) {
+	if job.Description != "" {
+		if job.S3Pointer == "" {
+			archiveDescription(ctx, archive, &job)
+		}
+		return job, nil
+	}
+	if job.S3Pointer == "" {
+		return job, fmt.Errorf("no archived description")
+	}
+	description, err := archive.Get(ctx, job.S3Pointer)
+	if err != nil {
+		return job, err
+	}
+	job.Description = description
+	return job, nil
+}
+
+func selectForReparse(job models.Job, opts ReparseOptions) bool {
+	if opts.Outdated && job.ExtractionVersion == services.EnrichmentPromptVersion() {
+		return false
+	}
+	if len(opts.Versions) == 0 {
+		return true
+	}
+	for _, version := range opts.Versions {
+		if job.ExtractionVersion == version {
+			return true
+		}
+	}
+	return false
+}
+
+// newDescriptionArchive returns nil when archiving is off or has no bucket.
+// s3Service is reused when the run already has one.
+func newDescriptionArchive(cfg *config.Config, awsConfig aws.Config, s3Service services.S3Client) services.DescriptionArchive {
+	if !cfg.UseDescriptionArchive || cfg.DescriptionBucket == "" {
+		return nil
+	}
+	if s3Service == nil {
+		s3Service = services.NewS3Service(awsConfig)
+	}
+	return services.NewDescriptionArchive(s3Service, cfg.DescriptionBucket, cfg.DescriptionPrefix)
+}
+
+// archiveDescription sets job.S3Pointer. A failed upload is logged and the
+// job continues without one.
+func archiveDescription(ctx context.Context, archive services.DescriptionArchive, job *models.Job) {
+	if archive == nil || job.Description == "" {
+		return
+	}
+	pointer, err := archive.Put(ctx, job)
+	if err != nil {
+		log.Printf("Failed to archive description: %v", err)
+		return
+	}
+	job.S3Pointer = pointer
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const skillReviewLabel = "skill review"
+
+// recordUnknownSkills adds a run's counts of skills missing from the taxonomy
+// to the stored review list, most frequent first, so maintainers know which
+// aliases to add to services/data/skills.csv next.
+func recordUnknownSkills(ctx context.Context, cfg *config.Config, s3Service services.S3Client, counts map[string]int64) error {
+	if !cfg.UseSkillReview || len(counts) == 0 {
+		return nil
+	}
+	reviews, err := loadSkillReview(ctx, cfg, s3Service)
+	if err != nil {
+		return fmt.Errorf("load skill review: %w", err)
+	}
+	reviews = mergeSkillReview(reviews, counts, time.Now())
+	if err := saveSkillReview(ctx, cfg, s3Service, reviews); err != nil {
+		return fmt.Errorf("save skill review: %w", err)
+	}
+	return nil
+}
+
+// mergeSkillReview adds counts to reviews and drops terms the taxonomy has
+// since learned.
+func mergeSkillReview(reviews []models.SkillReview, counts map[string]int64, seenAt time.Time) []models.SkillReview {
+	timestamp := seenAt.UTC().Format(time.RFC3339)
+	byTerm := make(map[string]int, len(reviews))
+	merged := make([]models.SkillReview, 0, len(reviews)+len(counts))
+	for _, review := range reviews {
+		if services.SkillCategory(review.Term) != "" {
+			continue
+		}
+		byTerm[review.Term] = len(merged)
+		merged = append(merged, review)
+	}
+	for term, count := range counts {
+		if i, ok := byTerm[term]; ok {
+			merged[i].Count += count
+			merged[i].LastSeen = timestamp
+			continue
+		}
+		byTerm[term] = len(merged)
+		merged = append(merged, models.SkillReview{Term: term, Count: count, FirstSeen: timestamp, LastSeen: timestamp})
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Count != merged[j].Count {
+			return merged[i].Count > merged[j].Count
+		}
+		return merged[i].Term < merged[j].Term
+	})
+	return merged
+}
+
+func loadSkillReview(ctx context.Context, cfg *config.Config, s3Service services.S3Client) ([]models.SkillReview, error) {
+	if s3Service != nil && cfg.SkillReviewS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.SkillReviewS3Key, cfg.SkillReviewFile, skillReviewLabel); err != nil {
+			return nil, err
+		}
+	}
+	return readSkillReviewFile(cfg.SkillReviewFile)
+}
+
+func saveSkillReview(ctx context.Context, cfg *config.Config, s3Service services.S3Client, reviews []models.SkillReview) error {
+	if err := writeSkillReviewFile(cfg.SkillReviewFile, reviews); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d skill(s) for review to %s", len(reviews), cfg.SkillReviewFile))
+	if s3Service != nil && cfg.SkillReviewS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.SkillReviewS3Key, cfg.SkillReviewFile, skillReviewLabel)
+	}
+	return nil
+}
+
+func readSkillReviewFile(filename string) ([]models.SkillReview, error) {
+	var reviews []models.SkillReview
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reviews, nil
+		}
+		return nil, fmt.Errorf("read skill review file: %w", err)
+	}
+	if len(data) == 0 {
+		return reviews, nil
+	}
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return nil, fmt.Errorf("decode skill review file: %w", err)
+	}
+	return reviews, nil
+}
+
+func writeSkillReviewFile(filename string, reviews []models.SkillReview) error {
+	data, err := json.MarshalIndent(reviews, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode skill review: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write skill review file: %w", err)
+	}
+	return nil
+}
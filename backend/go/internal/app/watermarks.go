@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/services"
+	"gopher-source/utils"
+)
+
+const scrapeWatermarksLabel = "scrape watermarks"
+
+// loadScrapeWatermarks syncs the watermark file from S3 when the job ID cache
+// bucket is configured, then reads it from disk.
+func loadScrapeWatermarks(ctx context.Context, cfg *config.Config, s3Service services.S3Client) (map[string]models.ScrapeWatermark, error) {
+	if s3Service != nil && cfg.WatermarksS3Key != "" {
+		if err := downloadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.WatermarksS3Key, cfg.WatermarksFilename, scrapeWatermarksLabel); err != nil {
+			return nil, err
+		}
+	}
+	return readWatermarksFile(cfg.WatermarksFilename)
+}
+
+func saveScrapeWatermarks(ctx context.Context, cfg *config.Config, s3Service services.S3Client, watermarks map[string]models.ScrapeWatermark) error {
+	if err := writeWatermarksFile(cfg.WatermarksFilename, watermarks); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Saved %d scrape watermark(s) to %s", len(watermarks), cfg.WatermarksFilename))
+	if s3Service != nil && cfg.WatermarksS3Key != "" {
+		return uploadCacheFile(ctx, s3Service, cfg.JobIDsBucket, cfg.WatermarksS3Key, cfg.WatermarksFilename, scrapeWatermarksLabel)
+	}
+	return nil
+}
+
+func readWatermarksFile(filename string) (map[string]models.ScrapeWatermark, error) {
+	watermarks := make(map[string]models.ScrapeWatermark)
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return watermarks, nil
+		}
+		return nil, fmt.Errorf("read watermarks file: %w", err)
+	}
+	if len(data) == 0 {
+		return watermarks, nil
+	}
+	if err := json.Unmarshal(data, &watermarks); err != nil {
+		return nil, fmt.Errorf("decode watermarks file: %w", err)
+	}
+	return watermarks, nil
+}
+
+func writeWatermarksFile(filename string, watermarks map[string]models.ScrapeWatermark) error {
+	data, err := json.MarshalIndent(watermarks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode watermarks: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o644); err != nil {
+		return fmt.Errorf("write watermarks file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,390 @@
+// Package eval scores the job extraction prompt against a labeled corpus so
+// prompt and schema edits can be compared with a saved baseline.
+package eval
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopher-source/models"
+	"gopher-source/services"
+)
+
+// Example is one labeled posting. Expected holds the fields a correct
+// extraction returns; a null MinYearsExperience expects null.
+type Example struct {
+	Job      models.Job                      `json:"job"`
+	Expected models.OpenAIJobParsingResponse `json:"expected"`
+}
+
+// Report is the field-level accuracy of one run over a corpus.
+type Report struct {
+	PromptVersion string                        `json:"promptVersion"`
+	Examples      int                           `json:"examples"`
+	Failures      int                           `json:"failures"`
+	YOE           YOEMetrics                    `json:"minYearsExperience"`
+	Categorical   map[string]CategoricalMetrics `json:"categorical"`
+	Lists         map[string]ListMetrics        `json:"lists"`
+	Usage         models.TokenUsage             `json:"usage"`
+	Mismatches    []Mismatch                    `json:"mismatches,omitempty"`
+}
+
+// YOEMetrics counts exact MinYearsExperience matches, where null must match
+// null, and matches within one year of a non-null label.
+type YOEMetrics struct {
+	Total       int     `json:"total"`
+	Exact       int     `json:"exact"`
+	Within1     int     `json:"within1"`
+	ExactRate   float64 `json:"exactRate"`
+	Within1Rate float64 `json:"within1Rate"`
+}
+
+// CategoricalMetrics keys Confusion by expected value, then predicted value.
+type CategoricalMetrics struct {
+	Total     int                       `json:"total"`
+	Correct   int                       `json:"correct"`
+	Accuracy  float64                   `json:"accuracy"`
+	Confusion map[string]map[string]int `json:"confusion"`
+}
+
+// ListMetrics are micro-averaged over every example, comparing entries
+// case-insensitively.
+type ListMetrics struct {
+	TruePositives  int     `json:"truePositives"`
+	FalsePositives int     `json:"falsePositives"`
+	FalseNegatives int     `json:"falseNegatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// Mismatch is one field the parser got wrong, kept for reviewing regressions.
+type Mismatch struct {
+	JobID    string `json:"jobId"`
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Got      string `json:"got"`
+}
+
+type categoricalField struct {
+	name      string
+	expected  func(models.OpenAIJobParsingResponse) string
+	predicted func(*models.Job) string
+}
+
+var categoricalFields = []categoricalField{
+	{"domain", func(r models.OpenAIJobParsingResponse) string { return r.Domain }, func(j *models.Job) string { return j.Domain }},
+	{"minDegree", func(r models.OpenAIJobParsingResponse) string { return r.MinDegree }, func(j *models.Job) string { return j.MinDegree }},
+	{"modality", func(r models.OpenAIJobParsingResponse) string { return r.Modality }, func(j *models.Job) string { return j.Modality }},
+	{"visaSponsorship", func(r models.OpenAIJobParsingResponse) string { return r.VisaSponsorship }, func(j *models.Job) string { return j.VisaSponsorship }},
+	{"securityClearance", func(r models.OpenAIJobParsingResponse) string { return r.SecurityClearance }, func(j *models.Job) string { return j.SecurityClearance }},
+	{"workAuthorization", func(r models.OpenAIJobParsingResponse) string { return r.WorkAuthorization }, func(j *models.Job) string { return j.WorkAuthorization }},
+	{"seniority", func(r models.OpenAIJobParsingResponse) string { return r.Seniority }, func(j *models.Job) string { return j.Seniority }},
+	{"isSoftwareEngineerRelated",
+		func(r models.OpenAIJobParsingResponse) string { return fmt.Sprint(r.IsSoftwareEngineerRelated) },
+		func(j *models.Job) string { return fmt.Sprint(j.IsSoftwareEngineerRelated) }},
+}
+
+// failedPrediction stands in for a job the parser could not extract, so
+// failures count against every field.
+const failedPrediction = "(parse failed)"
+
+// LoadCorpus reads a JSONL corpus, one Example per line. Blank lines are
+// skipped.
+func LoadCorpus(filename string) ([]Example, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open corpus: %w", err)
+	}
+	defer file.Close()
+
+	var examples []Example
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var example Example
+		if err := json.Unmarshal([]byte(text), &example); err != nil {
+			return nil, fmt.Errorf("decode corpus line %d: %w", line, err)
+		}
+		if example.Job.JobId == "" {
+			example.Job.JobId = fmt.Sprintf("line-%d", line)
+		}
+		examples = append(examples, example)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read corpus: %w", err)
+	}
+	return examples, nil
+}
+
+// Run parses every example with parser, at most concurrency at a time, and
+// scores the results. stats should be the one parser reports usage to.
+func Run(ctx context.Context, parser services.ParserClient, stats *models.JobStats, examples []Example, concurrency int) Report {
+	predictions := make([]*models.Job, len(examples))
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for i := range examples {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			job := examples[i].Job
+			if parsed, err := parser.ParseWithStats(ctx, &job); err == nil {
+				predictions[i] = parsed
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	report := Score(examples, predictions)
+	report.Usage = stats.TotalUsage()
+	return report
+}
+
+// Score compares predictions, aligned with examples, to their labels. A nil
+// prediction is a parse failure.
+func Score(examples []Example, predictions []*models.Job) Report {
+	report := Report{
+		PromptVersion: services.EnrichmentPromptVersion(),
+		Examples:      len(examples),
+		Categorical:   make(map[string]CategoricalMetrics),
+		Lists:         make(map[string]ListMetrics),
+	}
+	categorical := make(map[string]*CategoricalMetrics, len(categoricalFields))
+	for _, field := range categoricalFields {
+		categorical[field.name] = &CategoricalMetrics{Confusion: make(map[string]map[string]int)}
+	}
+	lists := map[string]*ListMetrics{"languages": {}, "technologies": {}}
+
+	for i, example := range examples {
+		expected := example.Expected
+		predicted := predictions[i]
+		jobID := example.Job.JobId
+		if predicted == nil {
+			report.Failures++
+		}
+
+		report.YOE.Total++
+		gotYOE := failedPrediction
+		if predicted != nil {
+			gotYOE = formatYOE(predicted.MinYearsExperience)
+			exact, within1 := compareYOE(expected.MinYearsExperience, predicted.MinYearsExperience)
+			if exact {
+				report.YOE.Exact++
+			}
+			if within1 {
+				report.YOE.Within1++
+			}
+		}
+		if wantYOE := formatYOE(expected.MinYearsExperience); wantYOE != gotYOE {
+			report.Mismatches = append(report.Mismatches, Mismatch{JobID: jobID, Field: "minYearsExperience", Expected: wantYOE, Got: gotYOE})
+		}
+
+		for _, field := range categoricalFields {
+			want, got := field.expected(expected), failedPrediction
+			if predicted != nil {
+				got = field.predicted(predicted)
+			}
+			if !recordCategorical(categorical[field.name], want, got) {
+				report.Mismatches = append(report.Mismatches, Mismatch{JobID: jobID, Field: field.name, Expected: want, Got: got})
+			}
+		}
+
+		var gotLanguages, gotTechnologies []string
+		if predicted != nil {
+			gotLanguages, gotTechnologies = predicted.Languages, predicted.Technologies
+		}
+		recordList(lists["languages"], expected.Languages, gotLanguages)
+		recordList(lists["technologies"], expected.Technologies, gotTechnologies)
+	}
+
+	report.YOE.ExactRate = ratio(report.YOE.Exact, report.YOE.Total)
+	report.YOE.Within1Rate = ratio(report.YOE.Within1, report.YOE.Total)
+	for field, metrics := range categorical {
+		metrics.Accuracy = ratio(metrics.Correct, metrics.Total)
+		report.Categorical[field] = *metrics
+	}
+	for field, metrics := range lists {
+		metrics.Precision = ratio(metrics.TruePositives, metrics.TruePositives+metrics.FalsePositives)
+		metrics.Recall = ratio(metrics.TruePositives, metrics.TruePositives+metrics.FalseNegatives)
+		if metrics.Precision+metrics.Recall > 0 {
+			metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+		}
+		report.Lists[field] = *metrics
+	}
+	return report
+}
+
+// Metrics flattens the report's rates into names such as "domain.accuracy"
+// and "languages.recall" for comparison with a baseline.
+func (r Report) Metrics() map[string]float64 {
+	metrics := map[string]float64{
+		"minYearsExperience.exact":   r.YOE.ExactRate,
+		"minYearsExperience.within1": r.YOE.Within1Rate,
+	}
+	for field, categorical := range r.Categorical {
+		metrics[field+".accuracy"] = categorical.Accuracy
+	}
+	for field, list := range r.Lists {
+		metrics[field+".precision"] = list.Precision
+		metrics[field+".recall"] = list.Recall
+		metrics[field+".f1"] = list.F1
+	}
+	return metrics
+}
+
+// MetricDelta compares one metric with the baseline.
+type MetricDelta struct {
+	Metric   string  `json:"metric"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Delta    float64 `json:"delta"`
+	// Regressed is set when the metric dropped by more than the tolerance
+	Regressed bool `json:"regressed"`
+}
+
+// Compare lists every metric in either report, sorted by name. A metric
+// missing from one side counts as zero there.
+func Compare(baseline, current Report, tolerance float64) []MetricDelta {
+	baselineMetrics, currentMetrics := baseline.Metrics(), current.Metrics()
+	names := make(map[string]bool)
+	for name := range baselineMetrics {
+		names[name] = true
+	}
+	for name := range currentMetrics {
+		names[name] = true
+	}
+
+	deltas := make([]MetricDelta, 0, len(names))
+	for _, name := range sortedKeys(names) {
+		delta := MetricDelta{Metric: name, Baseline: baselineMetrics[name], Current: currentMetrics[name]}
+		delta.Delta = delta.Current - delta.Baseline
+		delta.Regressed = delta.Delta < -tolerance
+		deltas = append(deltas, delta)
+	}
+	return deltas
+}
+
+// Regressions counts the deltas that dropped past the tolerance.
+func Regressions(deltas []MetricDelta) int {
+	count := 0
+	for _, delta := range deltas {
+		if delta.Regressed {
+			count++
+		}
+	}
+	return count
+}
+
+// LoadReport reads a report saved by WriteReport, usually the baseline.
+func LoadReport(filename string) (Report, error) {
+	var report Report
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return report, fmt.Errorf("read report: %w", err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		return report, fmt.Errorf("decode report: %w", err)
+	}
+	return report, nil
+}
+
+// WriteReport saves report as indented JSON so baselines diff cleanly in review.
+func WriteReport(filename string, report Report) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode report: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+func compareYOE(expected, predicted *int) (exact, within1 bool) {
+	if expected == nil || predicted == nil {
+		exact = expected == nil && predicted == nil
+		return exact, exact
+	}
+	diff := math.Abs(float64(*expected - *predicted))
+	return diff == 0, diff <= 1
+}
+
+func formatYOE(years *int) string {
+	if years == nil {
+		return "null"
+	}
+	return fmt.Sprint(*years)
+}
+
+func recordCategorical(metrics *CategoricalMetrics, expected, predicted string) bool {
+	metrics.Total++
+	row := metrics.Confusion[expected]
+	if row == nil {
+		row = make(map[string]int)
+		metrics.Confusion[expected] = row
+	}
+	row[predicted]++
+	if expected == predicted {
+		metrics.Correct++
+		return true
+	}
+	return false
+}
+
+func recordList(metrics *ListMetrics, expected, predicted []string) {
+	want := normalizeList(expected)
+	got := normalizeList(predicted)
+	for entry := range got {
+		if want[entry] {
+			metrics.TruePositives++
+		} else {
+			metrics.FalsePositives++
+		}
+	}
+	for entry := range want {
+		if !got[entry] {
+			metrics.FalseNegatives++
+		}
+	}
+}
+
+func normalizeList(entries []string) map[string]bool {
+	set := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if normalized := strings.ToLower(strings.TrimSpace(entry)); normalized != "" {
+			set[normalized] = true
+		}
+	}
+	return set
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
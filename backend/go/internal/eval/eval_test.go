@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"gopher-source/models"
+)
+
+type fakeParser struct {
+	responses map[string]*models.Job
+	stats     *models.JobStats
+}
+
+func (f *fakeParser) ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, error) {
+	f.stats.AddUsage(models.TokenUsage{Model: "gpt-4.1-nano", Calls: 1, PromptTokens: 100, CostUSD: 0.001})
+	if parsed, ok := f.responses[job.JobId]; ok {
+		return parsed, nil
+	}
+	return &models.Job{JobId: job.JobId, LowConfidence: true}, errors.New("llm unavailable")
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestScoreCountsFieldAccuracy(t *testing.T) {
+	examples := []Example{
+		{Job: models.Job{JobId: "a"}, Expected: models.OpenAIJobParsingResponse{
+			MinYearsExperience: intPtr(5), Domain: "Backend", MinDegree: "Bachelor's", Modality: "Hybrid",
+			Languages: []string{"Go"}, Technologies: []string{"AWS", "Kafka"}, IsSoftwareEngineerRelated: true,
+		}},
+		{Job: models.Job{JobId: "b"}, Expected: models.OpenAIJobParsingResponse{
+			Domain: "Front-End", MinDegree: "Unspecified", Modality: "Remote", Languages: []string{"TypeScript"},
+		}},
+		{Job: models.Job{JobId: "c"}, Expected: models.OpenAIJobParsingResponse{MinYearsExperience: intPtr(0), Domain: "Data"}},
+	}
+	predictions := []*models.Job{
+		{MinYearsExperience: intPtr(4), Domain: "Backend", MinDegree: "Bachelor's", Modality: "Hybrid",
+			Languages: []string{"go"}, Technologies: []string{"AWS", "Docker"}, IsSoftwareEngineerRelated: true},
+		{Domain: "Full-Stack", MinDegree: "Unspecified", Modality: "Remote", Languages: []string{"TypeScript"}},
+		nil,
+	}
+
+	report := Score(examples, predictions)
+
+	if report.Examples != 3 || report.Failures != 1 {
+		t.Fatalf("expected 3 examples with 1 failure, got %d and %d", report.Examples, report.Failures)
+	}
+	if report.YOE.Exact != 1 || report.YOE.Within1 != 2 {
+		t.Fatalf("expected 1 exact and 2 within-1 YOE matches, got %+v", report.YOE)
+	}
+	domain := report.Categorical["domain"]
+	if domain.Correct != 1 || domain.Total != 3 {
+		t.Fatalf("expected domain 1/3, got %+v", domain)
+	}
+	if domain.Confusion["Front-End"]["Full-Stack"] != 1 || domain.Confusion["Data"][failedPrediction] != 1 {
+		t.Fatalf("expected confusion to record misses, got %+v", domain.Confusion)
+	}
+	if modality := report.Categorical["modality"]; modality.Correct != 2 {
+		t.Fatalf("expected 2 modality matches, got %+v", modality)
+	}
+	languages := report.Lists["languages"]
+	if languages.TruePositives != 2 || languages.FalsePositives != 0 || languages.Recall != 1 {
+		t.Fatalf("expected case-insensitive language matches, got %+v", languages)
+	}
+	technologies := report.Lists["technologies"]
+	if technologies.Precision != 0.5 || technologies.Recall != 0.5 {
+		t.Fatalf("expected technologies precision and recall 0.5, got %+v", technologies)
+	}
+	if len(report.Mismatches) == 0 || report.Mismatches[0].JobID != "a" || report.Mismatches[0].Field != "minYearsExperience" {
+		t.Fatalf("expected the first mismatch to be a's YOE, got %+v", report.Mismatches)
+	}
+}
+
+func TestCompareFlagsRegressionsPastTolerance(t *testing.T) {
+	baseline := Report{
+		YOE:         YOEMetrics{ExactRate: 0.8, Within1Rate: 0.9},
+		Categorical: map[string]CategoricalMetrics{"domain": {Accuracy: 0.9}},
+	}
+	current := Report{
+		YOE:         YOEMetrics{ExactRate: 0.79, Within1Rate: 0.95},
+		Categorical: map[string]CategoricalMetrics{"domain": {Accuracy: 0.8}},
+		Lists:       map[string]ListMetrics{"languages": {Precision: 1, Recall: 1, F1: 1}},
+	}
+
+	deltas := Compare(baseline, current, 0.02)
+
+	regressed := make(map[string]bool)
+	for _, delta := range deltas {
+		if delta.Regressed {
+			regressed[delta.Metric] = true
+		}
+	}
+	if len(regressed) != 1 || !regressed["domain.accuracy"] {
+		t.Fatalf("expected only domain.accuracy to regress, got %v", regressed)
+	}
+	if Regressions(deltas) != 1 {
+		t.Fatalf("expected 1 regression, got %d", Regressions(deltas))
+	}
+	if len(deltas) != 6 || deltas[0].Metric != "domain.accuracy" {
+		t.Fatalf("expected metrics from both reports sorted by name, got %+v", deltas)
+	}
+}
+
+func TestRunScoresParserOutputAndUsage(t *testing.T) {
+	stats := &models.JobStats{}
+	parser := &fakeParser{
+		stats: stats,
+		responses: map[string]*models.Job{
+			"a": {Domain: "Backend"},
+		},
+	}
+	examples := []Example{
+		{Job: models.Job{JobId: "a"}, Expected: models.OpenAIJobParsingResponse{Domain: "Backend"}},
+		{Job: models.Job{JobId: "b"}, Expected: models.OpenAIJobParsingResponse{Domain: "Data"}},
+	}
+
+	report := Run(context.Background(), parser, stats, examples, 2)
+
+	if report.Failures != 1 || report.Categorical["domain"].Correct != 1 {
+		t.Fatalf("expected one failure and one domain match, got %+v", report)
+	}
+	if report.Usage.Calls != 2 || report.PromptVersion == "" {
+		t.Fatalf("expected usage for both calls and a prompt version, got %+v", report)
+	}
+}
+
+func TestReportRoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "baseline.json")
+	report := Score(
+		[]Example{{Job: models.Job{JobId: "a"}, Expected: models.OpenAIJobParsingResponse{Domain: "Backend"}}},
+		[]*models.Job{{Domain: "Backend"}},
+	)
+	if err := WriteReport(filename, report); err != nil {
+		t.Fatalf("write report: %v", err)
+	}
+	loaded, err := LoadReport(filename)
+	if err != nil {
+		t.Fatalf("load report: %v", err)
+	}
+	if deltas := Compare(report, loaded, 0); Regressions(deltas) != 0 {
+		t.Fatalf("expected a reloaded report to match, got %+v", deltas)
+	}
+}
+
+func TestLoadCorpusReadsGoldenSet(t *testing.T) {
+	examples, err := LoadCorpus(filepath.Join("..", "..", "testdata", "eval", "golden.jsonl"))
+	if err != nil {
+		t.Fatalf("load corpus: %v", err)
+	}
+	if len(examples) == 0 {
+		t.Fatal("expected labeled examples")
+	}
+	for _, example := range examples {
+		if example.Job.JobId == "" || example.Job.Description == "" || example.Expected.Domain == "" || example.Expected.VisaSponsorship == "" ||
+			example.Expected.SecurityClearance == "" || example.Expected.WorkAuthorization == "" || example.Expected.Seniority == "" {
+			t.Fatalf("expected every example to carry a job and labels, got %+v", example)
+		}
+	}
+}
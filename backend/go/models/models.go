@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"sync/atomic"
 	"time"
@@ -12,6 +13,7 @@ import (
 type Job struct {
 	ID                        uint     `json:"id,omitempty"`
 	JobId                     string   `json:"jobId"`
+	Source                    string   `json:"source,omitempty"`
 	Title                     string   `json:"title"`
 	Company                   string   `json:"company"`
 	Location                  string   `json:"location"`
@@ -30,6 +32,77 @@ type Job struct {
 	Languages                 []string `json:"languages,omitempty"`
 	Technologies              []string `json:"technologies,omitempty"`
 	IsSoftwareEngineerRelated bool     `json:"IsSoftwareEngineerRelated"`
+	// LowConfidence marks fields guessed by the rule-based fallback rather
+	// than the LLM; such jobs keep Description so they can be re-enriched.
+	LowConfidence bool `json:"lowConfidence,omitempty"`
+	// ExtractionVersion names the prompt and schema that produced the
+	// enriched fields, so outdated rows can be selected for reparsing.
+	ExtractionVersion string `json:"extractionVersion,omitempty"`
+	// Provenance records how the enriched fields were produced
+	Provenance *Provenance `json:"provenance,omitempty"`
+	// SalaryMin through SalaryDOE structure the pay behind Salary, or a range
+	// the description states when the listing has none. SalarySource says which.
+	SalaryMin       *float64 `json:"salaryMin,omitempty"`
+	SalaryMax       *float64 `json:"salaryMax,omitempty"`
+	SalaryCurrency  string   `json:"salaryCurrency,omitempty"`
+	SalaryPeriod    string   `json:"salaryPeriod,omitempty"`
+	SalaryAnnualMin *float64 `json:"salaryAnnualMin,omitempty"`
+	SalaryAnnualMax *float64 `json:"salaryAnnualMax,omitempty"`
+	// SalaryDOE marks pay listed as depending on experience
+	SalaryDOE    bool   `json:"salaryDoe,omitempty"`
+	SalarySource string `json:"salarySource,omitempty"`
+	// City through MetroArea are Location parsed and resolved against the
+	// bundled US places gazetteer; Country is an ISO 3166 code.
+	City       string   `json:"city,omitempty"`
+	State      string   `json:"state,omitempty"`
+	PostalCode string   `json:"postalCode,omitempty"`
+	Country    string   `json:"country,omitempty"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+	MetroArea  string   `json:"metroArea,omitempty"`
+	// VisaSponsorship, SecurityClearance and WorkAuthorization hold the
+	// eligibility requirements the description states, using the enum values
+	// of the matching OpenAIJobParsingResponse fields.
+	VisaSponsorship   string `json:"visaSponsorship,omitempty"`
+	SecurityClearance string `json:"securityClearance,omitempty"`
+	WorkAuthorization string `json:"workAuthorization,omitempty"`
+	// Seniority is the level the LLM classified the role at. QualityFlags
+	// names checks the enriched fields failed, such as a title that names a
+	// different level.
+	Seniority    string   `json:"seniority,omitempty"`
+	QualityFlags []string `json:"qualityFlags,omitempty"`
+	// Version counts the stored revisions of the posting, starting at 1;
+	// SourceHash fingerprints the posting fields that revision was built from.
+	Version    int    `json:"version,omitempty"`
+	SourceHash string `json:"sourceHash,omitempty"`
+	// ClosedAt is when the posting disappeared from search results; History
+	// lists the changes observed since it was first stored.
+	ClosedAt string         `json:"closedAt,omitempty"`
+	History  []PostingEvent `json:"history,omitempty"`
+}
+
+// Provenance describes one extraction. Model and the hashes are empty for
+// rule-based fallback jobs, and Model for responses reused from cache.
+type Provenance struct {
+	Model      string `json:"model,omitempty"`
+	PromptHash string `json:"promptHash,omitempty"`
+	SchemaHash string `json:"schemaHash,omitempty"`
+	// RetryUsed is set when MinYearsExperience came back null and was asked for again
+	RetryUsed        bool   `json:"retryUsed,omitempty"`
+	Cached           bool   `json:"cached,omitempty"`
+	LatencyMs        int64  `json:"latencyMs"`
+	PromptTokens     int64  `json:"promptTokens"`
+	CompletionTokens int64  `json:"completionTokens"`
+	ExtractedAt      string `json:"extractedAt"`
+}
+
+// RecordCall adds the tokens of one LLM call.
+func (p *Provenance) RecordCall(usage TokenUsage) {
+	if usage.Model != "" {
+		p.Model = usage.Model
+	}
+	p.PromptTokens += usage.PromptTokens
+	p.CompletionTokens += usage.CompletionTokens
 }
 
 func (j *Job) ToDynamoDBItem() (map[string]types.AttributeValue, error) {
@@ -50,28 +123,215 @@ type OpenAIJobParsingResponse struct {
 	Domain                    string   `json:"Domain" jsonschema:"enum=Backend,enum=Full-Stack,enum=AI/ML,enum=Data,enum=QA,enum=Front-End,enum=Security,enum=DevOps,enum=Mobile,enum=Site Reliability,enum=Networking,enum=Embedded Systems,enum=Gaming,enum=Financial,enum=Other" jsonschema_description:"Technical domain. If description focuses on server-side or microservices development, choose 'Backend'"`
 	Languages                 []string `json:"Languages" jsonschema_description:"Programming languages mentioned in the job. Only include programming languages, not spoken languages like English or Spanish"`
 	Technologies              []string `json:"Technologies" jsonschema_description:"Software tools, frameworks, databases, and technologies mentioned in the job"`
+	SalaryMin                 *float64 `json:"SalaryMin" jsonschema:"nullable,minimum=0" jsonschema_description:"Lowest pay the description states, as a plain number (80000 for '$80k'). Null when no pay is stated"`
+	SalaryMax                 *float64 `json:"SalaryMax" jsonschema:"nullable,minimum=0" jsonschema_description:"Highest pay the description states; the same as SalaryMin for a single figure. Null when no pay is stated"`
+	SalaryCurrency            string   `json:"SalaryCurrency" jsonschema_description:"ISO 4217 code of the stated pay, such as USD. Empty when no pay is stated"`
+	SalaryPeriod              string   `json:"SalaryPeriod" jsonschema:"enum=hour,enum=day,enum=week,enum=month,enum=year,enum=unspecified" jsonschema_description:"What the stated pay covers. Use 'unspecified' when no pay is stated or the period is unclear"`
+	VisaSponsorship           string   `json:"VisaSponsorship" jsonschema:"enum=Available,enum=Not Available,enum=Unspecified" jsonschema_description:"Whether the employer sponsors work visas for this role. Use 'Unspecified' when the description does not say"`
+	SecurityClearance         string   `json:"SecurityClearance" jsonschema:"enum=Public Trust,enum=Secret,enum=Top Secret,enum=TS/SCI,enum=Unspecified" jsonschema_description:"Lowest security clearance the role requires, whether held already or obtained after hire. Use 'Unspecified' when no clearance is required"`
+	WorkAuthorization         string   `json:"WorkAuthorization" jsonschema:"enum=U.S. Citizen,enum=U.S. Person,enum=Authorized to Work,enum=Unspecified" jsonschema_description:"Strictest work-authorization requirement stated: 'U.S. Citizen' for citizenship, 'U.S. Person' for citizens or permanent residents (as under ITAR or EAR), 'Authorized to Work' for any existing authorization to work in the country. Use 'Unspecified' when none is stated"`
+	Seniority                 string   `json:"Seniority" jsonschema:"enum=Intern,enum=Entry,enum=Mid,enum=Senior,enum=Staff,enum=Principal,enum=Manager,enum=Director" jsonschema_description:"Level the role hires at, from the title and responsibilities. Manager and Director are for roles that manage people or teams; classify product, project and program managers by their scope. Use 'Mid' when nothing indicates a level"`
 	IsSoftwareEngineerRelated bool     `json:"IsSoftwareEngineerRelated" jsonschema_description:"Whether the job is primarily related to software engineering. Set to true only for roles that primarily involve coding or deep technical system design (Software Engineer, Developer, Data Scientist, ML Engineer, DevOps Engineer, SRE, QA Engineer). Set to false for Project Manager, Product Manager, Designer, Sales Engineer, IT Support, etc."`
 }
 
+// ScrapeWatermark records the newest posting a source returned for a query so
+// later runs can stop paging once they reach it.
+type ScrapeWatermark struct {
+	LastPostingDate string `json:"lastPostingDate"`
+	LastRecordID    string `json:"lastRecordId"`
+	UpdatedAt       string `json:"updatedAt,omitempty"`
+}
+
+func (w ScrapeWatermark) IsZero() bool {
+	return w.LastPostingDate == "" && w.LastRecordID == ""
+}
+
+// Covers reports whether a posting is at or behind the watermark. Posting
+// times are ISO-8601 strings, so lexical order matches chronological order.
+func (w ScrapeWatermark) Covers(postingDate, recordID string) bool {
+	if w.IsZero() || postingDate == "" {
+		return false
+	}
+	if postingDate == w.LastPostingDate {
+		return recordID == w.LastRecordID
+	}
+	return postingDate < w.LastPostingDate
+}
+
+// ScrapeCursor marks where an interrupted source search stopped. State is
+// opaque to everything but the source that produced it; Newest carries the
+// watermark seen on the pages fetched before the interruption.
+type ScrapeCursor struct {
+	Newest ScrapeWatermark `json:"newest"`
+	State  json.RawMessage `json:"state,omitempty"`
+}
+
+func (c ScrapeCursor) IsZero() bool {
+	return len(c.State) == 0
+}
+
+// ScrapeCheckpoint records a run that stopped at its deadline. Cursors are
+// keyed like scrape watermarks; PendingJobs were scraped but never parsed.
+type ScrapeCheckpoint struct {
+	CreatedAt        string                  `json:"createdAt,omitempty"`
+	RequestID        string                  `json:"requestId,omitempty"`
+	CompletedQueries []string                `json:"completedQueries,omitempty"`
+	Cursors          map[string]ScrapeCursor `json:"cursors,omitempty"`
+	PendingJobs      []Job                   `json:"pendingJobs,omitempty"`
+}
+
+func (c ScrapeCheckpoint) IsEmpty() bool {
+	return len(c.CompletedQueries) == 0 && len(c.Cursors) == 0 && len(c.PendingJobs) == 0
+}
+
+// EnrichmentBatch tracks jobs submitted to the OpenAI Batch API until their
+// results are stored. Jobs keep their descriptions so results can be applied
+// without scraping again.
+type EnrichmentBatch struct {
+	ID           string `json:"id"`
+	InputFileID  string `json:"inputFileId"`
+	OutputFileID string `json:"outputFileId,omitempty"`
+	ErrorFileID  string `json:"errorFileId,omitempty"`
+	Status       string `json:"status"`
+	SubmittedAt  string `json:"submittedAt"`
+	Jobs         []Job  `json:"jobs"`
+}
+
+// DeadLetter is a job whose enrichment failed, kept with the original
+// description so cmd/retry-failed can parse it again.
+type DeadLetter struct {
+	Job           Job    `json:"job"`
+	Error         string `json:"error"`
+	Attempts      int    `json:"attempts"`
+	FirstFailedAt string `json:"firstFailedAt"`
+	LastFailedAt  string `json:"lastFailedAt"`
+}
+
+// SkillReview is a Languages or Technologies term missing from the skill
+// taxonomy, counted across runs until it is added.
+type SkillReview struct {
+	Term      string `json:"term"`
+	Count     int64  `json:"count"`
+	FirstSeen string `json:"firstSeen"`
+	LastSeen  string `json:"lastSeen"`
+}
+
+// Types of PostingEvent.
+const (
+	PostingEventChanged            = "changed"
+	PostingEventClosingDateChanged = "closing_date_changed"
+	PostingEventDisappeared        = "disappeared"
+	PostingEventReappeared         = "reappeared"
+)
+
+// PostingEvent is one change observed in a posting between runs. Field, Old
+// and New describe changed fields and closing dates.
+type PostingEvent struct {
+	Type  string `json:"type"`
+	At    string `json:"at"`
+	Field string `json:"field,omitempty"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// TrackedPosting is a listing as search results last showed it, kept across
+// runs so edits and removals are noticed after its ID is in the job ID cache.
+// Query is the key of the plan query that first found it.
+type TrackedPosting struct {
+	JobId       string         `json:"jobId"`
+	Source      string         `json:"source"`
+	Query       string         `json:"query"`
+	PostedDate  string         `json:"postedDate"`
+	Title       string         `json:"title"`
+	Company     string         `json:"company"`
+	Location    string         `json:"location"`
+	Salary      string         `json:"salary"`
+	ClosingDate string         `json:"closingDate,omitempty"`
+	URL         string         `json:"url"`
+	FirstSeen   string         `json:"firstSeen"`
+	LastSeen    string         `json:"lastSeen"`
+	ClosedAt    string         `json:"closedAt,omitempty"`
+	History     []PostingEvent `json:"history,omitempty"`
+	// Unsynced marks history the stored job has not received yet because
+	// its update failed; the next run retries it
+	Unsynced bool `json:"unsynced,omitempty"`
+}
+
+// SearchCoverage lists every listing one search of a source returned for a
+// plan query, including those skipped as already processed. Only searches
+// that ran from the first page without error are covered.
+type SearchCoverage struct {
+	Source string
+	Query  string
+	Jobs   []Job
+}
+
+// QueryStats counts what one scrape plan query contributed to a run. Listings
+// found by an earlier query in the same run count as skipped.
+type QueryStats struct {
+	Query       string `json:"query"`
+	Listings    int64  `json:"listings"`
+	NewJobs     int64  `json:"newJobs"`
+	SkippedJobs int64  `json:"skippedJobs"`
+	Error       string `json:"error,omitempty"`
+}
+
 type JobStats struct {
-	TotalJobs      int64
-	ProcessedJobs  int64
-	SuccessfulJobs int64
-	FailedJobs     int64
-	UnrelatedJobs  int64
-	SkippedJobs    int64
+	TotalJobs           int64
+	ProcessedJobs       int64
+	SuccessfulJobs      int64
+	FailedJobs          int64
+	UnrelatedJobs       int64
+	SkippedJobs         int64
+	EnrichmentCacheHits int64
+	YOERetries          int64
+	FallbackJobs        int64
+	StoredJobs          int64 // by batched writes, like the Store counters; update through RecordWrites
+	StoreCalls          int64
+	StoreRetries        int64
+	StoreNanos          int64
+	StoreFailures       int64                 // enriched jobs whose write failed; they are dead-lettered
+	Usage               map[string]TokenUsage // by model; update through AddUsage
+	UnknownSkills       map[string]int64      // by term; update through AddUnknownSkills
 }
 
 // snapshot captures a point-in-time copy of the aggregated counters
 func (s *JobStats) Snapshot() JobStats {
 	return JobStats{
-		TotalJobs:      atomic.LoadInt64(&s.TotalJobs),
-		ProcessedJobs:  atomic.LoadInt64(&s.ProcessedJobs),
-		SuccessfulJobs: atomic.LoadInt64(&s.SuccessfulJobs),
-		FailedJobs:     atomic.LoadInt64(&s.FailedJobs),
-		UnrelatedJobs:  atomic.LoadInt64(&s.UnrelatedJobs),
-		SkippedJobs:    atomic.LoadInt64(&s.SkippedJobs),
+		TotalJobs:           atomic.LoadInt64(&s.TotalJobs),
+		ProcessedJobs:       atomic.LoadInt64(&s.ProcessedJobs),
+		SuccessfulJobs:      atomic.LoadInt64(&s.SuccessfulJobs),
+		FailedJobs:          atomic.LoadInt64(&s.FailedJobs),
+		UnrelatedJobs:       atomic.LoadInt64(&s.UnrelatedJobs),
+		SkippedJobs:         atomic.LoadInt64(&s.SkippedJobs),
+		EnrichmentCacheHits: atomic.LoadInt64(&s.EnrichmentCacheHits),
+		YOERetries:          atomic.LoadInt64(&s.YOERetries),
+		FallbackJobs:        atomic.LoadInt64(&s.FallbackJobs),
+		StoredJobs:          atomic.LoadInt64(&s.StoredJobs),
+		StoreCalls:          atomic.LoadInt64(&s.StoreCalls),
+		StoreRetries:        atomic.LoadInt64(&s.StoreRetries),
+		StoreNanos:          atomic.LoadInt64(&s.StoreNanos),
+		StoreFailures:       atomic.LoadInt64(&s.StoreFailures),
+		Usage:               s.usageSnapshot(),
+		UnknownSkills:       s.unknownSkillsSnapshot(),
+	}
+}
+
+// RecordWrites adds one batched write of stored new jobs, taking calls
+// requests of which retries resent unprocessed items.
+func (s *JobStats) RecordWrites(stored, calls, retries int, elapsed time.Duration) {
+	atomic.AddInt64(&s.StoredJobs, int64(stored))
+	atomic.AddInt64(&s.StoreCalls, int64(calls))
+	atomic.AddInt64(&s.StoreRetries, int64(retries))
+	atomic.AddInt64(&s.StoreNanos, int64(elapsed))
+}
+
+// StoreWritesPerSecond is the jobs stored per second spent writing.
+func (s JobStats) StoreWritesPerSecond() float64 {
+	if s.StoreNanos <= 0 {
+		return 0
 	}
+	return float64(s.StoredJobs) / time.Duration(s.StoreNanos).Seconds()
 }
 
 func (s *JobStats) PrintSummary(executionTime time.Duration) {
@@ -88,7 +348,18 @@ func (s *JobStats) PrintSummary(executionTime time.Duration) {
 	fmt.Printf("   Jobs Skipped (cached): %d\n", skippedJobs)
 	fmt.Printf("   Unrelated Jobs: %d\n", snapshot.UnrelatedJobs)
 	fmt.Printf("   Successfully Parsed by OpenAI: %d\n", successfulJobs)
-	fmt.Printf("   Failed to Parse: %d\n", snapshot.FailedJobs)
+	fmt.Printf("   Enrichment Cache Hits: %d\n", snapshot.EnrichmentCacheHits)
+	fmt.Printf("   Failed to Parse: %d (%d stored from fallback rules)\n", snapshot.FailedJobs, snapshot.FallbackJobs)
+	if snapshot.StoreCalls > 0 {
+		fmt.Printf("   Stored in Batches: %d in %d call(s), %d retried, %.1f jobs/s\n",
+			snapshot.StoredJobs, snapshot.StoreCalls, snapshot.StoreRetries, snapshot.StoreWritesPerSecond())
+	}
+	if snapshot.StoreFailures > 0 {
+		fmt.Printf("   Failed to Store: %d (queued for retry)\n", snapshot.StoreFailures)
+	}
+	if len(snapshot.UnknownSkills) > 0 {
+		fmt.Printf("   Skills Missing from the Taxonomy: %d\n", len(snapshot.UnknownSkills))
+	}
 
 	if processedJobs > 0 {
 		fmt.Printf("   Success Rate: %.1f%%\n", float64(successfulJobs)/float64(processedJobs)*100)
@@ -98,4 +369,30 @@ func (s *JobStats) PrintSummary(executionTime time.Duration) {
 	if executionTime.Seconds() > 0 {
 		fmt.Printf("   Jobs per Second: %.2f\n", float64(handledJobs)/executionTime.Seconds())
 	}
+
+	usageByModel := snapshot.UsageByModel()
+	if len(usageByModel) == 0 {
+		return
+	}
+	fmt.Printf("\n💸 LLM Usage (%d YOE retries):\n", snapshot.YOERetries)
+	for _, usage := range usageByModel {
+		fmt.Printf("   %s: %d calls, %d prompt (%d cached) + %d completion tokens, ~$%.4f\n",
+			usage.Model, usage.Calls, usage.PromptTokens, usage.CachedTokens, usage.CompletionTokens, usage.CostUSD)
+	}
+	if len(usageByModel) > 1 {
+		fmt.Printf("   Total: ~$%.4f\n", snapshot.TotalUsage().CostUSD)
+	}
+}
+
+func PrintQueryStats(queryStats []QueryStats) {
+	if len(queryStats) == 0 {
+		return
+	}
+	fmt.Printf("\n🔎 Scrape Plan Queries:\n")
+	for _, query := range queryStats {
+		fmt.Printf("   %s: %d listings, %d new, %d skipped\n", query.Query, query.Listings, query.NewJobs, query.SkippedJobs)
+		if query.Error != "" {
+			fmt.Printf("      error: %s\n", query.Error)
+		}
+	}
 }
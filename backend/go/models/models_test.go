@@ -28,3 +28,52 @@ func TestJobJSONOmitsUnknownMinYearsExperience(t *testing.T) {
 		t.Fatalf("expected unknown min years experience to be omitted, got %s", b)
 	}
 }
+
+func TestScrapeWatermarkCovers(t *testing.T) {
+	watermark := ScrapeWatermark{LastPostingDate: "2026-08-18T01:40:56.000Z", LastRecordID: "record-123"}
+	cases := []struct {
+		name        string
+		postingDate string
+		recordID    string
+		want        bool
+	}{
+		{"newer", "2026-08-19T00:00:00.000Z", "record-999", false},
+		{"sameRecord", "2026-08-18T01:40:56.000Z", "record-123", true},
+		{"sameTimeOtherRecord", "2026-08-18T01:40:56.000Z", "record-124", false},
+		{"older", "2026-08-17T23:59:59.000Z", "record-001", true},
+		{"missingDate", "", "record-001", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := watermark.Covers(tc.postingDate, tc.recordID); got != tc.want {
+				t.Fatalf("Covers(%q, %q) = %v, want %v", tc.postingDate, tc.recordID, got, tc.want)
+			}
+		})
+	}
+
+	if (ScrapeWatermark{}).Covers("2000-01-01T00:00:00Z", "any") {
+		t.Fatal("expected zero watermark to cover nothing")
+	}
+}
+
+func TestJobStatsAddUsageAggregatesByModel(t *testing.T) {
+	stats := &JobStats{}
+	stats.AddUsage(TokenUsage{Model: "gpt-4.1-nano", Calls: 1, PromptTokens: 1000, CachedTokens: 200, CompletionTokens: 100, CostUSD: 0.001})
+	stats.AddUsage(TokenUsage{Model: "gpt-4.1-nano", Calls: 1, PromptTokens: 500, CompletionTokens: 50, CostUSD: 0.0005})
+	stats.AddUsage(TokenUsage{Model: "claude-haiku-4-5", Calls: 1, PromptTokens: 10, CompletionTokens: 5, CostUSD: 0.002})
+	stats.AddUsage(TokenUsage{})
+
+	snapshot := stats.Snapshot()
+	stats.AddUsage(TokenUsage{Model: "gpt-4.1-nano", Calls: 1})
+	byModel := snapshot.UsageByModel()
+	if len(byModel) != 2 || byModel[0].Model != "claude-haiku-4-5" || byModel[1].Model != "gpt-4.1-nano" {
+		t.Fatalf("unexpected models: %+v", byModel)
+	}
+	if nano := byModel[1]; nano.Calls != 2 || nano.PromptTokens != 1500 || nano.CachedTokens != 200 || nano.CompletionTokens != 150 {
+		t.Fatalf("unexpected gpt-4.1-nano totals (snapshot should not see later calls): %+v", nano)
+	}
+	total := snapshot.TotalUsage()
+	if total.Calls != 3 || total.Model != "" || total.CostUSD < 0.00349 || total.CostUSD > 0.00351 {
+		t.Fatalf("unexpected total: %+v", total)
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "sync"
+
+// skillsMu guards JobStats.UnknownSkills for every JobStats.
+var skillsMu sync.Mutex
+
+// AddUnknownSkills counts terms missing from the skill taxonomy.
+func (s *JobStats) AddUnknownSkills(terms []string) {
+	if len(terms) == 0 {
+		return
+	}
+	skillsMu.Lock()
+	defer skillsMu.Unlock()
+	if s.UnknownSkills == nil {
+		s.UnknownSkills = make(map[string]int64)
+	}
+	for _, term := range terms {
+		s.UnknownSkills[term]++
+	}
+}
+
+func (s *JobStats) unknownSkillsSnapshot() map[string]int64 {
+	skillsMu.Lock()
+	defer skillsMu.Unlock()
+	if s.UnknownSkills == nil {
+		return nil
+	}
+	counts := make(map[string]int64, len(s.UnknownSkills))
+	for term, count := range s.UnknownSkills {
+		counts[term] = count
+	}
+	return counts
+}
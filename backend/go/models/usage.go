@@ -0,0 +1,79 @@
+package models
+
+import (
+	"sort"
+	"sync"
+)
+
+// TokenUsage totals what calls to one model consumed. PromptTokens includes
+// CachedTokens; CostUSD is an estimate from the provider's list prices.
+type TokenUsage struct {
+	Model            string  `json:"model"`
+	Calls            int64   `json:"calls"`
+	PromptTokens     int64   `json:"promptTokens"`
+	CachedTokens     int64   `json:"cachedTokens"`
+	CompletionTokens int64   `json:"completionTokens"`
+	CostUSD          float64 `json:"estimatedCostUsd"`
+}
+
+func (u *TokenUsage) add(other TokenUsage) {
+	u.Calls += other.Calls
+	u.PromptTokens += other.PromptTokens
+	u.CachedTokens += other.CachedTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.CostUSD += other.CostUSD
+}
+
+// usageMu guards JobStats.Usage for every JobStats; one lock per LLM call is
+// cheap next to the call itself and keeps JobStats copyable.
+var usageMu sync.Mutex
+
+// AddUsage folds one call's usage into the per-model totals.
+func (s *JobStats) AddUsage(usage TokenUsage) {
+	if usage.Calls == 0 {
+		return
+	}
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	if s.Usage == nil {
+		s.Usage = make(map[string]TokenUsage)
+	}
+	total := s.Usage[usage.Model]
+	total.Model = usage.Model
+	total.add(usage)
+	s.Usage[usage.Model] = total
+}
+
+// UsageByModel returns the per-model totals sorted by model name.
+func (s *JobStats) UsageByModel() []TokenUsage {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	models := make([]TokenUsage, 0, len(s.Usage))
+	for _, usage := range s.Usage {
+		models = append(models, usage)
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].Model < models[j].Model })
+	return models
+}
+
+// TotalUsage sums usage across models; its Model is empty.
+func (s *JobStats) TotalUsage() TokenUsage {
+	var total TokenUsage
+	for _, usage := range s.UsageByModel() {
+		total.add(usage)
+	}
+	return total
+}
+
+func (s *JobStats) usageSnapshot() map[string]TokenUsage {
+	usageMu.Lock()
+	defer usageMu.Unlock()
+	if s.Usage == nil {
+		return nil
+	}
+	usage := make(map[string]TokenUsage, len(s.Usage))
+	for model, total := range s.Usage {
+		usage[model] = total
+	}
+	return usage
+}
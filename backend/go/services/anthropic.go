@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+const (
+	anthropicDefaultBaseURL = "https://api.anthropic.com"
+	anthropicDefaultModel   = "claude-haiku-4-5"
+	anthropicAPIVersion     = "2023-06-01"
+	anthropicMaxTokens      = 4096
+	// the schema is offered as the only tool, so the tool input is the
+	// structured response
+	anthropicToolName = "record_job_parsing_response"
+)
+
+type anthropicClientImpl struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+}
+
+type anthropicMessagesRequest struct {
+	Model      string              `json:"model"`
+	MaxTokens  int                 `json:"max_tokens"`
+	System     string              `json:"system"`
+	Messages   []anthropicMessage  `json:"messages"`
+	Tools      []anthropicTool     `json:"tools"`
+	ToolChoice anthropicToolChoice `json:"tool_choice"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema any    `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Type  string          `json:"type"`
+		Name  string          `json:"name"`
+		Input json.RawMessage `json:"input"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens              int64 `json:"input_tokens"`
+		OutputTokens             int64 `json:"output_tokens"`
+		CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicAPIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *anthropicAPIError) Error() string {
+	return fmt.Sprintf("Anthropic API error: HTTP %d %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+func newAnthropicClient(cfg config.Config) LLMClient {
+	baseURL := strings.TrimRight(cfg.LLMBaseURL, "/")
+	if baseURL == "" {
+		baseURL = anthropicDefaultBaseURL
+	}
+	return &anthropicClientImpl{
+		httpClient: NewHTTPClient(cfg, 2*time.Minute),
+		baseURL:    baseURL,
+		apiKey:     cfg.LLMAPIKey,
+		model:      LLMModel(cfg),
+	}
+}
+
+// SendMessage forces a call to a tool whose input schema is
+// OpenAIJobParsingSchema and returns the tool input.
+func (a *anthropicClientImpl) SendMessage(ctx context.Context, message string) (string, models.TokenUsage, error) {
+	payload, err := json.Marshal(anthropicMessagesRequest{
+		Model:     a.model,
+		MaxTokens: anthropicMaxTokens,
+		System:    jobExtractionDeveloperInstruction,
+		Messages:  []anthropicMessage{{Role: "user", Content: message}},
+		Tools: []anthropicTool{{
+			Name:        anthropicToolName,
+			Description: "Parsed job posting information",
+			InputSchema: OpenAIJobParsingSchema,
+		}},
+		ToolChoice: anthropicToolChoice{Type: "tool", Name: anthropicToolName},
+	})
+	if err != nil {
+		return "", models.TokenUsage{}, fmt.Errorf("encode Anthropic request: %w", err)
+	}
+
+	response, err := executeWithRetry(ctx, func() (anthropicMessagesResponse, error) {
+		return a.createMessage(ctx, payload)
+	}, isAnthropicOverloaded)
+	if err != nil {
+		return "", models.TokenUsage{}, err
+	}
+
+	// Anthropic reports cache reads and writes apart from input_tokens
+	tokens := response.Usage
+	promptTokens := tokens.InputTokens + tokens.CacheCreationInputTokens + tokens.CacheReadInputTokens
+	usage := newTokenUsage(a.model, promptTokens, tokens.CacheReadInputTokens, tokens.OutputTokens)
+	for _, block := range response.Content {
+		if block.Type == "tool_use" && block.Name == anthropicToolName {
+			return string(block.Input), usage, nil
+		}
+	}
+	return "", usage, fmt.Errorf("no %s tool call returned from Anthropic (stop reason %q)", anthropicToolName, response.StopReason)
+}
+
+func (a *anthropicClientImpl) createMessage(ctx context.Context, payload []byte) (anthropicMessagesResponse, error) {
+	var result anthropicMessagesResponse
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/v1/messages", bytes.NewReader(payload))
+	if err != nil {
+		return result, fmt.Errorf("create Anthropic request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("x-api-key", a.apiKey)
+	request.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	response, err := a.httpClient.Do(request)
+	if err != nil {
+		return result, fmt.Errorf("send Anthropic request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 4<<20))
+	if err != nil {
+		return result, fmt.Errorf("read Anthropic response: %w", err)
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		apiErr := &anthropicAPIError{StatusCode: response.StatusCode, Message: truncateScraperResponse(string(body), 300)}
+		var envelope struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &envelope) == nil && envelope.Error.Message != "" {
+			apiErr.Type = envelope.Error.Type
+			apiErr.Message = envelope.Error.Message
+		}
+		return result, apiErr
+	}
+
+	if err := json.Unmarshal(body, &result); err != nil {
+		return result, fmt.Errorf("decode Anthropic response: %w", err)
+	}
+	return result, nil
+}
+
+// isAnthropicOverloaded matches rate limits and the 529 overloaded status.
+func isAnthropicOverloaded(err error) bool {
+	var apiErr *anthropicAPIError
+	return errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode == 529)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"gopher-source/models"
+)
+
+const s3PointerScheme = "s3://"
+
+// DescriptionArchive keeps each posting's raw description in S3, since the
+// stored job drops it once enriched, so jobs can be parsed again later.
+type DescriptionArchive interface {
+	// Put stores the job's description and returns its S3Pointer.
+	Put(ctx context.Context, job *models.Job) (string, error)
+	// Get returns the description an S3Pointer refers to.
+	Get(ctx context.Context, pointer string) (string, error)
+}
+
+type descriptionArchiveImpl struct {
+	s3     S3Client
+	bucket string
+	prefix string
+}
+
+// NewDescriptionArchive stores descriptions as <prefix>/<JobId>.txt, so
+// archiving a job again overwrites the same object.
+func NewDescriptionArchive(s3Client S3Client, bucket, prefix string) DescriptionArchive {
+	return &descriptionArchiveImpl{s3: s3Client, bucket: bucket, prefix: strings.Trim(prefix, "/")}
+}
+
+func (a *descriptionArchiveImpl) Put(ctx context.Context, job *models.Job) (string, error) {
+	if strings.TrimSpace(job.JobId) == "" {
+		return "", fmt.Errorf("archive description: job has no ID")
+	}
+	key := path.Join(a.prefix, job.JobId+".txt")
+	if err := a.s3.PutObject(ctx, a.bucket, key, []byte(job.Description)); err != nil {
+		return "", fmt.Errorf("archive description for job %s: %w", job.JobId, err)
+	}
+	return s3PointerScheme + a.bucket + "/" + key, nil
+}
+
+func (a *descriptionArchiveImpl) Get(ctx context.Context, pointer string) (string, error) {
+	bucket, key, err := ParseS3Pointer(pointer)
+	if err != nil {
+		return "", err
+	}
+	body, err := a.s3.GetObject(ctx, bucket, key)
+	if err != nil {
+		return "", fmt.Errorf("read archived description: %w", err)
+	}
+	return string(body), nil
+}
+
+// ParseS3Pointer splits an s3://bucket/key pointer.
+func ParseS3Pointer(pointer string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(pointer), s3PointerScheme)
+	if !ok {
+		return "", "", fmt.Errorf("S3 pointer %q must start with %s", pointer, s3PointerScheme)
+	}
+	bucket, key, ok = strings.Cut(rest, "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", fmt.Errorf("S3 pointer %q must name a bucket and key", pointer)
+	}
+	return bucket, key, nil
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestDescriptionArchiveRoundTrip(t *testing.T) {
+	stub := newS3Stub()
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	archive := NewDescriptionArchive(newTestS3Client(server.URL), "bucket", "/jobs/descriptions/")
+	job := &models.Job{JobId: "42", Description: "Build Go services."}
+	pointer, err := archive.Put(context.Background(), job)
+	if err != nil {
+		t.Fatalf("Put returned error: %v", err)
+	}
+	if pointer != "s3://bucket/jobs/descriptions/42.txt" {
+		t.Fatalf("unexpected pointer %q", pointer)
+	}
+	description, err := archive.Get(context.Background(), pointer)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if description != job.Description {
+		t.Fatalf("expected archived description, got %q", description)
+	}
+}
+
+func TestParseS3PointerRejectsMalformed(t *testing.T) {
+	for _, pointer := range []string{"", "bucket/key", "s3://bucket", "s3:///key", "s3://bucket/"} {
+		if _, _, err := ParseS3Pointer(pointer); err == nil {
+			t.Fatalf("expected an error for %q", pointer)
+		}
+	}
+	bucket, key, err := ParseS3Pointer("s3://bucket/a/b.txt")
+	if err != nil || bucket != "bucket" || key != "a/b.txt" {
+		t.Fatalf("expected bucket and key, got %q %q %v", bucket, key, err)
+	}
+}
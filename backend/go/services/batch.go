@@ -0,0 +1,261 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/shared"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+const (
+	// The Batch API accepts at most 50,000 requests per input file.
+	enrichmentBatchMaxRequests = 50000
+	// batch requests are billed at half the synchronous price
+	enrichmentBatchDiscount = 0.5
+)
+
+// BatchEnrichmentClient parses jobs through the OpenAI Batch API, which
+// finishes within 24 hours at half the per-request price.
+type BatchEnrichmentClient interface {
+	// Submit uploads one batch per enrichmentBatchMaxRequests jobs.
+	Submit(ctx context.Context, jobs []models.Job) ([]models.EnrichmentBatch, error)
+	// Refresh updates the batch's status and result file IDs.
+	Refresh(ctx context.Context, batch *models.EnrichmentBatch) error
+	// Results returns one result per job in a finished batch, in job order.
+	Results(ctx context.Context, batch models.EnrichmentBatch) ([]BatchJobResult, error)
+}
+
+// BatchJobResult holds the enriched job or, when Err is set, a LowConfidence
+// job from FallbackParse. Batch results skip the MinYearsExperience retry the
+// synchronous parser makes.
+type BatchJobResult struct {
+	JobID string
+	Job   *models.Job
+	Usage models.TokenUsage
+	Err   error
+}
+
+type batchEnrichmentClientImpl struct {
+	openai *openaiClientImpl
+}
+
+type batchInputLine struct {
+	CustomID string                         `json:"custom_id"`
+	Method   string                         `json:"method"`
+	URL      string                         `json:"url"`
+	Body     openai.ChatCompletionNewParams `json:"body"`
+}
+
+type batchOutputLine struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int             `json:"status_code"`
+		Body       json.RawMessage `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewBatchEnrichmentClient requires the openai provider; compatible servers
+// and Anthropic do not implement OpenAI's batch endpoints.
+func NewBatchEnrichmentClient(cfg config.Config) (BatchEnrichmentClient, error) {
+	if cfg.LLMProvider != "" && cfg.LLMProvider != config.LLMProviderOpenAI {
+		return nil, fmt.Errorf("batch enrichment requires the %s provider, got %q", config.LLMProviderOpenAI, cfg.LLMProvider)
+	}
+	return &batchEnrichmentClientImpl{openai: newOpenAIClient(cfg, false)}, nil
+}
+
+// EnrichmentBatchFinished reports whether OpenAI will make no further
+// progress on a batch with status.
+func EnrichmentBatchFinished(status string) bool {
+	switch openai.BatchStatus(status) {
+	case openai.BatchStatusCompleted, openai.BatchStatusFailed, openai.BatchStatusExpired, openai.BatchStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *batchEnrichmentClientImpl) Submit(ctx context.Context, jobs []models.Job) ([]models.EnrichmentBatch, error) {
+	jobs = dedupeJobsByID(jobs)
+	var batches []models.EnrichmentBatch
+	for start := 0; start < len(jobs); start += enrichmentBatchMaxRequests {
+		end := min(start+enrichmentBatchMaxRequests, len(jobs))
+		batch, err := b.submitBatch(ctx, jobs[start:end])
+		if err != nil {
+			return batches, err
+		}
+		batches = append(batches, batch)
+	}
+	return batches, nil
+}
+
+func (b *batchEnrichmentClientImpl) submitBatch(ctx context.Context, jobs []models.Job) (models.EnrichmentBatch, error) {
+	var input bytes.Buffer
+	if err := b.writeBatchInput(&input, jobs); err != nil {
+		return models.EnrichmentBatch{}, err
+	}
+
+	file, err := b.openai.client.Files.New(ctx, openai.FileNewParams{
+		File:    openai.File(&input, "enrichment-batch.jsonl", "application/jsonl"),
+		Purpose: openai.FilePurposeBatch,
+	})
+	if err != nil {
+		return models.EnrichmentBatch{}, fmt.Errorf("upload batch input: %w", err)
+	}
+	created, err := b.openai.client.Batches.New(ctx, openai.BatchNewParams{
+		CompletionWindow: openai.BatchNewParamsCompletionWindow24h,
+		Endpoint:         openai.BatchNewParamsEndpointV1ChatCompletions,
+		InputFileID:      file.ID,
+		Metadata:         shared.Metadata{"pipeline": "job-enrichment"},
+	})
+	if err != nil {
+		return models.EnrichmentBatch{}, fmt.Errorf("create batch: %w", err)
+	}
+
+	return models.EnrichmentBatch{
+		ID:          created.ID,
+		InputFileID: file.ID,
+		Status:      string(created.Status),
+		SubmittedAt: time.Now().UTC().Format(time.RFC3339),
+		Jobs:        jobs,
+	}, nil
+}
+
+// writeBatchInput writes one chat completion request per job, identified by
+// its JobId.
+func (b *batchEnrichmentClientImpl) writeBatchInput(w io.Writer, jobs []models.Job) error {
+	encoder := json.NewEncoder(w)
+	for i := range jobs {
+		line := batchInputLine{
+			CustomID: jobs[i].JobId,
+			Method:   "POST",
+			URL:      string(openai.BatchNewParamsEndpointV1ChatCompletions),
+			Body:     b.openai.chatCompletionParams(buildJobParsingMessage(&jobs[i])),
+		}
+		if err := encoder.Encode(line); err != nil {
+			return fmt.Errorf("encode batch input for job %s: %w", jobs[i].JobId, err)
+		}
+	}
+	return nil
+}
+
+func (b *batchEnrichmentClientImpl) Refresh(ctx context.Context, batch *models.EnrichmentBatch) error {
+	current, err := b.openai.client.Batches.Get(ctx, batch.ID)
+	if err != nil {
+		return fmt.Errorf("get batch %s: %w", batch.ID, err)
+	}
+	batch.Status = string(current.Status)
+	batch.OutputFileID = current.OutputFileID
+	batch.ErrorFileID = current.ErrorFileID
+	return nil
+}
+
+func (b *batchEnrichmentClientImpl) Results(ctx context.Context, batch models.EnrichmentBatch) ([]BatchJobResult, error) {
+	outputs := make(map[string]batchOutputLine, len(batch.Jobs))
+	for _, fileID := range []string{batch.OutputFileID, batch.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		if err := b.readBatchOutput(ctx, fileID, outputs); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]BatchJobResult, 0, len(batch.Jobs))
+	for i := range batch.Jobs {
+		job := batch.Jobs[i]
+		result := BatchJobResult{JobID: job.JobId}
+		output, ok := outputs[job.JobId]
+		if !ok {
+			result.Err = fmt.Errorf("no result in batch %s (status %s)", batch.ID, batch.Status)
+		} else if res, usage, err := b.parseBatchOutputLine(output); err != nil {
+			result.Usage = usage
+			result.Err = err
+		} else {
+			result.Usage = usage
+			populateJobFromResponse(&job, res)
+			// batch requests skip the YOE retry and have no per-job latency
+			job.Provenance = newProvenance(time.Now())
+			job.Provenance.RecordCall(usage)
+			result.Job = &job
+		}
+		if result.Err != nil {
+			result.Job = FallbackJob(&job)
+			result.Job.Provenance.PromptTokens = result.Usage.PromptTokens
+			result.Job.Provenance.CompletionTokens = result.Usage.CompletionTokens
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (b *batchEnrichmentClientImpl) readBatchOutput(ctx context.Context, fileID string, outputs map[string]batchOutputLine) error {
+	response, err := b.openai.client.Files.Content(ctx, fileID)
+	if err != nil {
+		return fmt.Errorf("download batch file %s: %w", fileID, err)
+	}
+	defer response.Body.Close()
+
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var line batchOutputLine
+		if err := decoder.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decode batch file %s: %w", fileID, err)
+		}
+		outputs[line.CustomID] = line
+	}
+}
+
+func (b *batchEnrichmentClientImpl) parseBatchOutputLine(line batchOutputLine) (models.OpenAIJobParsingResponse, models.TokenUsage, error) {
+	var none models.OpenAIJobParsingResponse
+	if line.Error != nil {
+		return none, models.TokenUsage{}, fmt.Errorf("batch request failed: %s: %s", line.Error.Code, line.Error.Message)
+	}
+	if line.Response == nil {
+		return none, models.TokenUsage{}, fmt.Errorf("batch result has no response")
+	}
+	if line.Response.StatusCode != 200 {
+		return none, models.TokenUsage{}, fmt.Errorf("batch request returned HTTP %d: %s", line.Response.StatusCode, truncateScraperResponse(string(line.Response.Body), 300))
+	}
+
+	var chatCompletion openai.ChatCompletion
+	if err := json.Unmarshal(line.Response.Body, &chatCompletion); err != nil {
+		return none, models.TokenUsage{}, fmt.Errorf("decode batch chat completion: %w", err)
+	}
+	usage := chatCompletionUsage(b.openai.model, &chatCompletion)
+	usage.CostUSD *= enrichmentBatchDiscount
+	content, err := chatCompletionContent(&chatCompletion)
+	if err != nil {
+		return none, usage, err
+	}
+	res, err := unmarshalJobParsingResponse(content)
+	return res, usage, err
+}
+
+func dedupeJobsByID(jobs []models.Job) []models.Job {
+	seen := make(map[string]bool, len(jobs))
+	unique := make([]models.Job, 0, len(jobs))
+	for _, job := range jobs {
+		if seen[job.JobId] {
+			continue
+		}
+		seen[job.JobId] = true
+		unique = append(unique, job)
+	}
+	return unique
+}
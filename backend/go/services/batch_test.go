@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+type fakeBatchAPI struct {
+	t           *testing.T
+	inputLines  []batchInputLine
+	rawInput    []map[string]any
+	purpose     string
+	endpoint    string
+	outputLines []string
+	errorLines  []string
+}
+
+func (f *fakeBatchAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/files":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			f.t.Errorf("read uploaded file: %v", err)
+			http.Error(w, "bad upload", http.StatusBadRequest)
+			return
+		}
+		f.purpose = r.FormValue("purpose")
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 1<<20), 1<<20)
+		for scanner.Scan() {
+			var raw map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil {
+				f.t.Errorf("decode input line: %v", err)
+			}
+			f.rawInput = append(f.rawInput, raw)
+			f.inputLines = append(f.inputLines, batchInputLine{CustomID: raw["custom_id"].(string), Method: raw["method"].(string), URL: raw["url"].(string)})
+		}
+		io.WriteString(w, `{"id":"file-in","object":"file","bytes":1,"created_at":0,"filename":"enrichment-batch.jsonl","purpose":"batch","status":"processed"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/batches":
+		var body struct {
+			Endpoint    string `json:"endpoint"`
+			InputFileID string `json:"input_file_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.endpoint = body.Endpoint
+		io.WriteString(w, `{"id":"batch_1","object":"batch","endpoint":"/v1/chat/completions","input_file_id":"`+body.InputFileID+`","completion_window":"24h","status":"validating","created_at":0}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/batches/batch_1":
+		io.WriteString(w, `{"id":"batch_1","object":"batch","endpoint":"/v1/chat/completions","input_file_id":"file-in","completion_window":"24h","status":"completed","created_at":0,"output_file_id":"file-out","error_file_id":"file-err"}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/files/file-out/content":
+		io.WriteString(w, strings.Join(f.outputLines, "\n")+"\n")
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/files/file-err/content":
+		io.WriteString(w, strings.Join(f.errorLines, "\n")+"\n")
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		http.NotFound(w, r)
+	}
+}
+
+func batchOutputJSON(t *testing.T, customID, content string) string {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"id":      "chatcmpl-" + customID,
+		"object":  "chat.completion",
+		"created": 0,
+		"model":   "gpt-4.1-nano",
+		"choices": []map[string]any{{
+			"index":         0,
+			"finish_reason": "stop",
+			"message":       map[string]any{"role": "assistant", "content": content},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("marshal completion: %v", err)
+	}
+	return fmt.Sprintf(`{"id":"req-%s","custom_id":%q,"response":{"status_code":200,"request_id":"r","body":%s},"error":null}`, customID, customID, body)
+}
+
+func TestBatchEnrichmentSubmitRefreshAndResults(t *testing.T) {
+	api := &fakeBatchAPI{t: t}
+	api.outputLines = []string{batchOutputJSON(t, "job-1", jobParsingJSON(t, models.OpenAIJobParsingResponse{
+		ParsedDescription:         "Build Go services",
+		Domain:                    "Backend",
+		IsSoftwareEngineerRelated: true,
+	}))}
+	api.errorLines = []string{`{"id":"req-job-2","custom_id":"job-2","response":null,"error":{"code":"invalid_request","message":"too long"}}`}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	client, err := NewBatchEnrichmentClient(config.Config{LLMBaseURL: server.URL + "/v1/", LLMAPIKey: "test-key"})
+	if err != nil {
+		t.Fatalf("NewBatchEnrichmentClient returned error: %v", err)
+	}
+	jobs := []models.Job{
+		{JobId: "job-1", Title: "Go Developer", Description: "Build Go services."},
+		{JobId: "job-2", Title: "Data Engineer", Description: "Pipelines."},
+		{JobId: "job-1", Title: "Go Developer", Description: "Build Go services."},
+		{JobId: "job-3", Title: "SRE", Description: "Keep it up."},
+	}
+	batches, err := client.Submit(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("Submit returned error: %v", err)
+	}
+	if len(batches) != 1 || batches[0].ID != "batch_1" || batches[0].InputFileID != "file-in" || len(batches[0].Jobs) != 3 {
+		t.Fatalf("unexpected batches: %+v", batches)
+	}
+	if api.purpose != "batch" || api.endpoint != "/v1/chat/completions" {
+		t.Fatalf("unexpected upload purpose %q or endpoint %q", api.purpose, api.endpoint)
+	}
+	if len(api.inputLines) != 3 || api.inputLines[0].CustomID != "job-1" || api.inputLines[0].URL != "/v1/chat/completions" || api.inputLines[0].Method != "POST" {
+		t.Fatalf("unexpected batch input: %+v", api.inputLines)
+	}
+	body := api.rawInput[0]["body"].(map[string]any)
+	responseFormat := body["response_format"].(map[string]any)
+	if body["model"] != "gpt-4.1-nano" || responseFormat["type"] != "json_schema" {
+		t.Fatalf("expected structured-output chat completion bodies, got %+v", body)
+	}
+
+	batch := batches[0]
+	if err := client.Refresh(context.Background(), &batch); err != nil {
+		t.Fatalf("Refresh returned error: %v", err)
+	}
+	if !EnrichmentBatchFinished(batch.Status) || batch.OutputFileID != "file-out" || batch.ErrorFileID != "file-err" {
+		t.Fatalf("unexpected refreshed batch: %+v", batch)
+	}
+
+	results, err := client.Results(context.Background(), batch)
+	if err != nil {
+		t.Fatalf("Results returned error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected a result per job, got %+v", results)
+	}
+	if results[0].Err != nil || results[0].Job == nil || results[0].Job.Domain != "Backend" || results[0].Job.Description != "" {
+		t.Fatalf("expected job-1 to be enriched, got %+v", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "too long") {
+		t.Fatalf("expected job-2 request error, got %+v", results[1])
+	}
+	if results[2].Err == nil || !strings.Contains(results[2].Err.Error(), "no result") {
+		t.Fatalf("expected job-3 to be missing, got %+v", results[2])
+	}
+	for _, result := range results[1:] {
+		if result.Job == nil || !result.Job.LowConfidence || result.Job.JobId != result.JobID {
+			t.Fatalf("expected failed requests to carry a low-confidence fallback, got %+v", result)
+		}
+	}
+}
+
+func TestNewBatchEnrichmentClientRequiresOpenAI(t *testing.T) {
+	if _, err := NewBatchEnrichmentClient(config.Config{LLMProvider: config.LLMProviderAnthropic}); err == nil {
+		t.Fatal("expected batch enrichment to reject non-OpenAI providers")
+	}
+}
+
+func TestEnrichmentBatchFinished(t *testing.T) {
+	for status, want := range map[string]bool{
+		"validating":  false,
+		"in_progress": false,
+		"finalizing":  false,
+		"completed":   true,
+		"failed":      true,
+		"expired":     true,
+		"cancelled":   true,
+	} {
+		if got := EnrichmentBatchFinished(status); got != want {
+			t.Fatalf("EnrichmentBatchFinished(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
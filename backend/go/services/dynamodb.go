@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"gopher-source/models"
 	"gopher-source/utils"
+	"math/rand"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
@@ -18,21 +21,50 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
-type DynamoDBClient interface {
-	PutJob(ctx context.Context, job *models.Job) error
-	QueryJobsByPostedDate(ctx context.Context, date string) ([]models.Job, error)
-	GetAllJobIds(ctx context.Context) (map[string]bool, error)
-	WriteJobIdsToFile(filename string, keySet map[string]bool) error
-}
-
 type dynamoDBClientImpl struct {
 	client    *dynamodb.Client
 	tableName string
 }
 
-const postedDateIndexName = "PostedDate-Index"
+const (
+	postedDateIndexName = "PostedDate-Index"
+	// the company and domain indexes use PostedDate as their sort key
+	companyIndexName = "Company-Index"
+	domainIndexName  = "Domain-Index"
+)
+
+const (
+	// maxBatchWriteItems is the most items one BatchWriteItem call accepts
+	maxBatchWriteItems = 25
+	// maxBatchGetItems is the most keys one BatchGetItem call accepts
+	maxBatchGetItems = 100
+	// calls for one batch, counting the first, before unprocessed items fail it
+	batchWriteMaxAttempts = 8
+	batchWriteBaseDelay   = 50 * time.Millisecond
+	batchWriteMaxDelay    = 5 * time.Second
+	// maxDayQueries is how many days of a date range are queried at once
+	maxDayQueries = 8
+)
+
+// enrichedAttributes are the item attributes derived from the LLM response;
+// Description is cleared along with them once a job is parsed.
+var enrichedAttributes = []string{
+	"Description", "ParsedDescription", "ExpiresDate", "MinDegree", "MinYearsExperience", "Modality", "Domain",
+	"Languages", "Technologies", "IsSoftwareEngineerRelated", "LowConfidence", "ExtractionVersion", "Provenance", "S3Pointer",
+	"SalaryMin", "SalaryMax", "SalaryCurrency", "SalaryPeriod", "SalaryAnnualMin", "SalaryAnnualMax", "SalaryDOE", "SalarySource",
+	"VisaSponsorship", "SecurityClearance", "WorkAuthorization", "Seniority", "QualityFlags",
+}
 
-func NewDynamoService(cfg aws.Config, tableName, endpoint string) DynamoDBClient {
+// historyAttributes are kept up to date by the posting tracker; upserts and
+// enrichment updates leave them alone.
+var historyAttributes = []string{"ClosedAt", "History"}
+
+// indexKeyAttributes key the company and domain indexes. DynamoDB rejects an
+// empty string as an index key, so an empty one is left off the item, which
+// keeps the job out of that index.
+var indexKeyAttributes = []string{"Company", "Domain"}
+
+func NewDynamoService(cfg aws.Config, tableName, endpoint string) JobStore {
 	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
 		if strings.TrimSpace(endpoint) != "" {
 			o.BaseEndpoint = aws.String(endpoint)
@@ -70,16 +102,198 @@ func (d *dynamoDBClientImpl) queryJobs(ctx context.Context, keyCondition string,
 	if strings.TrimSpace(keyCondition) == "" {
 		return nil, fmt.Errorf("key condition expression is required")
 	}
-	if d.client == nil {
-		return nil, fmt.Errorf("dynamodb client is not initialized")
-	}
 
-	input := &dynamodb.QueryInput{
+	return d.paginateQuery(ctx, &dynamodb.QueryInput{
 		TableName:                 aws.String(d.tableName),
 		IndexName:                 aws.String(postedDateIndexName),
 		KeyConditionExpression:    aws.String(keyCondition),
 		ExpressionAttributeValues: values,
 		ScanIndexForward:          aws.Bool(false),
+	})
+}
+
+func (d *dynamoDBClientImpl) QueryJobsByDateRange(ctx context.Context, startDate, endDate string) ([]models.Job, error) {
+	filter, err := dateRangeFilter(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryJobs(ctx, filter)
+}
+
+// QueryJobs queries the company or domain index when filter names one, with
+// the posted dates as its sort key condition. Otherwise a bounded date range
+// is queried from the posted date index, several days at a time, and an
+// unbounded one falls back to a scan. Fields the key does not cover become
+// a filter expression.
+func (d *dynamoDBClientImpl) QueryJobs(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	var jobs []models.Job
+	var err error
+	switch {
+	case filter.Company != "":
+		jobs, err = d.queryIndex(ctx, companyIndexName, "Company", filter.Company, filter)
+	case filter.Domain != "":
+		jobs, err = d.queryIndex(ctx, domainIndexName, "Domain", filter.Domain, filter)
+	case filter.PostedFrom != "" && filter.PostedTo != "":
+		jobs, err = d.queryDays(ctx, filter)
+	default:
+		jobs, err = d.scanJobs(ctx, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	sortNewestFirst(jobs)
+	return jobs, nil
+}
+
+// queryIndex queries the index whose partition key hashName equals hashValue.
+// The company and domain indexes sort by PostedDate, so the filter's date
+// bounds narrow the key condition there.
+func (d *dynamoDBClientImpl) queryIndex(ctx context.Context, indexName, hashName, hashValue string, filter JobFilter) ([]models.Job, error) {
+	key := expression.Key(hashName).Equal(expression.Value(hashValue))
+	if indexName != postedDateIndexName {
+		postedDate := expression.Key("PostedDate")
+		switch {
+		case filter.PostedFrom != "" && filter.PostedTo != "":
+			key = key.And(postedDate.Between(expression.Value(filter.PostedFrom), expression.Value(filter.PostedTo)))
+		case filter.PostedFrom != "":
+			key = key.And(postedDate.GreaterThanEqual(expression.Value(filter.PostedFrom)))
+		case filter.PostedTo != "":
+			key = key.And(postedDate.LessThanEqual(expression.Value(filter.PostedTo)))
+		}
+	}
+	builder := expression.NewBuilder().WithKeyCondition(key)
+	if cond, ok := filterCondition(filter, hashName, "PostedDate"); ok {
+		builder = builder.WithFilter(cond)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("build query: %w", err)
+	}
+
+	return d.paginateQuery(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(d.tableName),
+		IndexName:                 aws.String(indexName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+	})
+}
+
+// queryDays queries the posted date index once per day of the filter's
+// range, up to maxDayQueries days at a time. Every stored item carries
+// PostedDate, so no day is missed, and each day is its own index partition.
+func (d *dynamoDBClientImpl) queryDays(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	from, _ := time.Parse(postedDateLayout, filter.PostedFrom)
+	to, _ := time.Parse(postedDateLayout, filter.PostedTo)
+	var dates []string
+	for day := to; !day.Before(from); day = day.AddDate(0, 0, -1) {
+		dates = append(dates, day.Format(postedDateLayout))
+	}
+
+	dailyJobs := make([][]models.Job, len(dates))
+	errs := make([]error, len(dates))
+	sem := make(chan struct{}, maxDayQueries)
+	var wg sync.WaitGroup
+	for i, date := range dates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			jobs, err := d.queryIndex(ctx, postedDateIndexName, "PostedDate", date, filter)
+			if err != nil {
+				errs[i] = fmt.Errorf("query jobs for %s: %w", date, err)
+				return
+			}
+			dailyJobs[i] = jobs
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	var jobs []models.Job
+	for _, day := range dailyJobs {
+		jobs = append(jobs, day...)
+	}
+	return jobs, nil
+}
+
+// scanJobs reads the whole table, for filters no index serves.
+func (d *dynamoDBClientImpl) scanJobs(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	if d.client == nil {
+		return nil, fmt.Errorf("dynamodb client is not initialized")
+	}
+	input := &dynamodb.ScanInput{TableName: aws.String(d.tableName)}
+	if cond, ok := filterCondition(filter); ok {
+		expr, err := expression.NewBuilder().WithFilter(cond).Build()
+		if err != nil {
+			return nil, fmt.Errorf("build scan: %w", err)
+		}
+		input.FilterExpression = expr.Filter()
+		input.ExpressionAttributeNames = expr.Names()
+		input.ExpressionAttributeValues = expr.Values()
+	}
+
+	paginator := dynamodb.NewScanPaginator(d.client, input)
+	var jobs []models.Job
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("scan jobs: %w", err)
+		}
+		var pageJobs []models.Job
+		if err := attributevalue.UnmarshalListOfMaps(output.Items, &pageJobs); err != nil {
+			return nil, fmt.Errorf("unmarshal jobs: %w", err)
+		}
+		jobs = append(jobs, pageJobs...)
+	}
+	return jobs, nil
+}
+
+// filterCondition turns the fields of filter not named in keyed into one
+// condition, or reports false when none are set.
+func filterCondition(filter JobFilter, keyed ...string) (expression.ConditionBuilder, bool) {
+	skip := make(map[string]bool, len(keyed))
+	for _, name := range keyed {
+		skip[name] = true
+	}
+	var conds []expression.ConditionBuilder
+	for _, field := range []struct{ name, value string }{
+		{"Company", filter.Company},
+		{"Domain", filter.Domain},
+		{"Modality", filter.Modality},
+	} {
+		if field.value != "" && !skip[field.name] {
+			conds = append(conds, expression.Name(field.name).Equal(expression.Value(field.value)))
+		}
+	}
+	if !skip["PostedDate"] {
+		if filter.PostedFrom != "" {
+			conds = append(conds, expression.Name("PostedDate").GreaterThanEqual(expression.Value(filter.PostedFrom)))
+		}
+		if filter.PostedTo != "" {
+			conds = append(conds, expression.Name("PostedDate").LessThanEqual(expression.Value(filter.PostedTo)))
+		}
+	}
+	switch len(conds) {
+	case 0:
+		return expression.ConditionBuilder{}, false
+	case 1:
+		return conds[0], true
+	default:
+		return expression.And(conds[0], conds[1], conds[2:]...), true
+	}
+}
+
+func (d *dynamoDBClientImpl) paginateQuery(ctx context.Context, input *dynamodb.QueryInput) ([]models.Job, error) {
+	if d.client == nil {
+		return nil, fmt.Errorf("dynamodb client is not initialized")
 	}
 
 	paginator := dynamodb.NewQueryPaginator(d.client, input)
@@ -103,14 +317,15 @@ func (d *dynamoDBClientImpl) queryJobs(ctx context.Context, keyCondition string,
 }
 
 func (d *dynamoDBClientImpl) PutJob(ctx context.Context, job *models.Job) error {
-	cond := expression.Name(job.JobId).AttributeNotExists()
+	cond := expression.AttributeNotExists(expression.Name("JobId"))
 	expr, err := expression.NewBuilder().WithCondition(cond).Build()
 	if err != nil {
 		return err
 	}
-	item, err := job.ToDynamoDBItem()
+	stored := firstVersion(job)
+	item, err := jobItem(&stored)
 	if err != nil {
-		return fmt.Errorf("failed to marshal job to DynamoDB item: %w", err)
+		return err
 	}
 
 	input := &dynamodb.PutItemInput{
@@ -134,6 +349,296 @@ func (d *dynamoDBClientImpl) PutJob(ctx context.Context, job *models.Job) error
 	return nil
 }
 
+// PutJobs writes jobs in BatchWriteItem calls of up to 25 items. Those
+// calls take no condition, so each batch is first looked up with
+// BatchGetItem and jobs already stored are left out, which keeps PutJob's
+// insert-if-absent behavior for a single writer.
+func (d *dynamoDBClientImpl) PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error) {
+	var result WriteResult
+	for start := 0; start < len(jobs); start += maxBatchWriteItems {
+		batch := jobs[start:min(start+maxBatchWriteItems, len(jobs))]
+		if err := d.putBatch(ctx, batch, &result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+func (d *dynamoDBClientImpl) putBatch(ctx context.Context, batch []models.Job, result *WriteResult) error {
+	// BatchWriteItem rejects a request that repeats a key
+	unique := make(map[string]models.Job, len(batch))
+	keys := make([]map[string]types.AttributeValue, 0, len(batch))
+	for _, job := range batch {
+		id := itemKey(job)
+		if _, repeated := unique[id]; repeated {
+			result.Skipped++
+			continue
+		}
+		unique[id] = job
+		keys = append(keys, jobKey(job))
+	}
+
+	stored, err := d.storedHashes(ctx, keys, result)
+	if err != nil {
+		return err
+	}
+	var requests []types.WriteRequest
+	for _, job := range batch {
+		id := itemKey(job)
+		if _, pending := unique[id]; !pending {
+			continue
+		}
+		delete(unique, id)
+		if _, ok := stored[id]; ok {
+			result.Skipped++
+			continue
+		}
+		first := firstVersion(&job)
+		item, err := jobItem(&first)
+		if err != nil {
+			return err
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+
+	written := len(requests)
+	for attempt := 1; len(requests) > 0; attempt++ {
+		if attempt > 1 {
+			if attempt > batchWriteMaxAttempts {
+				return fmt.Errorf("batch write left %d of %d job(s) unprocessed after %d attempts", len(requests), written, batchWriteMaxAttempts)
+			}
+			result.Retries++
+			if err := batchBackoff(ctx, attempt); err != nil {
+				return err
+			}
+		}
+		output, err := d.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{d.tableName: requests},
+		})
+		result.Calls++
+		if err != nil {
+			return fmt.Errorf("batch write jobs: %w", err)
+		}
+		requests = output.UnprocessedItems[d.tableName]
+	}
+	result.Stored += written
+	utils.Debug(fmt.Sprintf("\t📦 Batch stored %d job(s)", written))
+	return nil
+}
+
+// SourceHashes looks the jobs up with one BatchGetItem call per 100 keys,
+// reading only their keys and fingerprints.
+func (d *dynamoDBClientImpl) SourceHashes(ctx context.Context, jobs []models.Job) ([]string, error) {
+	hashes := make([]string, len(jobs))
+	for start := 0; start < len(jobs); start += maxBatchGetItems {
+		batch := jobs[start:min(start+maxBatchGetItems, len(jobs))]
+		// BatchGetItem rejects a request that repeats a key
+		requested := make(map[string]bool, len(batch))
+		keys := make([]map[string]types.AttributeValue, 0, len(batch))
+		for _, job := range batch {
+			if id := itemKey(job); !requested[id] {
+				requested[id] = true
+				keys = append(keys, jobKey(job))
+			}
+		}
+		stored, err := d.storedHashes(ctx, keys, &WriteResult{})
+		if err != nil {
+			return nil, err
+		}
+		for i, job := range batch {
+			hashes[start+i] = stored[itemKey(job)]
+		}
+	}
+	return hashes, nil
+}
+
+// storedHashes returns the SourceHash of each job the table already holds,
+// keyed by itemKey; a job stored before fingerprints maps to "".
+func (d *dynamoDBClientImpl) storedHashes(ctx context.Context, keys []map[string]types.AttributeValue, result *WriteResult) (map[string]string, error) {
+	stored := make(map[string]string)
+	if len(keys) == 0 {
+		return stored, nil
+	}
+	proj := expression.NamesList(expression.Name("JobId"), expression.Name("PostedDate"), expression.Name("SourceHash"))
+	expr, err := expression.NewBuilder().WithProjection(proj).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+	request := map[string]types.KeysAndAttributes{d.tableName: {
+		Keys:                     keys,
+		ProjectionExpression:     expr.Projection(),
+		ExpressionAttributeNames: expr.Names(),
+	}}
+
+	for attempt := 1; len(request) > 0; attempt++ {
+		if attempt > 1 {
+			if attempt > batchWriteMaxAttempts {
+				return nil, fmt.Errorf("batch get left keys unprocessed after %d attempts", batchWriteMaxAttempts)
+			}
+			result.Retries++
+			if err := batchBackoff(ctx, attempt); err != nil {
+				return nil, err
+			}
+		}
+		output, err := d.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: request})
+		result.Calls++
+		if err != nil {
+			return nil, fmt.Errorf("batch get jobs: %w", err)
+		}
+		var items []models.Job
+		if err := attributevalue.UnmarshalListOfMaps(output.Responses[d.tableName], &items); err != nil {
+			return nil, fmt.Errorf("unmarshal stored keys: %w", err)
+		}
+		for _, item := range items {
+			stored[itemKey(item)] = item.SourceHash
+		}
+		request = output.UnprocessedKeys
+	}
+	return stored, nil
+}
+
+// jobItem marshals job for a write, leaving off empty index keys.
+func jobItem(job *models.Job) (map[string]types.AttributeValue, error) {
+	item, err := job.ToDynamoDBItem()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job to DynamoDB item: %w", err)
+	}
+	for _, name := range indexKeyAttributes {
+		if value, ok := item[name].(*types.AttributeValueMemberS); ok && value.Value == "" {
+			delete(item, name)
+		}
+	}
+	return item, nil
+}
+
+// itemKey joins the table key of job with a NUL.
+func itemKey(job models.Job) string {
+	return job.JobId + "\x00" + job.PostedDate
+}
+
+func jobKey(job models.Job) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"JobId":      &types.AttributeValueMemberS{Value: job.JobId},
+		"PostedDate": &types.AttributeValueMemberS{Value: job.PostedDate},
+	}
+}
+
+// batchBackoff waits before the given attempt with exponential backoff and
+// full jitter, as DynamoDB recommends for unprocessed items.
+func batchBackoff(ctx context.Context, attempt int) error {
+	delay := min(batchWriteBaseDelay<<(attempt-2), batchWriteMaxDelay)
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(delay)) + 1)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// UpsertJob writes every attribute in one conditional update, so the source
+// fingerprint is compared and Version bumped without reading the item first.
+func (d *dynamoDBClientImpl) UpsertJob(ctx context.Context, job *models.Job) (bool, error) {
+	stored := firstVersion(job)
+	item, err := jobItem(&stored)
+	if err != nil {
+		return false, err
+	}
+	version := expression.Name("Version")
+	update := expression.Set(version, expression.Plus(expression.IfNotExists(version, expression.Value(0)), expression.Value(1)))
+	for name, value := range item {
+		switch name {
+		case "JobId", "PostedDate", "Version", "ClosedAt", "History":
+			continue
+		}
+		update = update.Set(expression.Name(name), expression.Value(value))
+	}
+	for _, name := range indexKeyAttributes {
+		if _, ok := item[name]; !ok {
+			update = update.Remove(expression.Name(name))
+		}
+	}
+	// a missing SourceHash covers both a new item and one stored before
+	// fingerprints, which compares as neither equal nor unequal
+	hash := expression.Name("SourceHash")
+	cond := expression.AttributeNotExists(hash).Or(hash.NotEqual(expression.Value(stored.SourceHash)))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return false, fmt.Errorf("build upsert: %w", err)
+	}
+
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.tableName),
+		Key:                       jobKey(*job),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			utils.Debug("\t⚠️  Posting unchanged, skipping")
+			return false, nil
+		}
+		return false, fmt.Errorf("upsert job %s: %w", job.JobId, err)
+	}
+	utils.Debug(fmt.Sprintf("\t📦 Upserted job %s", job.Title))
+	return true, nil
+}
+
+func (d *dynamoDBClientImpl) UpdateJobEnrichment(ctx context.Context, job *models.Job) error {
+	if err := d.updateAttributes(ctx, job, enrichedAttributes); err != nil {
+		return fmt.Errorf("update enrichment of job %s: %w", job.JobId, err)
+	}
+	utils.Debug(fmt.Sprintf("\t📝 Updated enrichment for job %s", job.Title))
+	return nil
+}
+
+func (d *dynamoDBClientImpl) UpdateJobHistory(ctx context.Context, job *models.Job) error {
+	if err := d.updateAttributes(ctx, job, historyAttributes); err != nil {
+		return fmt.Errorf("update history of job %s: %w", job.JobId, err)
+	}
+	return nil
+}
+
+// updateAttributes sets the named attributes of a stored job from job.
+func (d *dynamoDBClientImpl) updateAttributes(ctx context.Context, job *models.Job, names []string) error {
+	item, err := jobItem(job)
+	if err != nil {
+		return err
+	}
+	var update expression.UpdateBuilder
+	for _, name := range names {
+		if value, ok := item[name]; ok {
+			update = update.Set(expression.Name(name), expression.Value(value))
+		} else {
+			update = update.Remove(expression.Name(name))
+		}
+	}
+	cond := expression.AttributeExists(expression.Name("JobId"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("build update: %w", err)
+	}
+
+	_, err = d.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(d.tableName),
+		Key:                       jobKey(*job),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return ErrJobNotStored
+		}
+		return err
+	}
+	return nil
+}
+
 func (d *dynamoDBClientImpl) GetAllJobIds(ctx context.Context) (map[string]bool, error) {
 	jobIds := make(map[string]bool)
 
@@ -169,7 +674,8 @@ func (d *dynamoDBClientImpl) GetAllJobIds(ctx context.Context) (map[string]bool,
 	return jobIds, nil
 }
 
-func (d *dynamoDBClientImpl) WriteJobIdsToFile(filename string, keySet map[string]bool) error {
+// WriteJobIDsFile writes the job ID cache, one id per line.
+func WriteJobIDsFile(filename string, keySet map[string]bool) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		fmt.Printf("failed to create file %v", err)
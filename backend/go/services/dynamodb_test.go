@@ -2,13 +2,17 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -43,6 +47,219 @@ func TestDynamoPutJobHandlesDuplicate(t *testing.T) {
 	}
 }
 
+func TestDynamoPutJobConditionsOnJobIdKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.PutItem":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			defer r.Body.Close()
+			payload := string(body)
+			for _, want := range []string{`"ConditionExpression":"attribute_not_exists (#0)"`, `"#0":"JobId"`, `"Version":{"N":"1"}`, `"SourceHash":{"S":"` + sourceHash(&testJob) + `"}`} {
+				if !strings.Contains(payload, want) {
+					t.Fatalf("expected %s in payload %s", want, payload)
+				}
+			}
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	job := testJob
+	if err := client.PutJob(context.Background(), &job); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+	if job.Version != 0 {
+		t.Fatalf("expected the caller's job to be left alone, got version %d", job.Version)
+	}
+}
+
+func TestDynamoUpsertJob(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		response    string
+		wantWritten bool
+	}{
+		{name: "changed posting is rewritten", status: http.StatusOK, response: `{}`, wantWritten: true},
+		{name: "unchanged posting is skipped", status: http.StatusBadRequest, response: `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if target := r.Header.Get("X-Amz-Target"); target != "DynamoDB_20120810.UpdateItem" {
+					t.Fatalf("unexpected target %s", target)
+				}
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Fatalf("failed to read request body: %v", err)
+				}
+				defer r.Body.Close()
+				payload := string(body)
+				for _, want := range []string{`"JobId":{"S":"1"}`, `"PostedDate":{"S":"2025-11-04"}`, "if_not_exists", "attribute_not_exists", "<>", `"Version"`, `"SourceHash"`, `"Title"`, `"Engineer II"`} {
+					if !strings.Contains(payload, want) {
+						t.Fatalf("expected %s in payload %s", want, payload)
+					}
+				}
+				if strings.Contains(payload, `"History"`) {
+					t.Fatalf("expected the posting history to be left alone, got %s", payload)
+				}
+				w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.response)
+			}))
+			defer server.Close()
+
+			client := newTestDynamoClient(server.URL)
+			job := testJob
+			job.PostedDate = "2025-11-04"
+			job.Title = "Engineer II"
+			written, err := client.UpsertJob(context.Background(), &job)
+			if err != nil {
+				t.Fatalf("UpsertJob returned error: %v", err)
+			}
+			if written != tt.wantWritten {
+				t.Fatalf("expected written=%v, got %v", tt.wantWritten, written)
+			}
+		})
+	}
+}
+
+func TestDynamoPutJobsSkipsStoredJobsAndRetriesUnprocessedItems(t *testing.T) {
+	var writes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		defer r.Body.Close()
+		payload := string(body)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.BatchGetItem":
+			if strings.Count(payload, `"JobId":{"S":`) != 3 {
+				t.Fatalf("expected the repeated job to be looked up once, got %s", payload)
+			}
+			fmt.Fprint(w, `{"Responses":{"Jobs":[{"JobId":{"S":"2"},"PostedDate":{"S":"2025-11-04"}}]}}`)
+		case "DynamoDB_20120810.BatchWriteItem":
+			if atomic.AddInt32(&writes, 1) == 1 {
+				if !strings.Contains(payload, `"Title":{"S":"One"}`) || !strings.Contains(payload, `"Title":{"S":"Three"}`) || strings.Contains(payload, `"Title":{"S":"Two"}`) {
+					t.Fatalf("expected only the new jobs to be written, got %s", payload)
+				}
+				if strings.Contains(payload, "One again") {
+					t.Fatalf("expected the first of the repeated jobs to be kept, got %s", payload)
+				}
+				fmt.Fprint(w, `{"UnprocessedItems":{"Jobs":[{"PutRequest":{"Item":{"JobId":{"S":"3"},"PostedDate":{"S":"2025-11-04"},"Title":{"S":"Three"}}}}]}}`)
+				return
+			}
+			if strings.Contains(payload, `"Title":{"S":"One"}`) || !strings.Contains(payload, `"Title":{"S":"Three"}`) {
+				t.Fatalf("expected only the unprocessed job to be resent, got %s", payload)
+			}
+			fmt.Fprint(w, `{"UnprocessedItems":{}}`)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	jobs := []models.Job{
+		{JobId: "1", Title: "One", PostedDate: "2025-11-04"},
+		{JobId: "2", Title: "Two", PostedDate: "2025-11-04"},
+		{JobId: "1", Title: "One again", PostedDate: "2025-11-04"},
+		{JobId: "3", Title: "Three", PostedDate: "2025-11-04"},
+	}
+	result, err := client.PutJobs(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("PutJobs returned error: %v", err)
+	}
+	if result != (WriteResult{Stored: 2, Skipped: 2, Calls: 3, Retries: 1}) {
+		t.Fatalf("unexpected write result: %+v", result)
+	}
+}
+
+func TestDynamoPutJobsSplitsIntoBatchesOf25(t *testing.T) {
+	var gets, writes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		defer r.Body.Close()
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.BatchGetItem":
+			atomic.AddInt32(&gets, 1)
+			fmt.Fprint(w, `{"Responses":{"Jobs":[]}}`)
+		case "DynamoDB_20120810.BatchWriteItem":
+			if n := strings.Count(string(body), `"PutRequest"`); n > maxBatchWriteItems {
+				t.Fatalf("expected at most %d items per call, got %d", maxBatchWriteItems, n)
+			}
+			atomic.AddInt32(&writes, 1)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	jobs := make([]models.Job, 60)
+	for i := range jobs {
+		jobs[i] = models.Job{JobId: fmt.Sprint(i), PostedDate: "2025-11-04"}
+	}
+	result, err := newTestDynamoClient(server.URL).PutJobs(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("PutJobs returned error: %v", err)
+	}
+	if result.Stored != 60 || atomic.LoadInt32(&gets) != 3 || atomic.LoadInt32(&writes) != 3 {
+		t.Fatalf("expected 60 jobs in 3 batches, got %+v after %d gets and %d writes", result, gets, writes)
+	}
+}
+
+func TestDynamoSourceHashesLooksUpHundredKeysPerCall(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != "DynamoDB_20120810.BatchGetItem" {
+			t.Fatalf("unexpected target %s", target)
+		}
+		var req struct {
+			RequestItems map[string]struct {
+				Keys                 []map[string]map[string]string
+				ProjectionExpression string
+			}
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		atomic.AddInt32(&calls, 1)
+		request := req.RequestItems["Jobs"]
+		if len(request.Keys) > 100 || !strings.Contains(request.ProjectionExpression, "#2") {
+			t.Fatalf("expected at most 100 keys and a projection of the fingerprint, got %d keys and %q", len(request.Keys), request.ProjectionExpression)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		fmt.Fprint(w, `{"Responses":{"Jobs":[{"JobId":{"S":"7"},"PostedDate":{"S":"2026-10-15"},"SourceHash":{"S":"abc"}}]},"UnprocessedKeys":{}}`)
+	}))
+	defer server.Close()
+
+	jobs := make([]models.Job, 150)
+	for i := range jobs {
+		jobs[i] = models.Job{JobId: fmt.Sprint(i), PostedDate: "2026-10-15"}
+	}
+	hashes, err := newTestDynamoClient(server.URL).SourceHashes(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("SourceHashes returned error: %v", err)
+	}
+	if atomic.LoadInt32(&calls) != 2 || hashes[7] != "abc" || hashes[8] != "" {
+		t.Fatalf("expected 2 calls and the stored fingerprint, got %d calls and %v", calls, hashes[7:9])
+	}
+}
+
 func TestDynamoGetAllJobIds(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.Header.Get("X-Amz-Target") {
@@ -68,14 +285,13 @@ func TestDynamoGetAllJobIds(t *testing.T) {
 	}
 }
 
-func TestWriteJobIdsToFile(t *testing.T) {
-	client := &dynamoDBClientImpl{}
+func TestWriteJobIDsFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	outFile := filepath.Join(tmpDir, "ids.txt")
 
-	err := client.WriteJobIdsToFile(outFile, map[string]bool{"a": true, "b": true})
+	err := WriteJobIDsFile(outFile, map[string]bool{"a": true, "b": true})
 	if err != nil {
-		t.Fatalf("WriteJobIdsToFile returned error: %v", err)
+		t.Fatalf("WriteJobIDsFile returned error: %v", err)
 	}
 	data, err := os.ReadFile(outFile)
 	if err != nil {
@@ -155,3 +371,243 @@ func TestQueryJobsByPostedDate(t *testing.T) {
 		t.Fatalf("unexpected job returned: %+v", jobs[0])
 	}
 }
+
+func TestDynamoQueryJobsUsesCompanyIndex(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.Query":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			defer r.Body.Close()
+			payload := string(body)
+			for _, want := range []string{`"IndexName":"` + companyIndexName + `"`, "BETWEEN", `"Acme"`, `"Remote"`, `"FilterExpression"`, `"Modality"`} {
+				if !strings.Contains(payload, want) {
+					t.Fatalf("expected %s in payload %s", want, payload)
+				}
+			}
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			fmt.Fprint(w, `{"Items":[
+				{"JobId":{"S":"1"},"Company":{"S":"Acme"},"PostedDate":{"S":"2025-11-03"}},
+				{"JobId":{"S":"2"},"Company":{"S":"Acme"},"PostedDate":{"S":"2025-11-04"}}]}`)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	jobs, err := client.QueryJobs(context.Background(), JobFilter{Company: "Acme", Modality: "Remote", PostedFrom: "2025-11-01", PostedTo: "2025-11-04"})
+	if err != nil {
+		t.Fatalf("QueryJobs returned error: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].JobId != "2" || jobs[1].JobId != "1" {
+		t.Fatalf("expected jobs 2 and 1 newest first, got %+v", jobs)
+	}
+}
+
+func TestDynamoQueryJobsByDateRangeQueriesEachDay(t *testing.T) {
+	var mu sync.Mutex
+	var dates []string
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.Query":
+			current := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			var input struct {
+				IndexName                 string
+				ExpressionAttributeValues map[string]struct{ S string }
+			}
+			if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+				t.Fatalf("failed to decode request body: %v", err)
+			}
+			if input.IndexName != postedDateIndexName || len(input.ExpressionAttributeValues) != 1 {
+				t.Fatalf("expected one posted date key on %s, got %+v", postedDateIndexName, input)
+			}
+			var date string
+			for _, value := range input.ExpressionAttributeValues {
+				date = value.S
+			}
+			mu.Lock()
+			dates = append(dates, date)
+			if current > maxInFlight {
+				maxInFlight = current
+			}
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			fmt.Fprintf(w, `{"Items":[{"JobId":{"S":"%s"},"PostedDate":{"S":"%s"}}]}`, date, date)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	jobs, err := client.QueryJobsByDateRange(context.Background(), "2025-10-01", "2025-11-02")
+	if err != nil {
+		t.Fatalf("QueryJobsByDateRange returned error: %v", err)
+	}
+	sort.Strings(dates)
+	if len(dates) != 33 || dates[0] != "2025-10-01" || dates[32] != "2025-11-02" {
+		t.Fatalf("expected one query per day, got %v", dates)
+	}
+	if maxInFlight > maxDayQueries {
+		t.Fatalf("expected at most %d days queried at once, got %d", maxDayQueries, maxInFlight)
+	}
+	if len(jobs) != 33 || jobs[0].PostedDate != "2025-11-02" || jobs[32].PostedDate != "2025-10-01" {
+		t.Fatalf("expected a job per day newest first, got %+v", jobs)
+	}
+}
+
+func TestDynamoQueryJobsByModalityScansTable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.Scan":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			defer r.Body.Close()
+			payload := string(body)
+			for _, want := range []string{`"FilterExpression"`, `"Modality"`, `"Remote"`} {
+				if !strings.Contains(payload, want) {
+					t.Fatalf("expected %s in payload %s", want, payload)
+				}
+			}
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			fmt.Fprint(w, `{"Items":[{"JobId":{"S":"1"},"Modality":{"S":"Remote"},"PostedDate":{"S":"2025-11-04"}}]}`)
+		default:
+			t.Fatalf("expected a scan, got %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	jobs, err := client.QueryJobs(context.Background(), JobFilter{Modality: "Remote"})
+	if err != nil {
+		t.Fatalf("QueryJobs returned error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].JobId != "1" {
+		t.Fatalf("expected job 1, got %+v", jobs)
+	}
+}
+
+func TestDynamoPutJobLeavesEmptyIndexKeysOff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		defer r.Body.Close()
+		payload := string(body)
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.PutItem":
+			if !strings.Contains(payload, `"Company":{"S":"Acme"}`) || strings.Contains(payload, `"Domain"`) {
+				t.Fatalf("expected the company kept and the empty domain left off, got %s", payload)
+			}
+		case "DynamoDB_20120810.UpdateItem":
+			if !strings.Contains(payload, "REMOVE") || !strings.Contains(payload, `:"Domain"`) || !strings.Contains(payload, `{"S":"Acme"}`) {
+				t.Fatalf("expected an upsert to set the company and remove the empty domain, got %s", payload)
+			}
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	job := testJob
+	if err := client.PutJob(context.Background(), &job); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+	if _, err := client.UpsertJob(context.Background(), &job); err != nil {
+		t.Fatalf("UpsertJob returned error: %v", err)
+	}
+}
+
+func TestUpdateJobEnrichmentRewritesEnrichedFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDB_20120810.UpdateItem":
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				t.Fatalf("failed to read request body: %v", err)
+			}
+			defer r.Body.Close()
+			payload := string(body)
+			for _, want := range []string{`"JobId":{"S":"1"}`, `"PostedDate":{"S":"2025-11-04"}`, "attribute_exists", "SET", `"Domain"`, `"v2"`} {
+				if !strings.Contains(payload, want) {
+					t.Fatalf("expected %s in payload %s", want, payload)
+				}
+			}
+			if strings.Contains(payload, `"Title"`) {
+				t.Fatalf("expected scraped fields to be left alone, got %s", payload)
+			}
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Fatalf("unexpected target %s", r.Header.Get("X-Amz-Target"))
+		}
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	job := testJob
+	job.PostedDate = "2025-11-04"
+	job.Domain = "Backend"
+	job.ExtractionVersion = "v2"
+	if err := client.UpdateJobEnrichment(context.Background(), &job); err != nil {
+		t.Fatalf("UpdateJobEnrichment returned error: %v", err)
+	}
+}
+
+func TestUpdateJobHistoryRewritesOnlyHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		defer r.Body.Close()
+		payload := string(body)
+		for _, want := range []string{`"ClosedAt"`, `"History"`, `"disappeared"`, "attribute_exists"} {
+			if !strings.Contains(payload, want) {
+				t.Fatalf("expected %s in payload %s", want, payload)
+			}
+		}
+		if strings.Contains(payload, `"Domain"`) || strings.Contains(payload, `"Title"`) {
+			t.Fatalf("expected other fields to be left alone, got %s", payload)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		fmt.Fprint(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	job := testJob
+	job.PostedDate = "2025-11-04"
+	job.ClosedAt = "2025-11-10T08:00:00Z"
+	job.History = []models.PostingEvent{{Type: models.PostingEventDisappeared, At: job.ClosedAt}}
+	if err := client.UpdateJobHistory(context.Background(), &job); err != nil {
+		t.Fatalf("UpdateJobHistory returned error: %v", err)
+	}
+}
+
+func TestUpdateJobEnrichmentReportsMissingJob(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`)
+	}))
+	defer server.Close()
+
+	client := newTestDynamoClient(server.URL)
+	job := testJob
+	job.PostedDate = "2025-11-04"
+	if err := client.UpdateJobEnrichment(context.Background(), &job); !errors.Is(err, ErrJobNotStored) {
+		t.Fatalf("expected ErrJobNotStored, got %v", err)
+	}
+}
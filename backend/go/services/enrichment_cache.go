@@ -0,0 +1,190 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+// enrichmentPromptVersion changes whenever the extraction prompts or schema
+// do, so cached responses from an older prompt are never reused.
+var enrichmentPromptVersion = computeEnrichmentPromptVersion()
+
+// enrichmentPromptHash and enrichmentSchemaHash identify the prompts and the
+// schema separately in each job's Provenance.
+var (
+	enrichmentPromptHash = shortHash([]byte(jobExtractionDeveloperInstruction), []byte(yoeRetryInstruction))
+	enrichmentSchemaHash = shortHash(enrichmentSchemaJSON())
+)
+
+// EnrichmentCache maps a posting's content hash to the structured response
+// already paid for, so reposts under new record IDs skip the LLM.
+type EnrichmentCache interface {
+	// Key identifies a posting's content under this cache's prompt and model.
+	Key(title, description string) string
+	Get(key string) (EnrichmentCacheEntry, bool)
+	// Put stores a response with the provenance of the extraction that paid
+	// for it.
+	Put(key string, response models.OpenAIJobParsingResponse, provenance models.Provenance)
+	// Evict drops entries not used within maxAge, then the least recently
+	// used ones beyond maxEntries, and returns how many it dropped. A zero
+	// limit is not applied.
+	Evict(now time.Time, maxAge time.Duration, maxEntries int) int
+	// Entries returns a copy of the cache for persisting.
+	Entries() map[string]EnrichmentCacheEntry
+}
+
+// EnrichmentCacheEntry is one cached response, the provenance of the
+// extraction that produced it, and when it was last stored or served, which
+// eviction goes by.
+type EnrichmentCacheEntry struct {
+	Response   models.OpenAIJobParsingResponse `json:"response"`
+	Provenance *models.Provenance              `json:"provenance,omitempty"`
+	UsedAt     time.Time                       `json:"usedAt"`
+}
+
+type enrichmentCacheImpl struct {
+	mutex   sync.RWMutex
+	scope   string
+	entries map[string]EnrichmentCacheEntry
+}
+
+// NewEnrichmentCache seeds the cache with entries loaded from storage,
+// dropping any recorded under a different prompt version or by a different
+// model than scope, as built by EnrichmentCacheScope.
+func NewEnrichmentCache(scope string, entries map[string]EnrichmentCacheEntry) EnrichmentCache {
+	current := make(map[string]EnrichmentCacheEntry, len(entries))
+	prefix := enrichmentCacheKeyPrefix(scope)
+	for key, entry := range entries {
+		if strings.HasPrefix(key, prefix) {
+			current[key] = entry
+		}
+	}
+	return &enrichmentCacheImpl{scope: scope, entries: current}
+}
+
+func (c *enrichmentCacheImpl) Key(title, description string) string {
+	return EnrichmentCacheKey(c.scope, title, description)
+}
+
+// Get marks a hit as used, so entries that keep being reposted outlive ones
+// that are not.
+func (c *enrichmentCacheImpl) Get(key string) (EnrichmentCacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if ok {
+		entry.UsedAt = time.Now().UTC()
+		c.entries[key] = entry
+	}
+	return entry, ok
+}
+
+func (c *enrichmentCacheImpl) Put(key string, response models.OpenAIJobParsingResponse, provenance models.Provenance) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[key] = EnrichmentCacheEntry{Response: response, Provenance: &provenance, UsedAt: time.Now().UTC()}
+}
+
+func (c *enrichmentCacheImpl) Evict(now time.Time, maxAge time.Duration, maxEntries int) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	before := len(c.entries)
+	if maxAge > 0 {
+		cutoff := now.Add(-maxAge)
+		for key, entry := range c.entries {
+			if entry.UsedAt.Before(cutoff) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	if maxEntries > 0 && len(c.entries) > maxEntries {
+		keys := make([]string, 0, len(c.entries))
+		for key := range c.entries {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return c.entries[keys[i]].UsedAt.After(c.entries[keys[j]].UsedAt)
+		})
+		for _, key := range keys[maxEntries:] {
+			delete(c.entries, key)
+		}
+	}
+	return before - len(c.entries)
+}
+
+func (c *enrichmentCacheImpl) Entries() map[string]EnrichmentCacheEntry {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	entries := make(map[string]EnrichmentCacheEntry, len(c.entries))
+	for key, entry := range c.entries {
+		entries[key] = entry
+	}
+	return entries
+}
+
+// EnrichmentCacheScope names the provider and model whose responses a cache
+// holds, so switching either starts from an empty cache.
+func EnrichmentCacheScope(cfg config.Config) string {
+	provider := cfg.LLMProvider
+	if provider == "" {
+		provider = config.LLMProviderOpenAI
+	}
+	return provider + "/" + LLMModel(cfg)
+}
+
+// EnrichmentCacheKey hashes a posting's title and description after folding
+// case and whitespace, prefixed with the prompt version and a hash of scope.
+func EnrichmentCacheKey(scope, title, description string) string {
+	digest := sha256.New()
+	digest.Write([]byte(normalizeEnrichmentText(title)))
+	digest.Write([]byte{0})
+	digest.Write([]byte(normalizeEnrichmentText(description)))
+	return enrichmentCacheKeyPrefix(scope) + hex.EncodeToString(digest.Sum(nil))
+}
+
+func enrichmentCacheKeyPrefix(scope string) string {
+	return enrichmentPromptVersion + "-" + shortHash([]byte(scope)) + "-"
+}
+
+func normalizeEnrichmentText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// EnrichmentPromptVersion identifies the current extraction prompts and
+// schema; eval reports record it so baselines can be matched to prompts.
+func EnrichmentPromptVersion() string {
+	return enrichmentPromptVersion
+}
+
+func computeEnrichmentPromptVersion() string {
+	return shortHash([]byte(jobExtractionDeveloperInstruction), []byte(yoeRetryInstruction), enrichmentSchemaJSON())
+}
+
+func enrichmentSchemaJSON() []byte {
+	schema, err := json.Marshal(OpenAIJobParsingSchema)
+	if err != nil {
+		return nil
+	}
+	return schema
+}
+
+// shortHash joins parts with NUL separators, so the prompt version keeps the
+// value it had before the parts were hashed separately.
+func shortHash(parts ...[]byte) string {
+	digest := sha256.New()
+	for i, part := range parts {
+		if i > 0 {
+			digest.Write([]byte{0})
+		}
+		digest.Write(part)
+	}
+	return hex.EncodeToString(digest.Sum(nil))[:12]
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+const testCacheScope = "openai/gpt-4.1-nano"
+
+func TestEnrichmentCacheKeyNormalizesContent(t *testing.T) {
+	key := EnrichmentCacheKey(testCacheScope, "Backend Engineer", "Build  APIs.\n\nShip Go services.")
+	if repost := EnrichmentCacheKey(testCacheScope, "  backend engineer ", "build apis. ship go services."); repost != key {
+		t.Fatalf("expected reposted content to share a key, got %q and %q", key, repost)
+	}
+	if other := EnrichmentCacheKey(testCacheScope, "Backend Engineer", "Build APIs. Ship Rust services."); other == key {
+		t.Fatalf("expected different descriptions to get different keys")
+	}
+	if other := EnrichmentCacheKey(testCacheScope, "Frontend Engineer", "Build APIs. Ship Go services."); other == key {
+		t.Fatalf("expected different titles to get different keys")
+	}
+	if other := EnrichmentCacheKey("anthropic/claude-haiku-4-5", "Backend Engineer", "Build APIs. Ship Go services."); other == key {
+		t.Fatalf("expected a different model to get a different key")
+	}
+}
+
+func TestEnrichmentCacheScopeNamesProviderAndModel(t *testing.T) {
+	if scope := EnrichmentCacheScope(config.Config{}); scope != testCacheScope {
+		t.Fatalf("expected the default provider and model, got %q", scope)
+	}
+	if scope := EnrichmentCacheScope(config.Config{LLMProvider: config.LLMProviderAnthropic}); scope != "anthropic/"+anthropicDefaultModel {
+		t.Fatalf("expected the Anthropic default model, got %q", scope)
+	}
+	if scope := EnrichmentCacheScope(config.Config{LLMProvider: config.LLMProviderOpenAICompatible, LLMModel: "llama3"}); scope != "openai-compatible/llama3" {
+		t.Fatalf("expected the configured model, got %q", scope)
+	}
+}
+
+func TestNewEnrichmentCacheDropsOtherPromptVersionsAndModels(t *testing.T) {
+	current := EnrichmentCacheKey(testCacheScope, "Backend Engineer", "Go")
+	otherModel := EnrichmentCacheKey("openai/gpt-4.1", "Backend Engineer", "Go")
+	cache := NewEnrichmentCache(testCacheScope, map[string]EnrichmentCacheEntry{
+		current:                 {Response: models.OpenAIJobParsingResponse{Domain: "Backend"}},
+		otherModel:              {Response: models.OpenAIJobParsingResponse{Domain: "Frontend"}},
+		"000000000000-abcdef01": {Response: models.OpenAIJobParsingResponse{Domain: "Data"}},
+	})
+
+	entries := cache.Entries()
+	if len(entries) != 1 || entries[current].Response.Domain != "Backend" {
+		t.Fatalf("expected only the current prompt version and model to survive, got %+v", entries)
+	}
+}
+
+func TestEnrichmentCacheEvictsStaleThenLeastRecentlyUsed(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	key := func(title string) string { return EnrichmentCacheKey(testCacheScope, title, "") }
+	cache := NewEnrichmentCache(testCacheScope, map[string]EnrichmentCacheEntry{
+		key("stale"):  {UsedAt: now.AddDate(0, 0, -31)},
+		key("old"):    {UsedAt: now.AddDate(0, 0, -10)},
+		key("recent"): {UsedAt: now.AddDate(0, 0, -2)},
+		key("served"): {UsedAt: now.AddDate(0, 0, -20)},
+	})
+	if _, ok := cache.Get(key("served")); !ok {
+		t.Fatal("expected a cache hit")
+	}
+
+	if evicted := cache.Evict(now, 30*24*time.Hour, 2); evicted != 2 {
+		t.Fatalf("expected 2 evictions, got %d", evicted)
+	}
+	entries := cache.Entries()
+	if _, ok := entries[key("served")]; !ok {
+		t.Fatalf("expected the entry served this run to be kept, got %+v", entries)
+	}
+	if _, ok := entries[key("recent")]; !ok || len(entries) != 2 {
+		t.Fatalf("expected the most recently used entries to be kept, got %+v", entries)
+	}
+	if evicted := cache.Evict(now, 0, 0); evicted != 0 {
+		t.Fatalf("expected zero limits to keep everything, got %d evictions", evicted)
+	}
+}
+
+func TestParseWithStatsUsesEnrichmentCache(t *testing.T) {
+	yoe := 2
+	client := &fakeLLMClient{responses: []string{jobParsingJSON(t, models.OpenAIJobParsingResponse{
+		ParsedDescription:         "Build Go services",
+		MinYearsExperience:        &yoe,
+		Domain:                    "Backend",
+		IsSoftwareEngineerRelated: true,
+	})}, usage: models.TokenUsage{Model: "gpt-4.1-nano", PromptTokens: 900, CompletionTokens: 120}}
+	stats := &models.JobStats{}
+	cache := NewEnrichmentCache(testCacheScope, nil)
+	parser := NewCachedParserService(client, cache, stats)
+
+	first, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "1", Title: "Go Developer", Description: "Build Go services."})
+	if err != nil || first == nil {
+		t.Fatalf("expected first parse to succeed")
+	}
+	repost, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "2", Title: "Go developer", Description: "Build  Go services."})
+	if err != nil || repost == nil {
+		t.Fatalf("expected cached parse to succeed")
+	}
+
+	if client.sendCalls != 1 {
+		t.Fatalf("expected one LLM call for a repost, got %d", client.sendCalls)
+	}
+	if hits := atomic.LoadInt64(&stats.EnrichmentCacheHits); hits != 1 {
+		t.Fatalf("expected 1 cache hit, got %d", hits)
+	}
+	if repost.JobId != "2" || repost.Domain != "Backend" || repost.MinYearsExperience == nil || *repost.MinYearsExperience != 2 {
+		t.Fatalf("unexpected cached job: %+v", repost)
+	}
+	if repost.Description != "" {
+		t.Fatalf("expected cached parse to clear the raw description")
+	}
+	if repost.Provenance == nil || !repost.Provenance.Cached || repost.Provenance.PromptTokens != 0 || repost.Provenance.CompletionTokens != 0 {
+		t.Fatalf("expected cached provenance without tokens, got %+v", repost.Provenance)
+	}
+	if repost.Provenance.Model != "gpt-4.1-nano" || repost.Provenance.ExtractedAt != first.Provenance.ExtractedAt {
+		t.Fatalf("expected the original extraction's model and time, got %+v", repost.Provenance)
+	}
+	if first.Provenance.Cached || first.Provenance.PromptTokens != 900 {
+		t.Fatalf("expected the original provenance to be left alone, got %+v", first.Provenance)
+	}
+}
+
+func TestParseWithStatsDoesNotCacheFailures(t *testing.T) {
+	client := &fakeLLMClient{responses: []string{"not json"}}
+	cache := NewEnrichmentCache(testCacheScope, nil)
+	parser := NewCachedParserService(client, cache, &models.JobStats{})
+
+	for i := 0; i < 2; i++ {
+		if job, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "1", Title: "Go Developer"}); err == nil || job == nil || !job.LowConfidence {
+			t.Fatalf("expected a low-confidence fallback, got err=%v job=%+v", err, job)
+		}
+	}
+	if client.sendCalls != 2 || len(cache.Entries()) != 0 {
+		t.Fatalf("expected failures to bypass the cache, got %d calls and %d entries", client.sendCalls, len(cache.Entries()))
+	}
+}
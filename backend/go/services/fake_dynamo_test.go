@@ -0,0 +1,392 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"unicode"
+)
+
+// fakeDynamoIndexes are the hash and range keys of the table's indexes, as in
+// infra/terraform/go-serverless/main.tf.
+var fakeDynamoIndexes = map[string][2]string{
+	"":                  {"JobId", "PostedDate"},
+	postedDateIndexName: {"PostedDate", "PostedTime"},
+	companyIndexName:    {"Company", "PostedDate"},
+	domainIndexName:     {"Domain", "PostedDate"},
+}
+
+// fakeDynamoTable serves the calls the DynamoDB job store makes from an
+// in-memory jobs table, evaluating the condition, key and update expressions
+// the expression builder writes. Items stay in their JSON attribute value
+// form.
+type fakeDynamoTable struct {
+	mu    sync.Mutex
+	items map[string]map[string]any
+}
+
+// newFakeDynamoStore returns a DynamoDB job store backed by a fresh
+// fakeDynamoTable.
+func newFakeDynamoStore(t *testing.T) JobStore {
+	t.Helper()
+	table := &fakeDynamoTable{items: make(map[string]map[string]any)}
+	server := httptest.NewServer(table)
+	t.Cleanup(server.Close)
+	return newTestDynamoClient(server.URL)
+}
+
+type fakeDynamoRequest struct {
+	Key                       map[string]any
+	Item                      map[string]any
+	IndexName                 string
+	ConditionExpression       string
+	KeyConditionExpression    string
+	FilterExpression          string
+	UpdateExpression          string
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]any
+	ScanIndexForward          *bool
+	RequestItems              map[string]json.RawMessage
+}
+
+func (f *fakeDynamoTable) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req fakeDynamoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		fakeDynamoError(w, "SerializationException", err.Error())
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer func() {
+		if r := recover(); r != nil {
+			fakeDynamoError(w, "ValidationException", fmt.Sprint(r))
+		}
+	}()
+
+	expr := fakeExpression{names: req.ExpressionAttributeNames, values: req.ExpressionAttributeValues}
+	var response any = map[string]any{}
+	switch target := strings.TrimPrefix(r.Header.Get("X-Amz-Target"), "DynamoDB_20120810."); target {
+	case "PutItem":
+		key := fakeItemKey(req.Item)
+		if req.ConditionExpression != "" && !expr.condition(req.ConditionExpression, f.items[key]) {
+			fakeDynamoError(w, "ConditionalCheckFailedException", "The conditional request failed")
+			return
+		}
+		f.items[key] = req.Item
+	case "UpdateItem":
+		key := fakeItemKey(req.Key)
+		current := f.items[key]
+		if req.ConditionExpression != "" && !expr.condition(req.ConditionExpression, current) {
+			fakeDynamoError(w, "ConditionalCheckFailedException", "The conditional request failed")
+			return
+		}
+		next := make(map[string]any, len(current)+len(req.Key))
+		for name, value := range current {
+			next[name] = value
+		}
+		for name, value := range req.Key {
+			next[name] = value
+		}
+		expr.update(req.UpdateExpression, next)
+		f.items[key] = next
+	case "BatchGetItem":
+		responses := make(map[string][]map[string]any)
+		for table, raw := range req.RequestItems {
+			var keys struct{ Keys []map[string]any }
+			if err := json.Unmarshal(raw, &keys); err != nil {
+				fakeDynamoError(w, "SerializationException", err.Error())
+				return
+			}
+			responses[table] = []map[string]any{}
+			for _, key := range keys.Keys {
+				if item, ok := f.items[fakeItemKey(key)]; ok {
+					responses[table] = append(responses[table], item)
+				}
+			}
+		}
+		response = map[string]any{"Responses": responses, "UnprocessedKeys": map[string]any{}}
+	case "BatchWriteItem":
+		for _, raw := range req.RequestItems {
+			var requests []struct{ PutRequest struct{ Item map[string]any } }
+			if err := json.Unmarshal(raw, &requests); err != nil {
+				fakeDynamoError(w, "SerializationException", err.Error())
+				return
+			}
+			for _, request := range requests {
+				f.items[fakeItemKey(request.PutRequest.Item)] = request.PutRequest.Item
+			}
+		}
+		response = map[string]any{"UnprocessedItems": map[string]any{}}
+	case "Query":
+		schema, ok := fakeDynamoIndexes[req.IndexName]
+		if !ok {
+			fakeDynamoError(w, "ValidationException", "unknown index "+req.IndexName)
+			return
+		}
+		var items []map[string]any
+		for _, item := range f.items {
+			if _, indexed := item[schema[0]]; !indexed || !expr.condition(req.KeyConditionExpression, item) {
+				continue
+			}
+			if req.FilterExpression == "" || expr.condition(req.FilterExpression, item) {
+				items = append(items, item)
+			}
+		}
+		forward := req.ScanIndexForward == nil || *req.ScanIndexForward
+		sort.SliceStable(items, func(i, j int) bool {
+			order, _ := fakeCompare(items[i][schema[1]], items[j][schema[1]])
+			return forward && order < 0 || !forward && order > 0
+		})
+		response = map[string]any{"Items": fakeItems(items), "Count": len(items)}
+	case "Scan":
+		var items []map[string]any
+		for _, item := range f.items {
+			if req.FilterExpression == "" || expr.condition(req.FilterExpression, item) {
+				items = append(items, item)
+			}
+		}
+		response = map[string]any{"Items": fakeItems(items), "Count": len(items)}
+	default:
+		fakeDynamoError(w, "UnknownOperationException", target)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(response)
+}
+
+func fakeDynamoError(w http.ResponseWriter, code, message string) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"__type": "com.amazonaws.dynamodb.v20120810#" + code, "message": message})
+}
+
+func fakeItemKey(item map[string]any) string {
+	return fakeString(item["JobId"]) + "\x00" + fakeString(item["PostedDate"])
+}
+
+func fakeItems(items []map[string]any) []map[string]any {
+	if items == nil {
+		return []map[string]any{}
+	}
+	return items
+}
+
+func fakeString(value any) string {
+	attribute, _ := value.(map[string]any)
+	s, _ := attribute["S"].(string)
+	return s
+}
+
+// fakeCompare orders two string or two number attribute values.
+func fakeCompare(a, b any) (int, bool) {
+	left, _ := a.(map[string]any)
+	right, _ := b.(map[string]any)
+	if l, ok := left["S"].(string); ok {
+		if r, ok := right["S"].(string); ok {
+			return strings.Compare(l, r), true
+		}
+	}
+	if l, ok := left["N"].(string); ok {
+		if r, ok := right["N"].(string); ok {
+			x, _ := strconv.ParseFloat(l, 64)
+			y, _ := strconv.ParseFloat(r, 64)
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// fakeExpression evaluates the subset of the expression grammar the job
+// store uses against one item.
+type fakeExpression struct {
+	names  map[string]string
+	values map[string]any
+	tokens []string
+	pos    int
+	item   map[string]any
+}
+
+func (e fakeExpression) condition(source string, item map[string]any) bool {
+	e.tokens, e.pos, e.item = fakeTokens(source), 0, item
+	return e.or()
+}
+
+func (e fakeExpression) update(source string, item map[string]any) {
+	e.tokens, e.pos, e.item = fakeTokens(source), 0, item
+	for e.pos < len(e.tokens) {
+		clause := e.next()
+		for e.pos < len(e.tokens) && e.peek() != "SET" && e.peek() != "REMOVE" {
+			name := e.names[e.next()]
+			if clause == "REMOVE" {
+				delete(item, name)
+			} else {
+				e.expect("=")
+				value, _ := e.value()
+				item[name] = value
+			}
+			if e.peek() == "," {
+				e.next()
+			}
+		}
+	}
+}
+
+func (e *fakeExpression) or() bool {
+	result := e.and()
+	for e.peek() == "OR" {
+		e.next()
+		result = e.and() || result
+	}
+	return result
+}
+
+func (e *fakeExpression) and() bool {
+	result := e.not()
+	for e.peek() == "AND" {
+		e.next()
+		result = e.not() && result
+	}
+	return result
+}
+
+func (e *fakeExpression) not() bool {
+	if e.peek() == "NOT" {
+		e.next()
+		return !e.not()
+	}
+	return e.primary()
+}
+
+func (e *fakeExpression) primary() bool {
+	switch e.peek() {
+	case "(":
+		e.next()
+		result := e.or()
+		e.expect(")")
+		return result
+	case "attribute_exists", "attribute_not_exists":
+		function := e.next()
+		e.expect("(")
+		_, exists := e.value()
+		e.expect(")")
+		return exists == (function == "attribute_exists")
+	}
+	left, _ := e.value()
+	switch op := e.next(); op {
+	case "BETWEEN":
+		low, _ := e.value()
+		e.expect("AND")
+		high, _ := e.value()
+		lower, ok1 := fakeCompare(left, low)
+		upper, ok2 := fakeCompare(left, high)
+		return ok1 && ok2 && lower >= 0 && upper <= 0
+	case "=":
+		right, _ := e.value()
+		return reflect.DeepEqual(left, right)
+	case "<>":
+		right, _ := e.value()
+		return !reflect.DeepEqual(left, right)
+	default:
+		right, _ := e.value()
+		order, ok := fakeCompare(left, right)
+		return ok && (op == "<" && order < 0 || op == "<=" && order <= 0 || op == ">" && order > 0 || op == ">=" && order >= 0)
+	}
+}
+
+// value reads an operand: a placeholder or bare attribute name, a value,
+// if_not_exists or a sum of numbers. It reports whether the operand exists.
+func (e *fakeExpression) value() (any, bool) {
+	var value any
+	var exists bool
+	switch token := e.next(); {
+	case token == "if_not_exists":
+		e.expect("(")
+		current, ok := e.value()
+		e.expect(",")
+		fallback, _ := e.value()
+		e.expect(")")
+		value, exists = current, ok
+		if !ok {
+			value, exists = fallback, true
+		}
+	case strings.HasPrefix(token, "#"):
+		value, exists = e.item[e.names[token]]
+	case strings.HasPrefix(token, ":"):
+		value, exists = e.values[token]
+	case token != "" && unicode.IsLetter(rune(token[0])):
+		value, exists = e.item[token]
+	default:
+		panic(fmt.Sprintf("fake dynamo: unexpected token %q", token))
+	}
+	if e.peek() == "+" {
+		e.next()
+		other, _ := e.value()
+		left, _ := value.(map[string]any)
+		right, _ := other.(map[string]any)
+		x, _ := strconv.ParseFloat(fmt.Sprint(left["N"]), 64)
+		y, _ := strconv.ParseFloat(fmt.Sprint(right["N"]), 64)
+		value = map[string]any{"N": strconv.FormatFloat(x+y, 'f', -1, 64)}
+	}
+	return value, exists
+}
+
+func (e *fakeExpression) peek() string {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos]
+	}
+	return ""
+}
+
+func (e *fakeExpression) next() string {
+	token := e.peek()
+	e.pos++
+	return token
+}
+
+func (e *fakeExpression) expect(token string) {
+	if got := e.next(); got != token {
+		panic(fmt.Sprintf("fake dynamo: expected %q, got %q", token, got))
+	}
+}
+
+func fakeTokens(source string) []string {
+	var tokens []string
+	for i := 0; i < len(source); {
+		r := rune(source[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.ContainsRune("(),+=", r):
+			tokens = append(tokens, string(r))
+			i++
+		case r == '<' || r == '>':
+			end := i + 1
+			if end < len(source) && (source[end] == '=' || source[end] == '>') {
+				end++
+			}
+			tokens = append(tokens, source[i:end])
+			i = end
+		default:
+			end := i + 1
+			for end < len(source) && (source[end] == '_' || unicode.IsLetter(rune(source[end])) || unicode.IsDigit(rune(source[end]))) {
+				end++
+			}
+			tokens = append(tokens, source[i:end])
+			i = end
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopher-source/models"
+)
+
+const (
+	fallbackSummaryMaxRune = 500
+	// FallbackExtractionVersion is the ExtractionVersion of jobs enriched by
+	// FallbackParse rather than a prompt.
+	FallbackExtractionVersion = "fallback"
+)
+
+var (
+	// "5+ years of experience", "3-5 years of professional experience";
+	// spelled-out numbers are left to the LLM.
+	fallbackYearsPattern = regexp.MustCompile(`(?i)\b(\d{1,2})\s*(?:\+|plus)?\s*(?:(?:-|–|to)\s*\d{1,2}\s*\+?\s*)?(?:years?|yrs?)\b[^.;\n]{0,60}?\bexperience`)
+	fallbackEntryPattern = regexp.MustCompile(`(?i)\b(new grad(?:uate)?s?|entry[- ]level|no (?:prior |professional )?experience (?:is )?required)\b`)
+	// Seniority rules out 0 but never supplies a number, matching the prompt.
+	fallbackSeniorTitlePattern = regexp.MustCompile(`(?i)\b(senior|sr\.?|staff|principal|director|lead)\b`)
+
+	// Bare abbreviations are matched case-sensitively so "ms" latencies and
+	// "ba" in other words do not count.
+	fallbackBachelorPattern = regexp.MustCompile(`(?i:\bbachelor'?s?\b|\bb\.[sa]\.)|\b(?:BS|BA)\b`)
+	fallbackMasterPattern   = regexp.MustCompile(`(?i:\bmaster'?s? (?:degree|of|in)\b|\bm\.s\.)|\bMS\b`)
+	fallbackPhDPattern      = regexp.MustCompile(`(?i)\b(?:ph\.?\s?d|doctorate|doctoral)\b`)
+
+	fallbackHybridPattern = regexp.MustCompile(`(?i)\bhybrid\b|\bdays? (?:a|per) week (?:in|on)[- ]?(?:site|the office|office)\b`)
+	fallbackRemotePattern = regexp.MustCompile(`(?i)\b(fully remote|100% remote|remote[- ]first|work from home|remote)\b`)
+	fallbackOnsitePattern = regexp.MustCompile(`(?i)\b(on[- ]?site|in[- ]office|in the office)\b`)
+
+	fallbackUnrelatedTitlePattern = regexp.MustCompile(`(?i)\b(manager|designer|sales|support|recruiter|analyst|technician|administrator|coordinator|specialist|consultant)\b`)
+	fallbackEngineerTitlePattern  = regexp.MustCompile(`(?i)\b(engineer|engineering|developer|programmer|sde|swe|scientist|architect)\b`)
+)
+
+type fallbackTerm struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// term matches name or an alias case-insensitively. Names ending in symbols
+// such as C++ cannot rely on \b, so the boundaries are spelled out.
+func term(name string, aliases ...string) fallbackTerm {
+	return newFallbackTerm("(?i)", name, aliases)
+}
+
+// properNoun matches only the capitalized spelling, for names that are also
+// ordinary words ("go", "swift", "react").
+func properNoun(name string, aliases ...string) fallbackTerm {
+	return newFallbackTerm("", name, aliases)
+}
+
+func newFallbackTerm(flags, name string, aliases []string) fallbackTerm {
+	alternatives := make([]string, 0, len(aliases)+1)
+	for _, alias := range append([]string{name}, aliases...) {
+		alternatives = append(alternatives, regexp.QuoteMeta(alias))
+	}
+	return fallbackTerm{
+		name:    name,
+		pattern: regexp.MustCompile(flags + `(?:^|[^\w+#.])(?:` + strings.Join(alternatives, "|") + `)(?:$|[^\w+#])`),
+	}
+}
+
+// fallbackLanguages is deliberately short: a missed language costs less than
+// a false one on a record that will be re-enriched anyway.
+var fallbackLanguages = []fallbackTerm{
+	properNoun("Go", "Golang"),
+	term("Python"),
+	term("Java"),
+	term("JavaScript"),
+	term("TypeScript"),
+	term("C++"),
+	term("C#"),
+	properNoun("Rust"),
+	term("Ruby"),
+	term("Kotlin"),
+	properNoun("Swift"),
+	term("Scala"),
+	term("PHP"),
+	term("SQL"),
+	term("Bash"),
+}
+
+var fallbackTechnologies = []fallbackTerm{
+	term("AWS", "Amazon Web Services"),
+	term("Azure"),
+	term("GCP", "Google Cloud"),
+	term("Docker"),
+	term("Kubernetes", "k8s"),
+	term("Terraform"),
+	term("Jenkins"),
+	term("Kafka"),
+	properNoun("Spark"),
+	term("Airflow"),
+	term("Snowflake"),
+	term("PostgreSQL", "Postgres"),
+	term("MySQL"),
+	term("MongoDB"),
+	term("Redis"),
+	term("DynamoDB"),
+	term("Elasticsearch"),
+	term("GraphQL"),
+	properNoun("React"),
+	term("Angular"),
+	term("Vue", "Vue.js"),
+	term("Node.js", "NodeJS"),
+	term(".NET", "dotnet"),
+	properNoun("Spring", "Spring Boot"),
+	term("Django"),
+	term("Flask"),
+	term("PyTorch"),
+	term("TensorFlow"),
+	term("Git"),
+	term("Linux"),
+	term("iOS"),
+	term("Android"),
+}
+
+// fallbackDomains is checked in order against the title, then the
+// description; the first match wins.
+var fallbackDomains = []struct {
+	domain  string
+	pattern *regexp.Regexp
+}{
+	{"Site Reliability", regexp.MustCompile(`(?i)\b(site reliability|sre)\b`)},
+	{"DevOps", regexp.MustCompile(`(?i)\b(devops|platform engineer|infrastructure|ci/cd)\b`)},
+	{"Security", regexp.MustCompile(`(?i)\b(security|appsec|cybersecurity)\b`)},
+	{"AI/ML", regexp.MustCompile(`(?i)\b(machine learning|ml|ai|deep learning|llm)\b`)},
+	{"Data", regexp.MustCompile(`(?i)\b(data engineer|data scientist|data platform|analytics|etl)\b`)},
+	{"QA", regexp.MustCompile(`(?i)\b(qa|quality assurance|sdet|test automation)\b`)},
+	{"Mobile", regexp.MustCompile(`(?i)\b(mobile|ios|android)\b`)},
+	{"Embedded Systems", regexp.MustCompile(`(?i)\b(embedded|firmware)\b`)},
+	{"Networking", regexp.MustCompile(`(?i)\b(network|networking)\b`)},
+	{"Gaming", regexp.MustCompile(`(?i)\b(game|gaming|gameplay)\b`)},
+	{"Full-Stack", regexp.MustCompile(`(?i)\b(full[- ]?stack)\b`)},
+	{"Front-End", regexp.MustCompile(`(?i)\b(front[- ]?end|ui engineer)\b`)},
+	{"Backend", regexp.MustCompile(`(?i)\b(back[- ]?end|server[- ]side|microservices?|apis?)\b`)},
+}
+
+// FallbackParse extracts what simple rules can find in a posting, for when
+// the LLM is unavailable. Its answers are guesses; callers mark the job
+// LowConfidence so it can be re-enriched. It never invents a deadline, and
+// leaves Modality empty when the text names none, so the listing's closing
+// date and location type are kept.
+func FallbackParse(job *models.Job) models.OpenAIJobParsingResponse {
+	text := job.Title + "\n" + job.Description
+	return models.OpenAIJobParsingResponse{
+		ParsedDescription:         fallbackSummary(job.Description),
+		MinDegree:                 fallbackDegree(job.Description),
+		MinYearsExperience:        fallbackYears(job.Title, job.Description),
+		Modality:                  fallbackModality(text),
+		Domain:                    fallbackDomain(job.Title, job.Description),
+		Seniority:                 titleSeniority(job.Title),
+		Languages:                 matchFallbackTerms(fallbackLanguages, text),
+		Technologies:              matchFallbackTerms(fallbackTechnologies, text),
+		IsSoftwareEngineerRelated: fallbackIsSoftwareEngineer(job.Title),
+	}
+}
+
+// FallbackJob returns a copy of job enriched by FallbackParse. It keeps the
+// original description, which re-enrichment needs.
+func FallbackJob(job *models.Job) *models.Job {
+	fallbackJob := *job
+	populateJobFromResponse(&fallbackJob, FallbackParse(job))
+	fallbackJob.Description = job.Description
+	fallbackJob.LowConfidence = true
+	fallbackJob.ExtractionVersion = FallbackExtractionVersion
+	fallbackJob.Provenance = &models.Provenance{ExtractedAt: time.Now().UTC().Format(time.RFC3339)}
+	return &fallbackJob
+}
+
+func fallbackYears(title, description string) *int {
+	var lowest *int
+	for _, match := range fallbackYearsPattern.FindAllStringSubmatch(description, -1) {
+		years, err := strconv.Atoi(match[1])
+		if err != nil || years > 25 {
+			continue
+		}
+		if lowest == nil || years < *lowest {
+			lowest = &years
+		}
+	}
+	if lowest != nil {
+		return lowest
+	}
+	if fallbackEntryPattern.MatchString(title+"\n"+description) && !fallbackSeniorTitlePattern.MatchString(title) {
+		zero := 0
+		return &zero
+	}
+	return nil
+}
+
+// fallbackDegree returns the lowest degree mentioned, since postings list
+// alternatives such as "BS, MS, or PhD".
+func fallbackDegree(description string) string {
+	switch {
+	case fallbackBachelorPattern.MatchString(description):
+		return "Bachelor's"
+	case fallbackMasterPattern.MatchString(description):
+		return "Master's"
+	case fallbackPhDPattern.MatchString(description):
+		return "Ph.D"
+	default:
+		return "Unspecified"
+	}
+}
+
+func fallbackModality(text string) string {
+	switch {
+	case fallbackHybridPattern.MatchString(text):
+		return "Hybrid"
+	case fallbackRemotePattern.MatchString(text):
+		return "Remote"
+	case fallbackOnsitePattern.MatchString(text):
+		return "In-Office"
+	default:
+		return ""
+	}
+}
+
+func fallbackDomain(title, description string) string {
+	for _, text := range []string{title, description} {
+		for _, candidate := range fallbackDomains {
+			if candidate.pattern.MatchString(text) {
+				return candidate.domain
+			}
+		}
+	}
+	return "Other"
+}
+
+func fallbackIsSoftwareEngineer(title string) bool {
+	return fallbackEngineerTitlePattern.MatchString(title) && !fallbackUnrelatedTitlePattern.MatchString(title)
+}
+
+func matchFallbackTerms(terms []fallbackTerm, text string) []string {
+	var matched []string
+	for _, candidate := range terms {
+		if candidate.pattern.MatchString(text) {
+			matched = append(matched, candidate.name)
+		}
+	}
+	return matched
+}
+
+func fallbackSummary(description string) string {
+	summary := strings.Join(strings.Fields(description), " ")
+	runes := []rune(summary)
+	if len(runes) <= fallbackSummaryMaxRune {
+		return summary
+	}
+	cut := string(runes[:fallbackSummaryMaxRune])
+	if space := strings.LastIndex(cut, " "); space > 0 {
+		cut = cut[:space]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestFallbackParse(t *testing.T) {
+	tests := []struct {
+		name string
+		job  models.Job
+		want models.OpenAIJobParsingResponse
+	}{
+		{
+			name: "experience range and hybrid",
+			job: models.Job{
+				Title:       "Senior Backend Engineer",
+				Description: "Hybrid role. BS in Computer Science, MS preferred. Requires 5-7 years of software engineering experience and Kafka. We use Go, C++, and PostgreSQL on AWS with Kubernetes.",
+			},
+			want: models.OpenAIJobParsingResponse{
+				MinDegree:                 "Bachelor's",
+				MinYearsExperience:        intPtr(5),
+				Modality:                  "Hybrid",
+				Domain:                    "Backend",
+				Seniority:                 "Senior",
+				Languages:                 []string{"Go", "C++"},
+				Technologies:              []string{"AWS", "Kubernetes", "Kafka", "PostgreSQL"},
+				IsSoftwareEngineerRelated: true,
+			},
+		},
+		{
+			name: "new grad remote front end",
+			job: models.Job{
+				Title:       "Software Engineer I, Front-End",
+				Description: "Fully remote. New graduates welcome. You will build JavaScript and TypeScript apps in React and react quickly to feedback.",
+			},
+			want: models.OpenAIJobParsingResponse{
+				MinDegree:                 "Unspecified",
+				MinYearsExperience:        intPtr(0),
+				Modality:                  "Remote",
+				Domain:                    "Front-End",
+				Seniority:                 "Entry",
+				Languages:                 []string{"JavaScript", "TypeScript"},
+				Technologies:              []string{"React"},
+				IsSoftwareEngineerRelated: true,
+			},
+		},
+		{
+			name: "senior title never falls back to zero",
+			job: models.Job{
+				Title:       "Staff Engineer",
+				Description: "Entry-level mentoring is part of the role. Ph.D required. Onsite.",
+			},
+			want: models.OpenAIJobParsingResponse{
+				MinDegree:                 "Ph.D",
+				Modality:                  "In-Office",
+				Domain:                    "Other",
+				Seniority:                 "Staff",
+				IsSoftwareEngineerRelated: true,
+			},
+		},
+		{
+			name: "non engineering title",
+			job: models.Job{
+				Title:       "Engineering Manager, Security",
+				Description: "Lead a team of 8 engineers. 200 ms latency budgets. Master's degree in Computer Science.",
+			},
+			want: models.OpenAIJobParsingResponse{
+				MinDegree: "Master's",
+				Domain:    "Security",
+				Seniority: "Manager",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FallbackParse(&tt.job)
+			if got.DeadlineDate != "" || got.ParsedDescription == "" {
+				t.Fatalf("expected no deadline and a summary, got %+v", got)
+			}
+			got.ParsedDescription = ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("FallbackParse() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFallbackJobKeepsListingClosingDateAndModality(t *testing.T) {
+	job := models.Job{
+		Title:       "Software Engineer",
+		Description: "Build services in Go.",
+		ExpiresDate: "2026-11-30",
+		Modality:    "Hybrid",
+	}
+	got := FallbackJob(&job)
+	if got.ExpiresDate != "2026-11-30" || got.Modality != "Hybrid" {
+		t.Fatalf("expected the scraped closing date and modality to be kept, got %q and %q", got.ExpiresDate, got.Modality)
+	}
+	if !got.LowConfidence {
+		t.Fatalf("expected a low-confidence job, got %+v", got)
+	}
+}
+
+func TestFallbackSummaryTruncatesOnWordBoundary(t *testing.T) {
+	description := ""
+	for len(description) < 2*fallbackSummaryMaxRune {
+		description += "distributed systems "
+	}
+	summary := fallbackSummary(description)
+	if len([]rune(summary)) > fallbackSummaryMaxRune+1 || summary[len(summary)-len("…"):] != "…" {
+		t.Fatalf("expected a truncated summary, got %d runes: %q", len([]rune(summary)), summary)
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
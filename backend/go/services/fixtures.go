@@ -0,0 +1,200 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/utils"
+)
+
+// httpFixture is one recorded exchange. JSON bodies are stored inline so
+// fixtures stay reviewable; anything else is kept as text.
+type httpFixture struct {
+	Request  httpFixtureRequest  `json:"request"`
+	Response httpFixtureResponse `json:"response"`
+}
+
+type httpFixtureRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Text   string          `json:"text,omitempty"`
+}
+
+type httpFixtureResponse struct {
+	StatusCode  int             `json:"statusCode"`
+	ContentType string          `json:"contentType,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+type fixtureTransport struct {
+	mode string
+	dir  string
+	next http.RoundTripper
+}
+
+// NewHTTPClient returns the client used for every outbound API call, recording
+// to or replaying from cfg.HTTPFixtureDir when cfg.HTTPFixtureMode is set.
+func NewHTTPClient(cfg config.Config, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewFixtureTransport(cfg.HTTPFixtureMode, cfg.HTTPFixtureDir, http.DefaultTransport),
+	}
+}
+
+// NewFixtureTransport wraps next so requests are captured to dir in record
+// mode and answered from dir in replay mode. Any other mode returns next.
+// Fixtures are keyed by method, URL, and request body, so replay does not
+// depend on the order in which concurrent requests are made.
+func NewFixtureTransport(mode, dir string, next http.RoundTripper) http.RoundTripper {
+	switch mode {
+	case config.HTTPFixtureRecord, config.HTTPFixtureReplay:
+		return &fixtureTransport{mode: mode, dir: dir, next: next}
+	default:
+		return next
+	}
+}
+
+func (f *fixtureTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		body, err := io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+		requestBody = body
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	path := f.fixturePath(request, requestBody)
+
+	if f.mode == config.HTTPFixtureReplay {
+		return f.replay(request, path)
+	}
+
+	response, err := f.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	fixture := httpFixture{
+		Request: httpFixtureRequest{Method: request.Method, URL: request.URL.String()},
+		Response: httpFixtureResponse{
+			StatusCode:  response.StatusCode,
+			ContentType: response.Header.Get("Content-Type"),
+		},
+	}
+	fixture.Request.Body, fixture.Request.Text = splitFixtureBody(requestBody)
+	fixture.Response.Body, fixture.Response.Text = splitFixtureBody(responseBody)
+	if err := writeFixture(path, fixture); err != nil {
+		return nil, err
+	}
+	utils.Debug(fmt.Sprintf("Recorded %s %s to %s", request.Method, request.URL, path))
+	return response, nil
+}
+
+func (f *fixtureTransport) replay(request *http.Request, path string) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no fixture for %s %s (expected %s)", request.Method, request.URL, path)
+		}
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+	var fixture httpFixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+
+	body := []byte(fixture.Response.Text)
+	if len(fixture.Response.Body) > 0 {
+		// fixtures are indented for review; APIs answer with compact JSON
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, fixture.Response.Body); err != nil {
+			return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+		}
+		body = compact.Bytes()
+	}
+	header := make(http.Header)
+	if fixture.Response.ContentType != "" {
+		header.Set("Content-Type", fixture.Response.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+// fixturePath groups fixtures by host and names them by a digest of the
+// request, e.g. api.openai.com/post-3f1c0a9b2d4e6f70.json.
+func (f *fixtureTransport) fixturePath(request *http.Request, body []byte) string {
+	digest := sha256.New()
+	digest.Write([]byte(request.Method + " " + request.URL.String() + "\n"))
+	digest.Write(body)
+	name := strings.ToLower(request.Method) + "-" + hex.EncodeToString(digest.Sum(nil))[:16] + ".json"
+	return filepath.Join(f.dir, request.URL.Hostname(), name)
+}
+
+func splitFixtureBody(body []byte) (json.RawMessage, string) {
+	if len(body) == 0 {
+		return nil, ""
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body), ""
+	}
+	return nil, string(body)
+}
+
+func writeFixture(path string, fixture httpFixture) error {
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create fixture directory: %w", err)
+	}
+	// concurrent identical requests may record the same fixture, so write
+	// through a temp file to keep each one whole
+	temp, err := os.CreateTemp(filepath.Dir(path), ".fixture-*")
+	if err != nil {
+		return fmt.Errorf("create fixture: %w", err)
+	}
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		os.Remove(temp.Name())
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("write fixture: %w", err)
+	}
+	return nil
+}
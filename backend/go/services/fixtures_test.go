@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+func TestFixtureTransportRecordsAndReplays(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "plain") {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("not json"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"returnValue":{"echo":` + string(body) + `}}`))
+	}))
+	dir := t.TempDir()
+
+	recorder := &http.Client{Transport: NewFixtureTransport(config.HTTPFixtureRecord, dir, http.DefaultTransport)}
+	recorded := postFixtureRequest(t, recorder, server.URL+"/apex?language=en-US", `{"method":"initializeJobSearch"}`)
+	recordedText := postFixtureRequest(t, recorder, server.URL+"/apex?language=en-US", "plain")
+	server.Close()
+	if calls != 2 {
+		t.Fatalf("expected record mode to reach the server, got %d call(s)", calls)
+	}
+
+	hostDir := filepath.Join(dir, "127.0.0.1")
+	entries, err := os.ReadDir(hostDir)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected two fixtures under %s, got %v (%v)", hostDir, entries, err)
+	}
+
+	replayer := &http.Client{Transport: NewFixtureTransport(config.HTTPFixtureReplay, dir, http.DefaultTransport)}
+	if got := postFixtureRequest(t, replayer, server.URL+"/apex?language=en-US", `{"method":"initializeJobSearch"}`); got != recorded {
+		t.Fatalf("replayed body %q, want %q", got, recorded)
+	}
+	if got := postFixtureRequest(t, replayer, server.URL+"/apex?language=en-US", "plain"); got != recordedText || got != "not json" {
+		t.Fatalf("replayed text %q, want %q", got, recordedText)
+	}
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/apex?language=en-US", strings.NewReader(`{"method":"loadMoreJobs"}`))
+	if _, err := replayer.Do(request); err == nil || !strings.Contains(err.Error(), "no fixture for POST") {
+		t.Fatalf("expected missing fixture error, got %v", err)
+	}
+}
+
+func TestFixtureTransportReplaysWorkSourceSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeSearchResponse(t, w, workSourceSearchResponse{
+			Jobs:       []workSourceJob{{RecordID: "record-1", JobTitle: "Go Developer", PostingDate: "2026-08-19T08:00:00.000Z"}},
+			TotalCount: 1,
+		})
+	}))
+	dir := t.TempDir()
+
+	scrape := func(mode string) []models.Job {
+		cfg := config.Config{MaxPages: 1, HTTPFixtureMode: mode, HTTPFixtureDir: dir}
+		source := NewWorkSourceJobSource(cfg).(*workSourceJobSource)
+		source.apexEndpoint = server.URL
+		jobs, err := source.SearchJobs(context.Background(), config.ScrapeQuery{Query: "go developer"})
+		if err != nil {
+			t.Fatalf("%s search returned error: %v", mode, err)
+		}
+		return jobs
+	}
+
+	recorded := scrape(config.HTTPFixtureRecord)
+	server.Close()
+	replayed := scrape(config.HTTPFixtureReplay)
+	if len(replayed) != 1 || !reflect.DeepEqual(replayed, recorded) {
+		t.Fatalf("replayed jobs %+v, want %+v", replayed, recorded)
+	}
+}
+
+func TestNewFixtureTransportPassesThroughWithoutMode(t *testing.T) {
+	if NewFixtureTransport("", t.TempDir(), http.DefaultTransport) != http.DefaultTransport {
+		t.Fatal("expected the base transport when no fixture mode is set")
+	}
+}
+
+func postFixtureRequest(t *testing.T, client *http.Client, endpoint, body string) string {
+	t.Helper()
+	response, err := client.Post(endpoint, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST %s returned error: %v", endpoint, err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	return string(data)
+}
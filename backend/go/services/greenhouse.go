@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/utils"
+)
+
+const (
+	GreenhouseSourceName      = "greenhouse"
+	greenhouseBoardsAPIPrefix = "https://boards-api.greenhouse.io/v1/boards/"
+)
+
+type greenhouseJobSource struct {
+	boardTokens []string
+	httpClient  *http.Client
+	baseURL     string
+}
+
+type greenhouseJobsResponse struct {
+	Jobs []greenhouseJob `json:"jobs"`
+}
+
+type greenhouseJob struct {
+	ID             int64              `json:"id"`
+	Title          string             `json:"title"`
+	CompanyName    string             `json:"company_name"`
+	Location       greenhouseLocation `json:"location"`
+	AbsoluteURL    string             `json:"absolute_url"`
+	UpdatedAt      string             `json:"updated_at"`
+	FirstPublished string             `json:"first_published"`
+	Content        string             `json:"content"`
+}
+
+type greenhouseLocation struct {
+	Name string `json:"name"`
+}
+
+func init() {
+	RegisterJobSource(GreenhouseSourceName, NewGreenhouseJobSource)
+}
+
+// NewGreenhouseJobSource reads the public Job Board API for each token in
+// cfg.GreenhouseBoardTokens.
+func NewGreenhouseJobSource(cfg config.Config) (JobSource, error) {
+	if len(cfg.GreenhouseBoardTokens) == 0 {
+		return nil, fmt.Errorf("GREENHOUSE_BOARD_TOKENS must list at least one board token")
+	}
+	return &greenhouseJobSource{
+		boardTokens: cfg.GreenhouseBoardTokens,
+		httpClient:  NewHTTPClient(cfg, 30*time.Second),
+		baseURL:     greenhouseBoardsAPIPrefix,
+	}, nil
+}
+
+func (g *greenhouseJobSource) Name() string {
+	return GreenhouseSourceName
+}
+
+func (g *greenhouseJobSource) SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error) {
+	var jobs []models.Job
+	var boardErrs []error
+	for _, token := range g.boardTokens {
+		if err := ctx.Err(); err != nil {
+			return jobs, err
+		}
+
+		endpoint := g.baseURL + url.PathEscape(token) + "/jobs?content=true"
+		response, err := fetchSourceJSON[greenhouseJobsResponse](ctx, g.httpClient, endpoint)
+		if err != nil {
+			log.Printf("Error fetching Greenhouse board %s: %v", token, err)
+			boardErrs = append(boardErrs, fmt.Errorf("board %s: %w", token, err))
+			continue
+		}
+
+		matched := 0
+		for _, posting := range response.Jobs {
+			if posting.ID == 0 || !matchesJobQuery(posting.Title, posting.Location.Name, query) {
+				continue
+			}
+			jobs = append(jobs, posting.toJob(token))
+			matched++
+		}
+		utils.Debug(fmt.Sprintf("Greenhouse board %s returned %d jobs, %d matching '%s'", token, len(response.Jobs), matched, query.Label()))
+	}
+	return jobs, errors.Join(boardErrs...)
+}
+
+func (job greenhouseJob) toJob(boardToken string) models.Job {
+	recordID := strconv.FormatInt(job.ID, 10)
+	location := fallbackScraperValue(job.Location.Name, "Unknown Location")
+	// the API returns entity-escaped HTML, so unescape before stripping tags
+	description := plainTextJobDescription(html.UnescapeString(job.Content))
+	if description == "" {
+		description = "No description available"
+	}
+	postedTime := fallbackScraperValue(job.FirstPublished, job.UpdatedAt)
+
+	return models.Job{
+		JobId:       GreenhouseSourceName + ":" + boardToken + ":" + recordID,
+		Source:      GreenhouseSourceName,
+		Title:       fallbackScraperValue(job.Title, "Unknown Title"),
+		Company:     fallbackScraperValue(job.CompanyName, boardToken),
+		Location:    location,
+		Modality:    modalityFromLocationName(location),
+		PostedDate:  postingDateOnly(postedTime),
+		PostedTime:  postedTime,
+		Salary:      formatWorkSourcePay("", ""),
+		URL:         job.AbsoluteURL,
+		Description: description,
+	}
+}
+
+// modalityFromLocationName recognizes the free-text location names ATS
+// boards use in place of a dedicated workplace field.
+func modalityFromLocationName(location string) string {
+	if modality := normalizeLocationType(location); modality != "" {
+		return modality
+	}
+	lower := strings.ToLower(location)
+	switch {
+	case strings.Contains(lower, "hybrid"):
+		return "Hybrid"
+	case strings.Contains(lower, "remote"):
+		return "Remote"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+)
+
+const greenhouseBoardPayload = `{
+  "jobs": [
+    {
+      "absolute_url": "https://boards.greenhouse.io/acme/jobs/4012345",
+      "data_compliance": [],
+      "internal_job_id": 3011111,
+      "location": {"name": "Seattle, WA (Hybrid)"},
+      "metadata": null,
+      "id": 4012345,
+      "updated_at": "2026-08-19T12:15:03-04:00",
+      "requisition_id": "ENG-101",
+      "title": "Senior Software Engineer, Platform",
+      "company_name": "Acme Robotics",
+      "first_published": "2026-08-18T09:00:00-07:00",
+      "content": "&lt;p&gt;Build &amp;amp; operate &lt;strong&gt;Go&lt;/strong&gt; services.&lt;/p&gt;&lt;ul&gt;&lt;li&gt;5+ years&lt;/li&gt;&lt;/ul&gt;"
+    },
+    {
+      "absolute_url": "https://boards.greenhouse.io/acme/jobs/4012399",
+      "location": {"name": "Remote - US"},
+      "id": 4012399,
+      "updated_at": "2026-08-17T08:00:00-04:00",
+      "title": "Account Executive",
+      "company_name": "Acme Robotics",
+      "content": "&lt;p&gt;Sell things.&lt;/p&gt;"
+    }
+  ],
+  "meta": {"total": 2}
+}`
+
+func TestGreenhouseSearchJobsMapsMatchingPostings(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/acme/jobs":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(greenhouseBoardPayload))
+		default:
+			http.Error(w, `{"status":404,"error":"Job board not found"}`, http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := newTestGreenhouseSource(t, server, "acme", "missing-board")
+	jobs, err := source.SearchJobs(context.Background(), config.ScrapeQuery{Query: "software engineer"})
+	if err == nil || !strings.Contains(err.Error(), "board missing-board") || !strings.Contains(err.Error(), "HTTP 404") {
+		t.Fatalf("expected missing board error, got %v", err)
+	}
+	if strings.Join(paths, ",") != "/acme/jobs?content=true,/missing-board/jobs?content=true" {
+		t.Fatalf("unexpected requests: %v", paths)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected only the engineering posting to match, got %+v", jobs)
+	}
+
+	job := jobs[0]
+	if job.JobId != "greenhouse:acme:4012345" || job.Source != GreenhouseSourceName {
+		t.Fatalf("unexpected identity fields: %+v", job)
+	}
+	if job.Title != "Senior Software Engineer, Platform" || job.Company != "Acme Robotics" || job.Location != "Seattle, WA (Hybrid)" {
+		t.Fatalf("unexpected mapped fields: %+v", job)
+	}
+	if job.Description != "Build & operate Go services. 5+ years" {
+		t.Fatalf("unexpected description: %q", job.Description)
+	}
+	if job.PostedDate != "2026-08-18" || job.PostedTime != "2026-08-18T09:00:00-07:00" {
+		t.Fatalf("unexpected posting dates: %+v", job)
+	}
+	if job.Modality != "Hybrid" || job.Salary != "Not specified" || job.URL != "https://boards.greenhouse.io/acme/jobs/4012345" {
+		t.Fatalf("unexpected normalized fields: %+v", job)
+	}
+}
+
+func TestNewGreenhouseJobSourceRequiresBoardTokens(t *testing.T) {
+	if _, err := NewGreenhouseJobSource(config.Config{}); err == nil || !strings.Contains(err.Error(), "GREENHOUSE_BOARD_TOKENS") {
+		t.Fatalf("expected missing token error, got %v", err)
+	}
+}
+
+func newTestGreenhouseSource(t *testing.T, server *httptest.Server, tokens ...string) *greenhouseJobSource {
+	t.Helper()
+	source, err := NewGreenhouseJobSource(config.Config{GreenhouseBoardTokens: tokens})
+	if err != nil {
+		t.Fatalf("NewGreenhouseJobSource returned error: %v", err)
+	}
+	impl := source.(*greenhouseJobSource)
+	impl.httpClient = server.Client()
+	impl.baseURL = server.URL + "/"
+	return impl
+}
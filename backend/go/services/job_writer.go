@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gopher-source/models"
+)
+
+const (
+	// a partial batch waits at most this long for more jobs
+	jobWriterFlushInterval = 2 * time.Second
+	// writes still buffered when the context ends get this long to finish
+	jobWriterDrainTimeout = 10 * time.Second
+)
+
+// JobWriter coalesces jobs into PutJobs calls of up to 25 jobs, the size of
+// one DynamoDB BatchWriteItem call, and records their throughput in stats.
+type JobWriter struct {
+	store     JobStore
+	stats     *models.JobStats
+	batchSize int
+	interval  time.Duration
+}
+
+// FailedBatch is a batch of jobs PutJobs could not store.
+type FailedBatch struct {
+	Jobs []models.Job
+	Err  error
+}
+
+func NewJobWriter(store JobStore, stats *models.JobStats) *JobWriter {
+	return &JobWriter{store: store, stats: stats, batchSize: maxBatchWriteItems, interval: jobWriterFlushInterval}
+}
+
+// Run stores the jobs received on jobs until it is closed, writing a batch
+// once it is full or has waited a flush interval. When ctx ends the buffered
+// and later jobs are still written, under a short drain timeout, so no job
+// sent to the writer is dropped. Batches that fail are logged and returned
+// whole; inserts skip stored jobs, so storing one again is safe.
+func (w *JobWriter) Run(ctx context.Context, jobs <-chan models.Job) []FailedBatch {
+	var failed []FailedBatch
+	writeCtx := ctx
+	done := ctx.Done()
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	buffer := make([]models.Job, 0, w.batchSize)
+	flush := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		start := time.Now()
+		result, err := w.store.PutJobs(writeCtx, buffer)
+		if w.stats != nil {
+			w.stats.RecordWrites(result.Stored, result.Calls, result.Retries, time.Since(start))
+		}
+		if err != nil {
+			log.Printf("Failed to store a batch of %d job(s): %v", len(buffer), err)
+			failed = append(failed, FailedBatch{Jobs: append([]models.Job(nil), buffer...), Err: err})
+		}
+		buffer = buffer[:0]
+	}
+
+	for {
+		select {
+		case job, ok := <-jobs:
+			if !ok {
+				flush()
+				return failed
+			}
+			buffer = append(buffer, job)
+			if len(buffer) >= w.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-done:
+			var cancel context.CancelFunc
+			writeCtx, cancel = context.WithTimeout(context.WithoutCancel(ctx), jobWriterDrainTimeout)
+			defer cancel()
+			done = nil
+			flush()
+		}
+	}
+}
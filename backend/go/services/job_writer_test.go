@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"gopher-source/models"
+)
+
+// batchRecorder records the size of each PutJobs call it receives.
+type batchRecorder struct {
+	JobStore
+	mu      sync.Mutex
+	batches []int
+	ctxErrs []error
+}
+
+func (r *batchRecorder) PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error) {
+	r.mu.Lock()
+	r.batches = append(r.batches, len(jobs))
+	r.ctxErrs = append(r.ctxErrs, ctx.Err())
+	r.mu.Unlock()
+	return r.JobStore.PutJobs(ctx, jobs)
+}
+
+func TestJobWriterFlushesFullBatchesAndOnClose(t *testing.T) {
+	store := &batchRecorder{JobStore: NewMemoryStore()}
+	stats := &models.JobStats{}
+	writer := NewJobWriter(store, stats)
+	writer.interval = time.Hour
+
+	jobs := make(chan models.Job)
+	done := make(chan struct{})
+	go func() {
+		writer.Run(context.Background(), jobs)
+		close(done)
+	}()
+	for i := 0; i < 60; i++ {
+		jobs <- models.Job{JobId: string(rune('a' + i)), PostedDate: "2026-10-15"}
+	}
+	close(jobs)
+	<-done
+
+	if len(store.batches) != 3 || store.batches[0] != 25 || store.batches[1] != 25 || store.batches[2] != 10 {
+		t.Fatalf("expected batches of 25, 25 and 10, got %v", store.batches)
+	}
+	snapshot := stats.Snapshot()
+	if snapshot.StoredJobs != 60 || snapshot.StoreCalls != 3 {
+		t.Fatalf("expected 60 stored jobs over 3 calls, got %+v", snapshot)
+	}
+}
+
+// failingStore fails every PutJobs call.
+type failingStore struct {
+	JobStore
+}
+
+func (failingStore) PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error) {
+	return WriteResult{Calls: 1}, errors.New("AccessDeniedException")
+}
+
+func TestJobWriterReturnsFailedBatches(t *testing.T) {
+	writer := NewJobWriter(failingStore{}, &models.JobStats{})
+	writer.interval = time.Hour
+
+	jobs := make(chan models.Job, 30)
+	for i := 0; i < 30; i++ {
+		jobs <- models.Job{JobId: string(rune('a' + i)), PostedDate: "2026-10-15"}
+	}
+	close(jobs)
+	failed := writer.Run(context.Background(), jobs)
+
+	if len(failed) != 2 || len(failed[0].Jobs) != 25 || len(failed[1].Jobs) != 5 || failed[0].Err == nil {
+		t.Fatalf("expected both batches returned with their error, got %+v", failed)
+	}
+	if failed[0].Jobs[0].JobId != "a" || failed[1].Jobs[0].JobId != string(rune('a'+25)) {
+		t.Fatalf("expected each failed batch to keep its own jobs, got %+v", failed)
+	}
+}
+
+func TestJobWriterDrainsAfterContextCancel(t *testing.T) {
+	store := &batchRecorder{JobStore: NewMemoryStore()}
+	writer := NewJobWriter(store, nil)
+	writer.interval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan models.Job)
+	done := make(chan struct{})
+	go func() {
+		writer.Run(ctx, jobs)
+		close(done)
+	}()
+	jobs <- models.Job{JobId: "1", PostedDate: "2026-10-15"}
+	cancel()
+	jobs <- models.Job{JobId: "2", PostedDate: "2026-10-15"}
+	close(jobs)
+	<-done
+
+	total := 0
+	for i, size := range store.batches {
+		total += size
+		if store.ctxErrs[i] != nil {
+			t.Fatalf("expected batches to be written with a live context, got %v", store.ctxErrs[i])
+		}
+	}
+	if total != 2 {
+		t.Fatalf("expected both jobs to be written after cancel, got batches %v", store.batches)
+	}
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+	"gopher-source/utils"
+)
+
+const (
+	LeverSourceName        = "lever"
+	leverPostingsAPIPrefix = "https://api.lever.co/v0/postings/"
+	leverPageSize          = 100
+)
+
+type leverJobSource struct {
+	config     config.Config
+	sites      []string
+	httpClient *http.Client
+	baseURL    string
+}
+
+type leverPosting struct {
+	ID            string            `json:"id"`
+	Text          string            `json:"text"`
+	Categories    leverCategories   `json:"categories"`
+	Description   string            `json:"description"`
+	Lists         []leverList       `json:"lists"`
+	Additional    string            `json:"additional"`
+	HostedURL     string            `json:"hostedUrl"`
+	CreatedAt     int64             `json:"createdAt"`
+	WorkplaceType string            `json:"workplaceType"`
+	SalaryRange   *leverSalaryRange `json:"salaryRange"`
+}
+
+type leverCategories struct {
+	Commitment string `json:"commitment"`
+	Department string `json:"department"`
+	Location   string `json:"location"`
+	Team       string `json:"team"`
+}
+
+type leverList struct {
+	Text    string `json:"text"`
+	Content string `json:"content"`
+}
+
+type leverSalaryRange struct {
+	Currency string  `json:"currency"`
+	Interval string  `json:"interval"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+}
+
+func init() {
+	RegisterJobSource(LeverSourceName, NewLeverJobSource)
+}
+
+// NewLeverJobSource reads the public postings API for each site in
+// cfg.LeverBoardTokens.
+func NewLeverJobSource(cfg config.Config) (JobSource, error) {
+	if len(cfg.LeverBoardTokens) == 0 {
+		return nil, fmt.Errorf("LEVER_BOARD_TOKENS must list at least one site name")
+	}
+	return &leverJobSource{
+		config:     cfg,
+		sites:      cfg.LeverBoardTokens,
+		httpClient: NewHTTPClient(cfg, 30*time.Second),
+		baseURL:    leverPostingsAPIPrefix,
+	}, nil
+}
+
+func (l *leverJobSource) Name() string {
+	return LeverSourceName
+}
+
+func (l *leverJobSource) SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error) {
+	var jobs []models.Job
+	var siteErrs []error
+	scrapedAt := time.Now().UTC()
+	for _, site := range l.sites {
+		postings, calls, err := l.fetchSite(ctx, site)
+		if err != nil {
+			log.Printf("Error fetching Lever site %s: %v", site, err)
+			siteErrs = append(siteErrs, fmt.Errorf("site %s: %w", site, err))
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return jobs, errors.Join(siteErrs...)
+			}
+		}
+
+		matched := 0
+		for _, posting := range postings {
+			if posting.ID == "" || !matchesJobQuery(posting.Text, posting.Categories.Location, query) {
+				continue
+			}
+			jobs = append(jobs, posting.toJob(site, scrapedAt))
+			matched++
+		}
+		utils.Debug(fmt.Sprintf("Lever site %s returned %d jobs in %d call(s), %d matching '%s'", site, len(postings), calls, matched, query.Label()))
+	}
+	return jobs, errors.Join(siteErrs...)
+}
+
+// fetchSite pages through a site with skip/limit, bounded by MaxPages like
+// the WorkSourceWA search.
+func (l *leverJobSource) fetchSite(ctx context.Context, site string) ([]leverPosting, int, error) {
+	maxPages := l.config.MaxPages
+	if maxPages < 1 {
+		maxPages = 1
+	}
+
+	var postings []leverPosting
+	calls := 0
+	for calls < maxPages {
+		endpoint := fmt.Sprintf("%s%s?mode=json&skip=%d&limit=%d", l.baseURL, url.PathEscape(site), len(postings), leverPageSize)
+		page, err := fetchSourceJSON[[]leverPosting](ctx, l.httpClient, endpoint)
+		calls++
+		if err != nil {
+			return postings, calls, err
+		}
+		postings = append(postings, page...)
+		if len(page) < leverPageSize {
+			break
+		}
+	}
+	return postings, calls, nil
+}
+
+// toJob maps a posting to a job. A posting without createdAt is dated
+// scrapedAt, so PostedTime is never empty beside a PostedDate.
+func (posting leverPosting) toJob(site string, scrapedAt time.Time) models.Job {
+	var content strings.Builder
+	content.WriteString(posting.Description)
+	for _, list := range posting.Lists {
+		content.WriteString("<h3>" + list.Text + "</h3><ul>" + list.Content + "</ul>")
+	}
+	content.WriteString(posting.Additional)
+	description := plainTextJobDescription(content.String())
+	if description == "" {
+		description = "No description available"
+	}
+
+	location := fallbackScraperValue(posting.Categories.Location, "Unknown Location")
+	modality := normalizeLocationType(posting.WorkplaceType)
+	if modality == "" {
+		modality = modalityFromLocationName(location)
+	}
+
+	postedTime := scrapedAt.UTC().Format(time.RFC3339)
+	if posting.CreatedAt > 0 {
+		postedTime = time.UnixMilli(posting.CreatedAt).UTC().Format(time.RFC3339)
+	}
+
+	job := models.Job{
+		JobId:       LeverSourceName + ":" + site + ":" + posting.ID,
+		Source:      LeverSourceName,
+		Title:       fallbackScraperValue(posting.Text, "Unknown Title"),
+		Company:     site,
+		Location:    location,
+		Modality:    modality,
+		PostedDate:  postingDateOnly(postedTime),
+		PostedTime:  postedTime,
+		Salary:      posting.SalaryRange.format(),
+		URL:         posting.HostedURL,
+		Description: description,
+	}
+	if pay, ok := posting.SalaryRange.pay(); ok {
+		setJobPay(&job, pay, SalarySourceListing)
+	}
+	return job
+}
+
+func (r *leverSalaryRange) pay() (payRange, bool) {
+	if r == nil || r.Min <= 0 && r.Max <= 0 {
+		return payRange{}, false
+	}
+	pay := payRange{min: r.Min, max: max(r.Min, r.Max), currency: strings.ToUpper(strings.TrimSpace(r.Currency))}
+	if r.Min <= 0 {
+		pay.min = r.Max
+	}
+	if pay.currency == "" {
+		pay.currency = defaultSalaryCurrency
+	}
+	// intervals look like "per-hour-wage" and "per-year-salary"
+	if parts := strings.Split(r.Interval, "-"); len(parts) == 3 {
+		pay.period = normalizePayPeriod(parts[1])
+	}
+	return pay, true
+}
+
+func (r *leverSalaryRange) format() string {
+	if r == nil || r.Min <= 0 && r.Max <= 0 {
+		return formatWorkSourcePay("", "")
+	}
+
+	amount := formatLeverAmount(r.Min)
+	if r.Max > r.Min {
+		amount += " - $" + formatLeverAmount(r.Max)
+	}
+	if currency := strings.ToUpper(strings.TrimSpace(r.Currency)); currency != "" && currency != "USD" {
+		amount += " " + currency
+	}
+
+	switch {
+	case strings.HasSuffix(r.Interval, "-hour-wage"):
+		return formatWorkSourcePay(amount, "hourly")
+	case r.Interval == "per-year-salary":
+		return formatWorkSourcePay(amount, "salary")
+	default:
+		return formatWorkSourcePay(amount, "")
+	}
+}
+
+func formatLeverAmount(value float64) string {
+	digits := strconv.FormatInt(int64(value), 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	return grouped.String()
+}
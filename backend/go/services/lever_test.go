@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gopher-source/config"
+)
+
+const leverPostingsPayload = `[
+  {
+    "additional": "<p>We offer <b>great</b> benefits.</p>",
+    "additionalPlain": "We offer great benefits.",
+    "categories": {
+      "commitment": "Full-time",
+      "department": "Engineering",
+      "location": "Portland, OR",
+      "team": "Data Platform",
+      "allLocations": ["Portland, OR"]
+    },
+    "createdAt": 1755561600000,
+    "descriptionPlain": "Join our data team.",
+    "description": "<div>Join our data team.</div>",
+    "id": "8b0c6d4e-1111-4a1b-9c2d-0f5e6a7b8c9d",
+    "lists": [
+      {"text": "Requirements", "content": "<li>3+ years with Python</li><li>SQL</li>"}
+    ],
+    "text": "Data Engineer II",
+    "country": "US",
+    "workplaceType": "remote",
+    "salaryRange": {"currency": "USD", "interval": "per-year-salary", "min": 140000, "max": 175000},
+    "hostedUrl": "https://jobs.lever.co/northwind/8b0c6d4e-1111-4a1b-9c2d-0f5e6a7b8c9d",
+    "applyUrl": "https://jobs.lever.co/northwind/8b0c6d4e-1111-4a1b-9c2d-0f5e6a7b8c9d/apply"
+  },
+  {
+    "categories": {"location": "Portland, OR"},
+    "createdAt": 1755475200000,
+    "description": "<p>Lead recruiting.</p>",
+    "id": "ffffffff-2222-4a1b-9c2d-0f5e6a7b8c9d",
+    "text": "Technical Recruiter",
+    "workplaceType": "onsite",
+    "hostedUrl": "https://jobs.lever.co/northwind/ffffffff-2222-4a1b-9c2d-0f5e6a7b8c9d"
+  }
+]`
+
+func TestLeverSearchJobsMapsMatchingPostings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/northwind" || r.URL.Query().Get("mode") != "json" || r.URL.Query().Get("skip") != "0" {
+			t.Errorf("unexpected request: %s", r.URL.String())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(leverPostingsPayload))
+	}))
+	defer server.Close()
+
+	source := newTestLeverSource(t, server, config.Config{MaxPages: 3}, "northwind")
+	jobs, err := source.SearchJobs(context.Background(), config.ScrapeQuery{Query: "engineer"})
+	if err != nil {
+		t.Fatalf("SearchJobs returned error: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected only the engineering posting to match, got %+v", jobs)
+	}
+
+	job := jobs[0]
+	if job.JobId != "lever:northwind:8b0c6d4e-1111-4a1b-9c2d-0f5e6a7b8c9d" || job.Source != LeverSourceName {
+		t.Fatalf("unexpected identity fields: %+v", job)
+	}
+	if job.Title != "Data Engineer II" || job.Company != "northwind" || job.Location != "Portland, OR" || job.Modality != "Remote" {
+		t.Fatalf("unexpected mapped fields: %+v", job)
+	}
+	if job.Description != "Join our data team. Requirements 3+ years with Python SQL We offer great benefits." {
+		t.Fatalf("unexpected description: %q", job.Description)
+	}
+	if job.PostedDate != "2025-08-19" || job.PostedTime != "2025-08-19T00:00:00Z" {
+		t.Fatalf("unexpected posting dates: %+v", job)
+	}
+	if job.Salary != "$140,000 - $175,000/year" {
+		t.Fatalf("unexpected salary: %q", job.Salary)
+	}
+	if job.SalaryMin == nil || *job.SalaryMin != 140000 || *job.SalaryMax != 175000 || job.SalaryPeriod != "year" || job.SalaryCurrency != "USD" {
+		t.Fatalf("expected structured salary, got %+v", job)
+	}
+}
+
+func TestLeverPostingWithoutCreatedAtUsesScrapeTime(t *testing.T) {
+	scrapedAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	job := leverPosting{ID: "1", Text: "Software Engineer"}.toJob("northwind", scrapedAt)
+	if job.PostedTime != "2026-10-16T09:30:00Z" || job.PostedDate != "2026-10-16" {
+		t.Fatalf("expected the scrape time as the posting time, got %q / %q", job.PostedTime, job.PostedDate)
+	}
+}
+
+func TestLeverSearchJobsPaginatesUpToMaxPages(t *testing.T) {
+	var skips []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		skips = append(skips, r.URL.Query().Get("skip"))
+		page := make([]leverPosting, leverPageSize)
+		for i := range page {
+			page[i] = leverPosting{ID: fmt.Sprintf("%s-%d", r.URL.Query().Get("skip"), i), Text: "Software Engineer"}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	source := newTestLeverSource(t, server, config.Config{MaxPages: 2}, "northwind")
+	jobs, err := source.SearchJobs(context.Background(), config.ScrapeQuery{Query: ""})
+	if err != nil {
+		t.Fatalf("SearchJobs returned error: %v", err)
+	}
+	if strings.Join(skips, ",") != "0,100" {
+		t.Fatalf("expected two pages, got skips %v", skips)
+	}
+	if len(jobs) != 2*leverPageSize {
+		t.Fatalf("expected %d jobs, got %d", 2*leverPageSize, len(jobs))
+	}
+}
+
+func TestLeverSalaryRangeFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		value *leverSalaryRange
+		want  string
+	}{
+		{"missing", nil, "Not specified"},
+		{"hourly", &leverSalaryRange{Currency: "USD", Interval: "per-hour-wage", Min: 45, Max: 55}, "$45 - $55/hour"},
+		{"singleValue", &leverSalaryRange{Currency: "USD", Interval: "per-year-salary", Min: 120000, Max: 120000}, "$120,000/year"},
+		{"foreignCurrency", &leverSalaryRange{Currency: "cad", Interval: "per-month-salary", Min: 9000, Max: 11000}, "$9,000 - $11,000 CAD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.value.format(); got != tt.want {
+				t.Fatalf("format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestLeverSource(t *testing.T, server *httptest.Server, cfg config.Config, sites ...string) *leverJobSource {
+	t.Helper()
+	cfg.LeverBoardTokens = sites
+	source, err := NewLeverJobSource(cfg)
+	if err != nil {
+		t.Fatalf("NewLeverJobSource returned error: %v", err)
+	}
+	impl := source.(*leverJobSource)
+	impl.httpClient = server.Client()
+	impl.baseURL = server.URL + "/"
+	return impl
+}
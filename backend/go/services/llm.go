@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+// LLMClient sends a job-extraction prompt to a model and returns the raw JSON
+// it produced for OpenAIJobParsingSchema. Usage is reported for every call the
+// provider billed, including ones that then fail.
+type LLMClient interface {
+	SendMessage(ctx context.Context, message string) (string, models.TokenUsage, error)
+}
+
+// modelPrice is a list price in USD per million tokens.
+type modelPrice struct {
+	input       float64
+	cachedInput float64
+	output      float64
+}
+
+// modelPrices covers the models this pipeline is run with. Self-hosted and
+// unlisted models are estimated at zero.
+var modelPrices = map[string]modelPrice{
+	"gpt-4.1-nano":      {input: 0.10, cachedInput: 0.025, output: 0.40},
+	"gpt-4.1-mini":      {input: 0.40, cachedInput: 0.10, output: 1.60},
+	"gpt-4.1":           {input: 2.00, cachedInput: 0.50, output: 8.00},
+	"gpt-4o-mini":       {input: 0.15, cachedInput: 0.075, output: 0.60},
+	"gpt-4o":            {input: 2.50, cachedInput: 1.25, output: 10.00},
+	"gpt-5-nano":        {input: 0.05, cachedInput: 0.005, output: 0.40},
+	"gpt-5-mini":        {input: 0.25, cachedInput: 0.025, output: 2.00},
+	"claude-haiku-4-5":  {input: 1.00, cachedInput: 0.10, output: 5.00},
+	"claude-sonnet-4-5": {input: 3.00, cachedInput: 0.30, output: 15.00},
+}
+
+// newTokenUsage records one call to model and estimates its cost. Dated
+// snapshots such as gpt-4.1-nano-2025-04-14 are priced as their base model.
+func newTokenUsage(model string, promptTokens, cachedTokens, completionTokens int64) models.TokenUsage {
+	usage := models.TokenUsage{
+		Model:            model,
+		Calls:            1,
+		PromptTokens:     promptTokens,
+		CachedTokens:     cachedTokens,
+		CompletionTokens: completionTokens,
+	}
+	price, ok := modelPrices[model]
+	if !ok {
+		longest := 0
+		for name, candidate := range modelPrices {
+			if len(name) > longest && strings.HasPrefix(model, name+"-") {
+				price, ok, longest = candidate, true, len(name)
+			}
+		}
+	}
+	if ok {
+		uncached := promptTokens - cachedTokens
+		usage.CostUSD = (float64(uncached)*price.input + float64(cachedTokens)*price.cachedInput + float64(completionTokens)*price.output) / 1e6
+	}
+	return usage
+}
+
+// LLMModel returns cfg.LLMModel, or the default model of cfg.LLMProvider
+// when it is empty.
+func LLMModel(cfg config.Config) string {
+	if cfg.LLMModel != "" {
+		return cfg.LLMModel
+	}
+	if cfg.LLMProvider == config.LLMProviderAnthropic {
+		return anthropicDefaultModel
+	}
+	return openai.ChatModelGPT4_1Nano
+}
+
+// NewLLMClient builds the client for cfg.LLMProvider. Every provider sends
+// its requests through NewHTTPClient, so fixtures record and replay them.
+func NewLLMClient(cfg config.Config) (LLMClient, error) {
+	switch cfg.LLMProvider {
+	case "", config.LLMProviderOpenAI:
+		return newOpenAIClient(cfg, false), nil
+	case config.LLMProviderOpenAICompatible:
+		if cfg.LLMBaseURL == "" {
+			return nil, fmt.Errorf("%s provider requires LLM_BASE_URL", config.LLMProviderOpenAICompatible)
+		}
+		return newOpenAIClient(cfg, true), nil
+	case config.LLMProviderAnthropic:
+		return newAnthropicClient(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q", cfg.LLMProvider)
+	}
+}
+
+func unmarshalJobParsingResponse(responseText string) (models.OpenAIJobParsingResponse, error) {
+	var res models.OpenAIJobParsingResponse
+	if err := json.Unmarshal([]byte(responseText), &res); err != nil {
+		return models.OpenAIJobParsingResponse{}, fmt.Errorf("error decoding LLM response: %v", err)
+	}
+	return res, nil
+}
+
+// executeWithRetry retries operation with exponential backoff and full jitter
+// while retryable reports the error as a rate limit or overload.
+func executeWithRetry[T any](ctx context.Context, operation func() (T, error), retryable func(error) bool) (T, error) {
+	var zero T
+	maxRetries := 10
+	baseDelay := 1000 * time.Millisecond
+	maxDelay := 10 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		result, err := operation()
+		if err != nil {
+			if retryable(err) {
+				backoffDelay := time.Duration(float64(baseDelay) * math.Pow(2, float64(i)))
+				backoffDelay = min(backoffDelay, maxDelay)
+				delay := time.Duration(rand.Int63n(int64(backoffDelay)))
+				fmt.Printf("\t⚠️ Rate limited: waiting %d ms before retry %d/%d\n", delay.Milliseconds(), i+1, maxRetries)
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return zero, ctx.Err()
+				}
+				continue
+			}
+			return zero, err
+		}
+		return result, nil
+	}
+	return zero, fmt.Errorf("\t❌ Max retry attempts of %d reached. Operation failed", maxRetries)
+}
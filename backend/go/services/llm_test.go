@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+)
+
+const llmTestResponse = `{"ParsedDescription":"parsed","IsSoftwareEngineerRelated":true}`
+
+func TestOpenAICompatibleProviderUsesBaseURLModelAndSystemRole(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-real-openai-key")
+	var request struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Role string `json:"role"`
+		} `json:"messages"`
+		ResponseFormat struct {
+			Type string `json:"type"`
+		} `json:"response_format"`
+	}
+	var authorization, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		writeChatCompletion(t, w, llmTestResponse)
+	}))
+	defer server.Close()
+
+	client, err := NewLLMClient(config.Config{
+		LLMProvider: config.LLMProviderOpenAICompatible,
+		LLMBaseURL:  server.URL + "/v1/",
+		LLMModel:    "llama3.1:8b",
+	})
+	if err != nil {
+		t.Fatalf("NewLLMClient returned error: %v", err)
+	}
+	got, _, err := client.SendMessage(context.Background(), "Job title: Go Developer")
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if got != llmTestResponse {
+		t.Fatalf("expected raw JSON content, got %q", got)
+	}
+	if path != "/v1/chat/completions" || request.Model != "llama3.1:8b" || request.ResponseFormat.Type != "json_schema" {
+		t.Fatalf("unexpected request: path=%s body=%+v", path, request)
+	}
+	if len(request.Messages) != 2 || request.Messages[0].Role != "system" {
+		t.Fatalf("expected system instructions for a compatible server, got %+v", request.Messages)
+	}
+	if strings.Contains(authorization, "sk-real-openai-key") {
+		t.Fatal("OPENAI_API_KEY must not be sent to a self-hosted server")
+	}
+}
+
+func TestOpenAIProviderDefaultsAndRefusal(t *testing.T) {
+	var model, role string
+	refuse := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role string `json:"role"`
+			} `json:"messages"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		model, role = request.Model, request.Messages[0].Role
+		if refuse {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"1","object":"chat.completion","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":"","refusal":"cannot help"}}]}`))
+			return
+		}
+		writeChatCompletion(t, w, llmTestResponse)
+	}))
+	defer server.Close()
+
+	client, err := NewLLMClient(config.Config{LLMBaseURL: server.URL, LLMAPIKey: "sk-test"})
+	if err != nil {
+		t.Fatalf("NewLLMClient returned error: %v", err)
+	}
+	_, usage, err := client.SendMessage(context.Background(), "Job title: Go Developer")
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if model != "gpt-4.1-nano" || role != "developer" {
+		t.Fatalf("expected OpenAI defaults, got model=%q role=%q", model, role)
+	}
+	// 600 uncached, 400 cached, and 200 completion tokens at gpt-4.1-nano prices
+	wantCost := (600*0.10 + 400*0.025 + 200*0.40) / 1e6
+	if usage.Model != "gpt-4.1-nano" || usage.Calls != 1 || usage.PromptTokens != 1000 || usage.CachedTokens != 400 ||
+		usage.CompletionTokens != 200 || math.Abs(usage.CostUSD-wantCost) > 1e-12 {
+		t.Fatalf("unexpected usage: %+v (want cost %v)", usage, wantCost)
+	}
+
+	refuse = true
+	_, usage, err = client.SendMessage(context.Background(), "Job title: Go Developer")
+	if err == nil || !strings.Contains(err.Error(), "cannot help") {
+		t.Fatalf("expected refusal error, got %v", err)
+	}
+	if usage.Calls != 1 {
+		t.Fatalf("expected a refused call to still report usage, got %+v", usage)
+	}
+}
+
+func TestAnthropicProviderForcesSchemaTool(t *testing.T) {
+	var request anthropicMessagesRequest
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		if r.URL.Path != "/v1/messages" {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"content":[{"type":"text","text":"Recording."},{"type":"tool_use","id":"toolu_1","name":"` + anthropicToolName + `","input":` + llmTestResponse + `}],"stop_reason":"tool_use","usage":{"input_tokens":900,"cache_read_input_tokens":100,"output_tokens":50}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewLLMClient(config.Config{LLMProvider: config.LLMProviderAnthropic, LLMBaseURL: server.URL, LLMAPIKey: "sk-ant-test"})
+	if err != nil {
+		t.Fatalf("NewLLMClient returned error: %v", err)
+	}
+	got, usage, err := client.SendMessage(context.Background(), "Job title: Go Developer")
+	if err != nil {
+		t.Fatalf("SendMessage returned error: %v", err)
+	}
+	if got != llmTestResponse {
+		t.Fatalf("expected tool input JSON, got %q", got)
+	}
+	if usage.Model != anthropicDefaultModel || usage.PromptTokens != 1000 || usage.CachedTokens != 100 || usage.CompletionTokens != 50 || usage.CostUSD <= 0 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+	if headers.Get("x-api-key") != "sk-ant-test" || headers.Get("anthropic-version") != anthropicAPIVersion {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	if request.Model != anthropicDefaultModel || request.ToolChoice.Name != anthropicToolName || len(request.Tools) != 1 || request.System == "" {
+		t.Fatalf("unexpected request: %+v", request)
+	}
+}
+
+func TestAnthropicProviderReturnsAPIErrors(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type":"error","error":{"type":"invalid_request_error","message":"max_tokens too large"}}`))
+	}))
+	defer server.Close()
+
+	client, _ := NewLLMClient(config.Config{LLMProvider: config.LLMProviderAnthropic, LLMBaseURL: server.URL})
+	_, _, err := client.SendMessage(context.Background(), "Job title: Go Developer")
+	if err == nil || !strings.Contains(err.Error(), "max_tokens too large") {
+		t.Fatalf("expected API error message, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected no retry for a bad request, got %d call(s)", calls)
+	}
+}
+
+func TestNewLLMClientValidatesProvider(t *testing.T) {
+	if _, err := NewLLMClient(config.Config{LLMProvider: "bard"}); err == nil {
+		t.Fatal("expected unknown provider error")
+	}
+	if _, err := NewLLMClient(config.Config{LLMProvider: config.LLMProviderOpenAICompatible}); err == nil || !strings.Contains(err.Error(), "LLM_BASE_URL") {
+		t.Fatalf("expected base URL error, got %v", err)
+	}
+}
+
+func writeChatCompletion(t *testing.T, w http.ResponseWriter, content string) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(map[string]any{
+		"id":      "chatcmpl-test",
+		"object":  "chat.completion",
+		"created": 0,
+		"model":   "test",
+		"choices": []map[string]any{{
+			"index":         0,
+			"finish_reason": "stop",
+			"message":       map[string]any{"role": "assistant", "content": content},
+		}},
+		"usage": map[string]any{
+			"prompt_tokens":         1000,
+			"completion_tokens":     200,
+			"total_tokens":          1200,
+			"prompt_tokens_details": map[string]any{"cached_tokens": 400},
+		},
+	})
+	if err != nil {
+		t.Errorf("encode completion: %v", err)
+	}
+}
+
+func TestNewTokenUsagePricesDatedSnapshots(t *testing.T) {
+	dated := newTokenUsage("gpt-4.1-nano-2025-04-14", 1_000_000, 0, 0)
+	if dated.CostUSD != 0.10 {
+		t.Fatalf("expected gpt-4.1-nano input price, got %v", dated.CostUSD)
+	}
+	mini := newTokenUsage("gpt-4.1-mini", 1_000_000, 0, 0)
+	if mini.CostUSD != 0.40 {
+		t.Fatalf("expected gpt-4.1-mini not to match gpt-4.1, got %v", mini.CostUSD)
+	}
+	if local := newTokenUsage("llama3.1:8b", 5000, 0, 500); local.CostUSD != 0 || local.Calls != 1 {
+		t.Fatalf("expected unpriced model to count tokens at no cost, got %+v", local)
+	}
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopher-source/models"
+)
+
+// usPlacesCSV lists US places with their internal-point coordinates and the
+// metropolitan statistical area each belongs to. cmd/gen-places builds it from
+// the Census Bureau's public-domain Gazetteer places, place-by-county and CBSA
+// delineation files; the file's header comment records which release.
+//
+//go:generate go run ../cmd/gen-places -out data/us_places.csv
+//go:embed data/us_places.csv
+var usPlacesCSV string
+
+var usPlaces = newGazetteer(usPlacesCSV)
+
+var (
+	postalCodePattern = regexp.MustCompile(`\b(\d{5})(?:-\d{4})?\b`)
+	// "Seattle, WA; Remote" and "Seattle or Tacoma" resolve the first place.
+	// Only lowercase conjunctions split, so the state code in "Corvallis, OR"
+	// is kept.
+	multipleLocationsPattern = regexp.MustCompile(`;|\||/| or | and `)
+)
+
+var usStates = map[string]string{
+	"alabama": "AL", "alaska": "AK", "arizona": "AZ", "arkansas": "AR", "california": "CA",
+	"colorado": "CO", "connecticut": "CT", "delaware": "DE", "district of columbia": "DC", "florida": "FL",
+	"georgia": "GA", "hawaii": "HI", "idaho": "ID", "illinois": "IL", "indiana": "IN",
+	"iowa": "IA", "kansas": "KS", "kentucky": "KY", "louisiana": "LA", "maine": "ME",
+	"maryland": "MD", "massachusetts": "MA", "michigan": "MI", "minnesota": "MN", "mississippi": "MS",
+	"missouri": "MO", "montana": "MT", "nebraska": "NE", "nevada": "NV", "new hampshire": "NH",
+	"new jersey": "NJ", "new mexico": "NM", "new york": "NY", "north carolina": "NC", "north dakota": "ND",
+	"ohio": "OH", "oklahoma": "OK", "oregon": "OR", "pennsylvania": "PA", "rhode island": "RI",
+	"south carolina": "SC", "south dakota": "SD", "tennessee": "TN", "texas": "TX", "utah": "UT",
+	"vermont": "VT", "virginia": "VA", "washington": "WA", "west virginia": "WV", "wisconsin": "WI",
+	"wyoming": "WY", "puerto rico": "PR",
+}
+
+var usStateCodes = func() map[string]bool {
+	codes := make(map[string]bool, len(usStates))
+	for _, code := range usStates {
+		codes[code] = true
+	}
+	return codes
+}()
+
+// canadianProvinces keeps "Vancouver, BC" from resolving to Vancouver, WA.
+var canadianProvinces = map[string]string{
+	"AB": "AB", "BC": "BC", "MB": "MB", "NB": "NB", "NL": "NL", "NS": "NS", "ON": "ON", "PE": "PE", "QC": "QC", "SK": "SK",
+	"alberta": "AB", "british columbia": "BC", "manitoba": "MB", "new brunswick": "NB", "newfoundland and labrador": "NL",
+	"nova scotia": "NS", "ontario": "ON", "prince edward island": "PE", "quebec": "QC", "saskatchewan": "SK",
+}
+
+var countryCodes = map[string]string{
+	"us": "US", "usa": "US", "u.s": "US", "u.s.a": "US", "united states": "US", "united states of america": "US",
+	"canada": "CA", "mexico": "MX", "united kingdom": "GB", "uk": "GB", "ireland": "IE", "germany": "DE",
+	"india": "IN", "australia": "AU",
+}
+
+// nonPlaces are location names that describe an arrangement, not a place.
+var nonPlaces = map[string]bool{
+	"remote": true, "hybrid": true, "on-site": true, "onsite": true, "in office": true, "anywhere": true,
+	"unknown location": true, "multiple locations": true, "various locations": true, "nationwide": true, "statewide": true,
+}
+
+// zipPrefixStates maps the first three digits of a ZIP code to its state,
+// for postings that give a postal code but no state.
+var zipPrefixStates = []struct {
+	low, high int
+	state     string
+}{
+	{5, 5, "NY"}, {6, 9, "PR"}, {10, 27, "MA"}, {28, 29, "RI"}, {30, 38, "NH"}, {39, 49, "ME"}, {50, 59, "VT"},
+	{60, 69, "CT"}, {70, 89, "NJ"}, {100, 149, "NY"}, {150, 196, "PA"}, {197, 199, "DE"}, {200, 205, "DC"},
+	{206, 219, "MD"}, {220, 246, "VA"}, {247, 268, "WV"}, {270, 289, "NC"}, {290, 299, "SC"}, {300, 319, "GA"},
+	{320, 349, "FL"}, {350, 369, "AL"}, {370, 385, "TN"}, {386, 397, "MS"}, {398, 399, "GA"}, {400, 427, "KY"},
+	{430, 459, "OH"}, {460, 479, "IN"}, {480, 499, "MI"}, {500, 528, "IA"}, {530, 549, "WI"}, {550, 567, "MN"},
+	{570, 577, "SD"}, {580, 588, "ND"}, {590, 599, "MT"}, {600, 629, "IL"}, {630, 658, "MO"}, {660, 679, "KS"},
+	{680, 693, "NE"}, {700, 714, "LA"}, {716, 729, "AR"}, {730, 749, "OK"}, {750, 799, "TX"}, {800, 816, "CO"},
+	{820, 831, "WY"}, {832, 838, "ID"}, {840, 847, "UT"}, {850, 865, "AZ"}, {870, 884, "NM"}, {885, 885, "TX"},
+	{889, 898, "NV"}, {900, 961, "CA"}, {967, 968, "HI"}, {970, 979, "OR"}, {980, 994, "WA"}, {995, 999, "AK"},
+}
+
+type gazetteerPlace struct {
+	city      string
+	state     string
+	latitude  float64
+	longitude float64
+	metro     string
+}
+
+type gazetteer struct {
+	byCityState map[string]gazetteerPlace
+	byCity      map[string][]gazetteerPlace
+}
+
+// newGazetteer panics on a malformed file, which is bundled with the binary.
+func newGazetteer(data string) *gazetteer {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.Comment = '#'
+	records, err := reader.ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("services: read gazetteer: %v", err))
+	}
+	g := &gazetteer{byCityState: make(map[string]gazetteerPlace), byCity: make(map[string][]gazetteerPlace)}
+	for i, record := range records[1:] {
+		latitude, latErr := strconv.ParseFloat(record[2], 64)
+		longitude, lonErr := strconv.ParseFloat(record[3], 64)
+		if latErr != nil || lonErr != nil {
+			panic(fmt.Sprintf("services: gazetteer line %d has invalid coordinates", i+2))
+		}
+		place := gazetteerPlace{city: record[0], state: record[1], latitude: latitude, longitude: longitude, metro: record[4]}
+		names := []string{record[0]}
+		if record[5] != "" {
+			names = append(names, strings.Split(record[5], "|")...)
+		}
+		for _, name := range names {
+			key := normalizePlaceName(name)
+			g.byCityState[key+"|"+place.state] = place
+			g.byCity[key] = append(g.byCity[key], place)
+		}
+	}
+	return g
+}
+
+// lookup finds city in state, or in any state when only one has a city by
+// that name.
+func (g *gazetteer) lookup(city, state string) (gazetteerPlace, bool) {
+	key := normalizePlaceName(city)
+	if state != "" {
+		place, ok := g.byCityState[key+"|"+state]
+		return place, ok
+	}
+	if places := g.byCity[key]; len(places) == 1 {
+		return places[0], true
+	}
+	return gazetteerPlace{}, false
+}
+
+// resolveJobLocation fills the structured location fields from
+// job.Location. Places outside the gazetteer keep whatever city, state and
+// postal code the text names, without coordinates.
+func resolveJobLocation(job *models.Job) {
+	job.City, job.State, job.PostalCode, job.Country, job.MetroArea = "", "", "", "", ""
+	job.Latitude, job.Longitude = nil, nil
+
+	segments := splitLocations(job.Location)
+	text := segments[0]
+	for _, segment := range segments {
+		if trimmed := strings.ToLower(strings.TrimSpace(segment)); trimmed != "" && !nonPlaces[trimmed] {
+			text = segment
+			break
+		}
+	}
+	if match := postalCodePattern.FindStringSubmatch(text); match != nil {
+		job.PostalCode = match[1]
+		text = strings.Replace(text, match[0], " ", 1)
+	}
+
+	var names []string
+	for _, part := range strings.Split(text, ",") {
+		for _, piece := range strings.Split(part, " - ") {
+			name := strings.Trim(strings.TrimSpace(piece), ".-")
+			lower := strings.ToLower(name)
+			switch {
+			case name == "" || nonPlaces[lower]:
+			case countryCodes[lower] != "":
+				job.Country = countryCodes[lower]
+			default:
+				names = append(names, splitTrailingState(name)...)
+			}
+		}
+	}
+
+	switch len(names) {
+	case 0:
+	case 1:
+		if state, country := placeRegion(names[0]); state != "" {
+			job.State, job.Country = state, firstNonEmpty(job.Country, country)
+		} else {
+			job.City = names[0]
+		}
+	default:
+		job.City = names[0]
+		for _, name := range names[1:] {
+			if state, country := placeRegion(name); state != "" {
+				job.State, job.Country = state, firstNonEmpty(job.Country, country)
+				break
+			}
+		}
+	}
+	if job.State == "" && job.PostalCode != "" && (job.Country == "" || job.Country == "US") {
+		job.State = stateForPostalCode(job.PostalCode)
+	}
+
+	if job.City != "" && (job.Country == "" || job.Country == "US") {
+		if place, ok := usPlaces.lookup(job.City, job.State); ok {
+			job.City, job.State, job.MetroArea = place.city, place.state, place.metro
+			job.Latitude, job.Longitude = floatPtr(place.latitude), floatPtr(place.longitude)
+		} else if job.City == strings.ToUpper(job.City) {
+			job.City = titleCasePlace(job.City)
+		}
+	}
+	if job.State != "" && job.Country == "" {
+		job.Country = "US"
+	}
+}
+
+// conjoinedRegionNames are the region names, such as "newfoundland and
+// labrador", that splitLocations must not split.
+var conjoinedRegionNames = func() []string {
+	var names []string
+	for _, regions := range []map[string]string{usStates, canadianProvinces, countryCodes} {
+		for name := range regions {
+			if strings.Contains(name, " and ") || strings.Contains(name, " or ") {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}()
+
+// splitLocations splits a location naming several places at
+// multipleLocationsPattern, except inside a conjoined region name.
+func splitLocations(location string) []string {
+	lower := strings.ToLower(location)
+	var protected [][2]int
+	for _, name := range conjoinedRegionNames {
+		for offset := 0; ; {
+			i := strings.Index(lower[offset:], name)
+			if i < 0 {
+				break
+			}
+			protected = append(protected, [2]int{offset + i, offset + i + len(name)})
+			offset += i + len(name)
+		}
+	}
+
+	var segments []string
+	start := 0
+	for _, match := range multipleLocationsPattern.FindAllStringIndex(location, -1) {
+		inside := false
+		for _, span := range protected {
+			if match[0] >= span[0] && match[1] <= span[1] {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			segments = append(segments, location[start:match[0]])
+			start = match[1]
+		}
+	}
+	return append(segments, location[start:])
+}
+
+// placeRegion returns the state or province a name refers to and its country.
+func placeRegion(name string) (string, string) {
+	if code := strings.ReplaceAll(name, ".", ""); len(code) == 2 && code == strings.ToUpper(code) {
+		if usStateCodes[code] {
+			return code, "US"
+		}
+		if code, ok := canadianProvinces[code]; ok {
+			return code, "CA"
+		}
+		return "", ""
+	}
+	lower := strings.ToLower(name)
+	if code, ok := usStates[lower]; ok {
+		return code, "US"
+	}
+	if code, ok := canadianProvinces[lower]; ok {
+		return code, "CA"
+	}
+	return "", ""
+}
+
+// splitTrailingState splits "Seattle WA" into its city and state code.
+func splitTrailingState(name string) []string {
+	fields := strings.Fields(name)
+	if len(fields) < 2 {
+		return []string{name}
+	}
+	last := strings.ToUpper(strings.ReplaceAll(fields[len(fields)-1], ".", ""))
+	if state, _ := placeRegion(last); len(last) != 2 || state == "" {
+		return []string{name}
+	}
+	return []string{strings.Join(fields[:len(fields)-1], " "), last}
+}
+
+func stateForPostalCode(postalCode string) string {
+	prefix, err := strconv.Atoi(postalCode[:3])
+	if err != nil {
+		return ""
+	}
+	for _, entry := range zipPrefixStates {
+		if prefix >= entry.low && prefix <= entry.high {
+			return entry.state
+		}
+	}
+	return ""
+}
+
+// normalizePlaceName folds case, periods and "Saint" so "ST. LOUIS" and
+// "Saint Louis" match.
+func normalizePlaceName(name string) string {
+	name = strings.ToLower(strings.ReplaceAll(name, ".", ""))
+	fields := strings.Fields(name)
+	for i, field := range fields {
+		if field == "saint" {
+			fields[i] = "st"
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func titleCasePlace(name string) string {
+	fields := strings.Fields(strings.ToLower(name))
+	for i, field := range fields {
+		fields[i] = strings.ToUpper(field[:1]) + field[1:]
+	}
+	return strings.Join(fields, " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
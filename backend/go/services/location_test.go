@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestResolveJobLocation(t *testing.T) {
+	tests := []struct {
+		location   string
+		city       string
+		state      string
+		postalCode string
+		country    string
+		metro      string
+		resolved   bool
+	}{
+		{"Seattle, WA", "Seattle", "WA", "", "US", "Seattle-Tacoma-Bellevue, WA", true},
+		{"BELLEVUE, WA 98004", "Bellevue", "WA", "98004", "US", "Seattle-Tacoma-Bellevue, WA", true},
+		{"Spokane Valley, Washington", "Spokane Valley", "WA", "", "US", "Spokane-Spokane Valley, WA", true},
+		{"Vancouver WA 98660-1234", "Vancouver", "WA", "98660", "US", "Portland-Vancouver-Hillsboro, OR-WA", true},
+		{"Portland, OR; Remote", "Portland", "OR", "", "US", "Portland-Vancouver-Hillsboro, OR-WA", true},
+		{"Remote / Tacoma", "Tacoma", "WA", "", "US", "Seattle-Tacoma-Bellevue, WA", true},
+		{"Washington, D.C.", "Washington", "DC", "", "US", "Washington-Arlington-Alexandria, DC-VA-MD-WV", true},
+		{"Saint Louis, MO, USA", "St. Louis", "MO", "", "US", "St. Louis, MO-IL", true},
+		{"NYC", "New York", "NY", "", "US", "New York-Newark-Jersey City, NY-NJ", true},
+		{"ELMA, WA", "Elma", "WA", "", "US", "", false},
+		{"Arlington", "Arlington", "", "", "", "", false},
+		{"Olympia 98501", "Olympia", "WA", "98501", "US", "Olympia-Lacey-Tumwater, WA", true},
+		{"Vancouver, BC, Canada", "Vancouver", "BC", "", "CA", "", false},
+		{"Corvallis, OR", "Corvallis", "OR", "", "US", "", false},
+		{"Corvallis, OR 97330", "Corvallis", "OR", "97330", "US", "", false},
+		{"Portland, OR 97201", "Portland", "OR", "97201", "US", "Portland-Vancouver-Hillsboro, OR-WA", true},
+		{"Seattle, WA or Portland, OR", "Seattle", "WA", "", "US", "Seattle-Tacoma-Bellevue, WA", true},
+		{"St. John's, Newfoundland and Labrador", "St. John's", "NL", "", "CA", "", false},
+		{"Newfoundland and Labrador, Canada", "", "NL", "", "CA", "", false},
+		{"Remote - US", "", "", "", "US", "", false},
+		{"Washington", "", "WA", "", "US", "", false},
+		{"Unknown Location", "", "", "", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			job := models.Job{Location: tt.location}
+			resolveJobLocation(&job)
+			if job.City != tt.city || job.State != tt.state || job.PostalCode != tt.postalCode || job.Country != tt.country || job.MetroArea != tt.metro {
+				t.Fatalf("resolveJobLocation(%q) = %q, %q, %q, %q, %q", tt.location, job.City, job.State, job.PostalCode, job.Country, job.MetroArea)
+			}
+			if resolved := job.Latitude != nil && job.Longitude != nil; resolved != tt.resolved {
+				t.Fatalf("expected coordinates %v for %q, got %v, %v", tt.resolved, tt.location, job.Latitude, job.Longitude)
+			}
+		})
+	}
+}
+
+func TestGazetteerCoordinatesAreInTheUS(t *testing.T) {
+	for key, place := range usPlaces.byCityState {
+		if place.latitude < 18 || place.latitude > 72 || place.longitude < -170 || place.longitude > -65 {
+			t.Fatalf("%s has coordinates outside the US: %v, %v", key, place.latitude, place.longitude)
+		}
+	}
+}
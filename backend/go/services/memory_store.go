@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gopher-source/models"
+	"gopher-source/utils"
+)
+
+type memoryStoreImpl struct {
+	mutex sync.Mutex
+	jobs  map[memoryKey]models.Job
+}
+
+// memoryKey is the table key of the DynamoDB store, so a JobId reposted on
+// another date is a separate job here too.
+type memoryKey struct {
+	jobID      string
+	postedDate string
+}
+
+func keyOf(job *models.Job) memoryKey {
+	return memoryKey{jobID: job.JobId, postedDate: job.PostedDate}
+}
+
+// NewMemoryStore keeps jobs in memory for the life of the process, for
+// replayed runs and tests that must never reach a database.
+func NewMemoryStore() JobStore {
+	return &memoryStoreImpl{jobs: make(map[memoryKey]models.Job)}
+}
+
+func (m *memoryStoreImpl) PutJob(ctx context.Context, job *models.Job) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, exists := m.jobs[keyOf(job)]; exists {
+		utils.Debug("\t⚠️  Item already exists, skipping")
+		return nil
+	}
+	m.jobs[keyOf(job)] = firstVersion(job)
+	utils.Debug(fmt.Sprintf("\t📦 Memory store saved job %s", job.Title))
+	return nil
+}
+
+func (m *memoryStoreImpl) PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	result := WriteResult{Calls: 1}
+	for i := range jobs {
+		if _, exists := m.jobs[keyOf(&jobs[i])]; exists {
+			result.Skipped++
+			continue
+		}
+		m.jobs[keyOf(&jobs[i])] = firstVersion(&jobs[i])
+		result.Stored++
+	}
+	return result, nil
+}
+
+func (m *memoryStoreImpl) UpsertJob(ctx context.Context, job *models.Job) (bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var current *models.Job
+	if stored, exists := m.jobs[keyOf(job)]; exists {
+		current = &stored
+	}
+	next, changed := nextVersion(current, job)
+	if !changed {
+		utils.Debug("\t⚠️  Posting unchanged, skipping")
+		return false, nil
+	}
+	m.jobs[keyOf(job)] = next
+	utils.Debug(fmt.Sprintf("\t📦 Memory store upserted job %s", job.Title))
+	return true, nil
+}
+
+func (m *memoryStoreImpl) UpdateJobEnrichment(ctx context.Context, job *models.Job) error {
+	if err := m.updateAttributes(job, enrichedAttributes); err != nil {
+		return fmt.Errorf("update enrichment of job %s: %w", job.JobId, err)
+	}
+	return nil
+}
+
+func (m *memoryStoreImpl) UpdateJobHistory(ctx context.Context, job *models.Job) error {
+	if err := m.updateAttributes(job, historyAttributes); err != nil {
+		return fmt.Errorf("update history of job %s: %w", job.JobId, err)
+	}
+	return nil
+}
+
+func (m *memoryStoreImpl) updateAttributes(job *models.Job, names []string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	stored, exists := m.jobs[keyOf(job)]
+	if !exists {
+		return ErrJobNotStored
+	}
+	merged, err := mergeAttributes(stored, *job, names)
+	if err != nil {
+		return err
+	}
+	m.jobs[keyOf(job)] = merged
+	return nil
+}
+
+func (m *memoryStoreImpl) QueryJobsByPostedDate(ctx context.Context, date string) ([]models.Job, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var jobs []models.Job
+	for _, job := range m.jobs {
+		if job.PostedDate == date {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *memoryStoreImpl) QueryJobsByDateRange(ctx context.Context, startDate, endDate string) ([]models.Job, error) {
+	filter, err := dateRangeFilter(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return m.QueryJobs(ctx, filter)
+}
+
+func (m *memoryStoreImpl) QueryJobs(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var jobs []models.Job
+	for _, job := range m.jobs {
+		if filter.matches(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	sortNewestFirst(jobs)
+	return jobs, nil
+}
+
+func (m *memoryStoreImpl) GetAllJobIds(ctx context.Context) (map[string]bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	jobIds := make(map[string]bool, len(m.jobs))
+	for key := range m.jobs {
+		jobIds[key.jobID] = true
+	}
+	return jobIds, nil
+}
+
+func (m *memoryStoreImpl) SourceHashes(ctx context.Context, jobs []models.Job) ([]string, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	hashes := make([]string, len(jobs))
+	for i := range jobs {
+		hashes[i] = m.jobs[keyOf(&jobs[i])].SourceHash
+	}
+	return hashes, nil
+}
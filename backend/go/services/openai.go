@@ -2,16 +2,15 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"math"
-	"math/rand"
-	"time"
+	"net/http"
 
 	"github.com/invopop/jsonschema"
 	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/option"
 
+	"gopher-source/config"
 	"gopher-source/models"
 )
 
@@ -28,80 +27,107 @@ For MinYearsExperience:
 - Never infer a number from title or seniority labels such as Entry-level, Junior, Mid-level, Senior, Staff, Principal, Lead, Director, New Grad, or Intern.
 - Return null only when the minimum cannot be determined from the available source.
 
-Follow the response schema exactly and do not add commentary.`
+For SalaryMin and SalaryMax, report only pay the description states for this role, converting shorthand such as "$80k" to 80000. Return null for both when no pay is stated, including "competitive" or "depends on experience."
 
-type OpenAIClient interface {
-	SendMessage(ctx context.Context, message string) (openai.ChatCompletion, error)
-	UnmarshalResponse(responseText string) (models.OpenAIJobParsingResponse, error)
-}
+For Seniority, classify the level from the title and responsibilities. This is separate from MinYearsExperience: a Senior title still gives no number of years.
+
+For VisaSponsorship, SecurityClearance and WorkAuthorization, report only requirements the description states for this role, such as "no sponsorship available," "active TS/SCI clearance" or "must be a U.S. citizen." Equal opportunity boilerplate does not state a requirement.
+
+Follow the response schema exactly and do not add commentary.`
 
 type openaiClientImpl struct {
 	client openai.Client
+	model  string
+	// compatible servers such as Ollama and vLLM take the instructions as a
+	// system message rather than OpenAI's developer role
+	compatible bool
 }
 
-func NewOpenAIService() OpenAIClient {
-	client := openai.NewClient()
-	return &openaiClientImpl{client: client}
+// newOpenAIClient talks to OpenAI, or with compatible set to any server that
+// implements its chat completions API at cfg.LLMBaseURL. Replay needs no API
+// key and skips SDK retries, since a missing fixture stays missing.
+func newOpenAIClient(cfg config.Config, compatible bool) *openaiClientImpl {
+	opts := []option.RequestOption{option.WithHTTPClient(NewHTTPClient(cfg, 0))}
+	if cfg.LLMBaseURL != "" {
+		opts = append(opts, option.WithBaseURL(cfg.LLMBaseURL))
+	}
+	apiKey := cfg.LLMAPIKey
+	if apiKey == "" && (compatible || cfg.HTTPFixtureMode == config.HTTPFixtureReplay) {
+		// never fall back to OPENAI_API_KEY from the environment for a
+		// self-hosted server
+		apiKey = "unused"
+	}
+	if apiKey != "" {
+		opts = append(opts, option.WithAPIKey(apiKey))
+	}
+	if cfg.HTTPFixtureMode == config.HTTPFixtureReplay {
+		opts = append(opts, option.WithMaxRetries(0))
+	}
+
+	return &openaiClientImpl{client: openai.NewClient(opts...), model: LLMModel(cfg), compatible: compatible}
 }
 
-func (o *openaiClientImpl) SendMessage(ctx context.Context, message string) (openai.ChatCompletion, error) {
+func (o *openaiClientImpl) SendMessage(ctx context.Context, message string) (string, models.TokenUsage, error) {
+	params := o.chatCompletionParams(message)
+	chatCompletion, err := executeWithRetry(ctx, func() (*openai.ChatCompletion, error) {
+		chatCompletion, err := o.client.Chat.Completions.New(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("OpenAI API error: %w", err)
+		}
+		return chatCompletion, nil
+	}, isOpenAIRateLimit)
+	if err != nil {
+		return "", models.TokenUsage{}, err
+	}
+	usage := chatCompletionUsage(o.model, chatCompletion)
+	content, err := chatCompletionContent(chatCompletion)
+	return content, usage, err
+}
+
+// chatCompletionParams builds the structured-output request for one posting;
+// batch enrichment sends the same body for each line of its input file.
+func (o *openaiClientImpl) chatCompletionParams(message string) openai.ChatCompletionNewParams {
 	schemaParam := openai.ResponseFormatJSONSchemaJSONSchemaParam{
 		Name:        "job_parsing_response",
 		Description: openai.String("Parsed job posting information"),
 		Schema:      OpenAIJobParsingSchema,
 		Strict:      openai.Bool(true),
 	}
+	instruction := openai.DeveloperMessage(jobExtractionDeveloperInstruction)
+	if o.compatible {
+		instruction = openai.SystemMessage(jobExtractionDeveloperInstruction)
+	}
+	return openai.ChatCompletionNewParams{
+		Messages: []openai.ChatCompletionMessageParamUnion{
+			instruction,
+			openai.UserMessage(message),
+		},
+		Model: o.model,
+		ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
+			OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
+		},
+	}
+}
 
-	return o.executeWithRetry(ctx, func() (openai.ChatCompletion, error) {
-		chatCompletion, err := o.client.Chat.Completions.New(ctx, openai.ChatCompletionNewParams{
-			Messages: []openai.ChatCompletionMessageParamUnion{
-				openai.DeveloperMessage(jobExtractionDeveloperInstruction),
-				openai.UserMessage(message),
-			},
-			Model: openai.ChatModelGPT4_1Nano,
-			ResponseFormat: openai.ChatCompletionNewParamsResponseFormatUnion{
-				OfJSONSchema: &openai.ResponseFormatJSONSchemaParam{JSONSchema: schemaParam},
-			},
-		})
-		if err != nil {
-			return openai.ChatCompletion{}, fmt.Errorf("OpenAI API error: %w", err)
-		}
-		return *chatCompletion, nil
-	})
+func chatCompletionUsage(model string, chatCompletion *openai.ChatCompletion) models.TokenUsage {
+	usage := chatCompletion.Usage
+	return newTokenUsage(model, usage.PromptTokens, usage.PromptTokensDetails.CachedTokens, usage.CompletionTokens)
 }
 
-func (o *openaiClientImpl) UnmarshalResponse(responseText string) (models.OpenAIJobParsingResponse, error) {
-	var res models.OpenAIJobParsingResponse
-	err := json.Unmarshal([]byte(responseText), &res)
-	if err != nil {
-		return models.OpenAIJobParsingResponse{}, fmt.Errorf("error decoding OpenAI response: %v", err)
+func chatCompletionContent(chatCompletion *openai.ChatCompletion) (string, error) {
+	if len(chatCompletion.Choices) == 0 {
+		return "", fmt.Errorf("no choices returned from OpenAI")
+	}
+	choice := chatCompletion.Choices[0].Message
+	if choice.Refusal != "" {
+		return "", fmt.Errorf("model refused the request: %s", choice.Refusal)
 	}
-	return res, nil
+	return choice.Content, nil
 }
 
-func (o *openaiClientImpl) executeWithRetry(ctx context.Context, operation func() (openai.ChatCompletion, error)) (openai.ChatCompletion, error) {
-	maxRetries := 10
-	baseDelay := 1000 * time.Millisecond
-	maxDelay := 10 * time.Second
-
-	for i := 0; i < maxRetries; i++ {
-		result, err := operation()
-		if err != nil {
-			var apiErr *openai.Error
-			if errors.As(err, &apiErr) && apiErr.StatusCode == 429 {
-				// exponential backoff with full jitter
-				backoffDelay := time.Duration(float64(baseDelay) * math.Pow(2, float64(i)))
-				backoffDelay = min(backoffDelay, maxDelay)
-				delay := time.Duration(rand.Int63n(int64(backoffDelay)))
-				fmt.Printf("\t⚠️ Rate limited: waiting %d ms before retry %d/%d\n", delay.Milliseconds(), i+1, maxRetries)
-				time.Sleep(delay)
-				continue
-			}
-			return openai.ChatCompletion{}, err
-		}
-		return result, nil
-	}
-	return openai.ChatCompletion{}, fmt.Errorf("\t❌ Max retry attempts of %d reached. Operation failed", maxRetries)
+func isOpenAIRateLimit(err error) bool {
+	var apiErr *openai.Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
 }
 
 func generateSchema[T any]() interface{} {
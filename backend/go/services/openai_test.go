@@ -2,6 +2,7 @@ package services
 
 import (
 	"encoding/json"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -75,3 +76,33 @@ func containsString(values []any, target string) bool {
 	}
 	return false
 }
+
+func TestOpenAIJobParsingSchemaConstrainsEligibilityFields(t *testing.T) {
+	b, err := json.Marshal(OpenAIJobParsingSchema)
+	if err != nil {
+		t.Fatalf("marshal schema: %v", err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Enum []string `json:"enum"`
+		} `json:"properties"`
+		Required []any `json:"required"`
+	}
+	if err := json.Unmarshal(b, &schema); err != nil {
+		t.Fatalf("unmarshal schema: %v", err)
+	}
+
+	for field, want := range map[string]string{
+		"VisaSponsorship":   "Not Available",
+		"SecurityClearance": "TS/SCI",
+		"WorkAuthorization": "U.S. Citizen",
+	} {
+		enum := schema.Properties[field].Enum
+		if !slices.Contains(enum, want) || !slices.Contains(enum, "Unspecified") {
+			t.Fatalf("expected %s to be an enum with %q and Unspecified, got %q", field, want, enum)
+		}
+		if !containsString(schema.Required, field) {
+			t.Fatalf("expected %s to be required, got %s", field, b)
+		}
+	}
+}
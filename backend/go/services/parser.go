@@ -7,33 +7,72 @@ import (
 	"gopher-source/utils"
 	"log"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 const yoeRetryInstruction = `The previous extraction returned null for MinYearsExperience. Re-scan the entire source and distinguish required from preferred qualifications. Evaluate every valid qualification path and return the lowest professional-experience minimum among them. Return the lower bound of an explicit required number or range. Return 0 when at least one valid path requires no prior professional experience, including complete requirements that accept education, coursework, an internship, or new-graduate qualifications without an additional professional-experience requirement. Never return 0 for a role identified as Senior or Sr., Staff, Principal, or Director; keep null if such a role has no explicit quantifiable minimum. Seniority may rule out 0 but must never be converted into a positive fallback number. Keep null when the minimum cannot be determined, including missing or visibly truncated qualifications and unquantified mandatory experience. Return the complete structured response.`
 
+// ParserClient enriches a job. When the LLM fails it returns the error along
+// with a LowConfidence job from FallbackParse, so the job is still stored.
 type ParserClient interface {
-	ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, bool)
+	ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, error)
 }
 
 type parserClientImpl struct {
-	openaiClient OpenAIClient
+	llmClient LLMClient
+	cache     EnrichmentCache
+	stats     *models.JobStats
 }
 
-func NewParserService(openaiClient OpenAIClient) ParserClient {
-	return &parserClientImpl{openaiClient: openaiClient}
+// NewParserService adds the token usage of every LLM call, including YOE
+// retries, to stats. A nil stats discards it.
+func NewParserService(llmClient LLMClient, stats *models.JobStats) ParserClient {
+	return NewCachedParserService(llmClient, nil, stats)
 }
 
-func (p *parserClientImpl) ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, bool) {
+// NewCachedParserService answers postings whose content was already parsed
+// from cache, counting each hit in stats.EnrichmentCacheHits.
+func NewCachedParserService(llmClient LLMClient, cache EnrichmentCache, stats *models.JobStats) ParserClient {
+	if stats == nil {
+		stats = &models.JobStats{}
+	}
+	return &parserClientImpl{llmClient: llmClient, cache: cache, stats: stats}
+}
+
+func (p *parserClientImpl) ParseWithStats(ctx context.Context, job *models.Job) (*models.Job, error) {
+	started := time.Now()
+	var cacheKey string
+	if p.cache != nil {
+		cacheKey = p.cache.Key(job.Title, job.Description)
+		if entry, ok := p.cache.Get(cacheKey); ok {
+			atomic.AddInt64(&p.stats.EnrichmentCacheHits, 1)
+			utils.Debug(fmt.Sprintf("\t♻️  Reusing cached enrichment for job: %s", job.Title))
+			enhancedJob := *job
+			populateJobFromResponse(&enhancedJob, entry.Response)
+			enhancedJob.Provenance = cachedProvenance(entry, started)
+			p.stats.AddUnknownSkills(UnknownSkills(&enhancedJob))
+			return &enhancedJob, nil
+		}
+	}
+
+	provenance := newProvenance(started)
 	message := buildJobParsingMessage(job)
-	res, err := p.parseMessage(ctx, message)
+	res, err := p.parseMessage(ctx, message, provenance)
 	if err != nil {
-		log.Printf("Error sending job %s to API: %v", job.JobId, err)
-		return nil, false
+		log.Printf("Error sending job %s to API, using fallback rules: %v", job.JobId, err)
+		atomic.AddInt64(&p.stats.FallbackJobs, 1)
+		fallbackJob := FallbackJob(job)
+		fallbackJob.Provenance.LatencyMs = time.Since(started).Milliseconds()
+		fallbackJob.Provenance.PromptTokens = provenance.PromptTokens
+		fallbackJob.Provenance.CompletionTokens = provenance.CompletionTokens
+		return fallbackJob, err
 	}
 
 	if res.MinYearsExperience == nil {
-		retryRes, retryErr := p.parseMessage(ctx, yoeRetryInstruction+"\n\n"+message)
+		atomic.AddInt64(&p.stats.YOERetries, 1)
+		provenance.RetryUsed = true
+		retryRes, retryErr := p.parseMessage(ctx, yoeRetryInstruction+"\n\n"+message, provenance)
 		if retryErr != nil {
 			log.Printf("YOE retry failed for job %s: %v", job.JobId, retryErr)
 		} else if retryRes.MinYearsExperience != nil {
@@ -43,22 +82,56 @@ func (p *parserClientImpl) ParseWithStats(ctx context.Context, job *models.Job)
 
 	enhancedJob := *job
 	populateJobFromResponse(&enhancedJob, res)
-	return &enhancedJob, true
+	provenance.LatencyMs = time.Since(started).Milliseconds()
+	enhancedJob.Provenance = provenance
+	if p.cache != nil {
+		p.cache.Put(cacheKey, res, *provenance)
+	}
+	p.stats.AddUnknownSkills(UnknownSkills(&enhancedJob))
+	return &enhancedJob, nil
 }
 
-func (p *parserClientImpl) parseMessage(ctx context.Context, message string) (models.OpenAIJobParsingResponse, error) {
-	chatResp, err := p.openaiClient.SendMessage(ctx, message)
+// cachedProvenance credits a cache hit to the extraction that produced it:
+// its model, prompts and time are kept, while the tokens it cost are not
+// counted again.
+func cachedProvenance(entry EnrichmentCacheEntry, started time.Time) *models.Provenance {
+	provenance := newProvenance(started)
+	if entry.Provenance != nil {
+		original := *entry.Provenance
+		original.LatencyMs = provenance.LatencyMs
+		original.PromptTokens = 0
+		original.CompletionTokens = 0
+		provenance = &original
+	}
+	provenance.Cached = true
+	return provenance
+}
+
+// newProvenance stamps an extraction by the current prompts that began at
+// started.
+func newProvenance(started time.Time) *models.Provenance {
+	return &models.Provenance{
+		PromptHash:  enrichmentPromptHash,
+		SchemaHash:  enrichmentSchemaHash,
+		LatencyMs:   time.Since(started).Milliseconds(),
+		ExtractedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func (p *parserClientImpl) parseMessage(ctx context.Context, message string, provenance *models.Provenance) (models.OpenAIJobParsingResponse, error) {
+	responseText, usage, err := p.llmClient.SendMessage(ctx, message)
+	p.stats.AddUsage(usage)
+	provenance.RecordCall(usage)
 	if err != nil {
 		return models.OpenAIJobParsingResponse{}, err
 	}
-	if len(chatResp.Choices) == 0 {
-		return models.OpenAIJobParsingResponse{}, fmt.Errorf("no choices returned from OpenAI")
+	if strings.TrimSpace(responseText) == "" {
+		return models.OpenAIJobParsingResponse{}, fmt.Errorf("empty response from LLM")
 	}
 
-	responseText := chatResp.Choices[0].Message.Content
-	res, err := p.openaiClient.UnmarshalResponse(responseText)
+	res, err := unmarshalJobParsingResponse(responseText)
 	if err != nil {
-		return models.OpenAIJobParsingResponse{}, fmt.Errorf("parse OpenAI response: %w", err)
+		return models.OpenAIJobParsingResponse{}, fmt.Errorf("parse LLM response: %w", err)
 	}
 	return res, nil
 }
@@ -77,12 +150,23 @@ func populateJobFromResponse(job *models.Job, res models.OpenAIJobParsingRespons
 		utils.Debug(fmt.Sprintf("\t🦉 Filtering out non-software related job (based on AI response): %s", job.Title))
 	}
 
+	// fingerprint the listing before its closing date is replaced
+	if job.SourceHash == "" {
+		job.SourceHash = sourceHash(job)
+	}
 	job.Description = ""
 	job.ParsedDescription = res.ParsedDescription
-	job.ExpiresDate = res.DeadlineDate
+	if res.DeadlineDate != "" {
+		job.ExpiresDate = res.DeadlineDate
+	}
 	job.MinDegree = res.MinDegree
 	job.MinYearsExperience = res.MinYearsExperience
 	job.IsSoftwareEngineerRelated = res.IsSoftwareEngineerRelated
+	job.VisaSponsorship = res.VisaSponsorship
+	job.SecurityClearance = res.SecurityClearance
+	job.WorkAuthorization = res.WorkAuthorization
+	job.Seniority = res.Seniority
+	checkSeniority(job)
 
 	if res.Modality != "" {
 		job.Modality = res.Modality
@@ -94,6 +178,9 @@ func populateJobFromResponse(job *models.Job, res models.OpenAIJobParsingRespons
 
 	job.Languages = res.Languages
 	job.Technologies = res.Technologies
+	normalizeJobSkills(job)
+	applyDescriptionPay(job, res)
+	job.ExtractionVersion = enrichmentPromptVersion
 	job.PostedTime = time.Now().UTC().Format(time.RFC3339Nano)
 
 	utils.Debug(fmt.Sprintf("\t🤖 Analyzing job: %s/", job.Title))
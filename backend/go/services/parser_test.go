@@ -2,64 +2,49 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
 
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/shared/constant"
-
 	"gopher-source/models"
 )
 
-type fakeOpenAIClient struct {
-	sendResp         openai.ChatCompletion
-	sendErr          error
-	sendCalls        int
-	messages         []string
-	unmarshalRes     models.OpenAIJobParsingResponse
-	unmarshalResults []models.OpenAIJobParsingResponse
-	unmarshalErr     error
-	unmarshalCalls   int
+// fakeLLMClient returns responses in order, repeating the last one, and
+// reports usage for every call.
+type fakeLLMClient struct {
+	responses []string
+	usage     models.TokenUsage
+	sendErr   error
+	sendCalls int
+	messages  []string
 }
 
-func (f *fakeOpenAIClient) SendMessage(ctx context.Context, message string) (openai.ChatCompletion, error) {
+func (f *fakeLLMClient) SendMessage(ctx context.Context, message string) (string, models.TokenUsage, error) {
 	f.sendCalls++
 	f.messages = append(f.messages, message)
 	if f.sendErr != nil {
-		return openai.ChatCompletion{}, f.sendErr
+		return "", f.usage, f.sendErr
+	}
+	if len(f.responses) == 0 {
+		return "", f.usage, nil
 	}
-	return f.sendResp, nil
+	return f.responses[min(f.sendCalls, len(f.responses))-1], f.usage, nil
 }
 
-func (f *fakeOpenAIClient) UnmarshalResponse(responseText string) (models.OpenAIJobParsingResponse, error) {
-	if f.unmarshalErr != nil {
-		return models.OpenAIJobParsingResponse{}, f.unmarshalErr
+func jobParsingJSON(t *testing.T, res models.OpenAIJobParsingResponse) string {
+	t.Helper()
+	data, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("marshal response: %v", err)
 	}
-	if f.unmarshalCalls < len(f.unmarshalResults) {
-		result := f.unmarshalResults[f.unmarshalCalls]
-		f.unmarshalCalls++
-		return result, nil
-	}
-	f.unmarshalCalls++
-	return f.unmarshalRes, nil
+	return string(data)
 }
 
 func TestParseWithStatsSuccess(t *testing.T) {
 	minYearsExperience := 5
-	client := &fakeOpenAIClient{
-		sendResp: openai.ChatCompletion{
-			Choices: []openai.ChatCompletionChoice{
-				{
-					Message: openai.ChatCompletionMessage{
-						Content: "response",
-						Refusal: "",
-						Role:    constant.Assistant("assistant"),
-					},
-				},
-			},
-		},
-		unmarshalRes: models.OpenAIJobParsingResponse{
+	client := &fakeLLMClient{
+		responses: []string{jobParsingJSON(t, models.OpenAIJobParsingResponse{
 			ParsedDescription:         "parsed",
 			DeadlineDate:              "tomorrow",
 			MinDegree:                 "Bachelor's",
@@ -69,18 +54,18 @@ func TestParseWithStatsSuccess(t *testing.T) {
 			Languages:                 []string{"Go"},
 			Technologies:              []string{"AWS"},
 			IsSoftwareEngineerRelated: true,
-		},
+		})},
 	}
-	parser := NewParserService(client)
+	parser := NewParserService(client, nil)
 
 	job := models.Job{
 		JobId:       "123",
 		Title:       "Engineer",
 		Description: "Desc",
 	}
-	enhanced, ok := parser.ParseWithStats(context.Background(), &job)
-	if !ok || enhanced == nil {
-		t.Fatalf("expected success, got ok=%v job=%v", ok, enhanced)
+	enhanced, err := parser.ParseWithStats(context.Background(), &job)
+	if err != nil || enhanced == nil {
+		t.Fatalf("expected success, got err=%v job=%v", err, enhanced)
 	}
 	if enhanced.ParsedDescription != "parsed" || enhanced.Modality != "Remote" || enhanced.Description != "" {
 		t.Fatalf("expected job fields populated, got %+v", enhanced)
@@ -88,6 +73,9 @@ func TestParseWithStatsSuccess(t *testing.T) {
 	if client.sendCalls != 1 {
 		t.Fatalf("expected no retry for non-null YOE, got %d API calls", client.sendCalls)
 	}
+	if enhanced.Provenance == nil || enhanced.Provenance.RetryUsed || enhanced.Provenance.Cached {
+		t.Fatalf("expected provenance for a single uncached call, got %+v", enhanced.Provenance)
+	}
 }
 
 func TestPopulateJobFromResponsePreservesKnownZeroAndUnknown(t *testing.T) {
@@ -104,24 +92,68 @@ func TestPopulateJobFromResponsePreservesKnownZeroAndUnknown(t *testing.T) {
 	}
 }
 
+func TestParseWithStatsExtractsEligibilityRequirements(t *testing.T) {
+	tests := []struct {
+		name          string
+		visa          string
+		clearance     string
+		authorization string
+		json          string
+	}{
+		{"cleared citizen", "Unspecified", "TS/SCI", "U.S. Citizen", `"visaSponsorship":"Unspecified","securityClearance":"TS/SCI","workAuthorization":"U.S. Citizen"`},
+		{"no sponsorship", "Not Available", "Unspecified", "Authorized to Work", `"visaSponsorship":"Not Available","securityClearance":"Unspecified","workAuthorization":"Authorized to Work"`},
+		{"export controlled", "Not Available", "Secret", "U.S. Person", `"visaSponsorship":"Not Available","securityClearance":"Secret","workAuthorization":"U.S. Person"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minYearsExperience := 2
+			client := &fakeLLMClient{responses: []string{jobParsingJSON(t, models.OpenAIJobParsingResponse{
+				MinYearsExperience:        &minYearsExperience,
+				VisaSponsorship:           tt.visa,
+				SecurityClearance:         tt.clearance,
+				WorkAuthorization:         tt.authorization,
+				IsSoftwareEngineerRelated: true,
+			})}}
+			job := models.Job{JobId: "123", Title: "Engineer", Description: "Desc"}
+
+			enhanced, err := NewParserService(client, nil).ParseWithStats(context.Background(), &job)
+			if err != nil {
+				t.Fatalf("ParseWithStats returned error: %v", err)
+			}
+			if enhanced.VisaSponsorship != tt.visa || enhanced.SecurityClearance != tt.clearance || enhanced.WorkAuthorization != tt.authorization {
+				t.Fatalf("expected %q, %q, %q, got %q, %q, %q", tt.visa, tt.clearance, tt.authorization,
+					enhanced.VisaSponsorship, enhanced.SecurityClearance, enhanced.WorkAuthorization)
+			}
+			data, err := json.Marshal(enhanced)
+			if err != nil {
+				t.Fatalf("marshal job: %v", err)
+			}
+			if !strings.Contains(string(data), tt.json) {
+				t.Fatalf("expected snapshot JSON to contain %s, got %s", tt.json, data)
+			}
+		})
+	}
+}
+
 func TestParseWithStatsRetriesNullYOE(t *testing.T) {
 	fiveYears := 5
-	client := &fakeOpenAIClient{
-		sendResp: successfulChatCompletion(),
-		unmarshalResults: []models.OpenAIJobParsingResponse{
-			{ParsedDescription: "first pass", IsSoftwareEngineerRelated: true},
-			{ParsedDescription: "retry", MinYearsExperience: &fiveYears, IsSoftwareEngineerRelated: true},
+	client := &fakeLLMClient{
+		responses: []string{
+			jobParsingJSON(t, models.OpenAIJobParsingResponse{ParsedDescription: "first pass", IsSoftwareEngineerRelated: true}),
+			jobParsingJSON(t, models.OpenAIJobParsingResponse{ParsedDescription: "retry", MinYearsExperience: &fiveYears, IsSoftwareEngineerRelated: true}),
 		},
+		usage: models.TokenUsage{Model: "gpt-4.1-nano", Calls: 1, PromptTokens: 800, CompletionTokens: 120, CostUSD: 0.0001},
 	}
-	parser := NewParserService(client)
+	stats := &models.JobStats{}
+	parser := NewParserService(client, stats)
 
-	job, ok := parser.ParseWithStats(context.Background(), &models.Job{
+	job, err := parser.ParseWithStats(context.Background(), &models.Job{
 		JobId:       "yoe-retry",
 		Title:       "Backend Engineer",
 		Description: "Required qualifications include 5+ years of software engineering experience.",
 	})
-	if !ok || job == nil {
-		t.Fatalf("expected successful parse, got ok=%v job=%v", ok, job)
+	if err != nil || job == nil {
+		t.Fatalf("expected successful parse, got err=%v job=%v", err, job)
 	}
 	if client.sendCalls != 2 {
 		t.Fatalf("expected one YOE retry, got %d API calls", client.sendCalls)
@@ -135,22 +167,32 @@ func TestParseWithStatsRetriesNullYOE(t *testing.T) {
 	if !strings.Contains(client.messages[1], yoeRetryInstruction) {
 		t.Fatalf("expected focused retry instruction, got %q", client.messages[1])
 	}
+	snapshot := stats.Snapshot()
+	if usage := snapshot.Usage["gpt-4.1-nano"]; snapshot.YOERetries != 1 || usage.Calls != 2 || usage.PromptTokens != 1600 || usage.CompletionTokens != 240 {
+		t.Fatalf("expected both calls in usage with one YOE retry, got retries=%d usage=%+v", snapshot.YOERetries, usage)
+	}
+	provenance := job.Provenance
+	if provenance == nil || provenance.Model != "gpt-4.1-nano" || !provenance.RetryUsed || provenance.PromptTokens != 1600 || provenance.CompletionTokens != 240 {
+		t.Fatalf("expected provenance covering both calls, got %+v", provenance)
+	}
+	if provenance.PromptHash != enrichmentPromptHash || provenance.SchemaHash != enrichmentSchemaHash || provenance.ExtractedAt == "" {
+		t.Fatalf("expected prompt and schema hashes with a timestamp, got %+v", provenance)
+	}
 }
 
 func TestParseWithStatsRetriesNullYOEWithoutExplicitCue(t *testing.T) {
-	client := &fakeOpenAIClient{
-		sendResp:     successfulChatCompletion(),
-		unmarshalRes: models.OpenAIJobParsingResponse{IsSoftwareEngineerRelated: true},
+	client := &fakeLLMClient{
+		responses: []string{jobParsingJSON(t, models.OpenAIJobParsingResponse{IsSoftwareEngineerRelated: true})},
 	}
-	parser := NewParserService(client)
+	parser := NewParserService(client, nil)
 
-	job, ok := parser.ParseWithStats(context.Background(), &models.Job{
+	job, err := parser.ParseWithStats(context.Background(), &models.Job{
 		JobId:       "yoe-retry-confirm-null",
 		Title:       "Backend Engineer",
 		Description: "Build and operate backend services.",
 	})
-	if !ok || job == nil {
-		t.Fatalf("expected successful parse, got ok=%v job=%v", ok, job)
+	if err != nil || job == nil {
+		t.Fatalf("expected successful parse, got err=%v job=%v", err, job)
 	}
 	if client.sendCalls != 2 {
 		t.Fatalf("expected one YOE retry, got %d API calls", client.sendCalls)
@@ -163,19 +205,6 @@ func TestParseWithStatsRetriesNullYOEWithoutExplicitCue(t *testing.T) {
 	}
 }
 
-func successfulChatCompletion() openai.ChatCompletion {
-	return openai.ChatCompletion{
-		Choices: []openai.ChatCompletionChoice{
-			{
-				Message: openai.ChatCompletionMessage{
-					Content: "response",
-					Role:    constant.Assistant("assistant"),
-				},
-			},
-		},
-	}
-}
-
 func jobWithResponseMinYears(minYears *int) models.Job {
 	job := models.Job{}
 	populateJobFromResponse(&job, models.OpenAIJobParsingResponse{MinYearsExperience: minYears})
@@ -183,47 +212,61 @@ func jobWithResponseMinYears(minYears *int) models.Job {
 }
 
 func TestParseWithStatsHandlesSendError(t *testing.T) {
-	client := &fakeOpenAIClient{
+	client := &fakeLLMClient{
 		sendErr: errors.New("network"),
 	}
-	parser := NewParserService(client)
+	parser := NewParserService(client, nil)
 
-	if job, ok := parser.ParseWithStats(context.Background(), &models.Job{JobId: "1"}); job != nil || ok {
-		t.Fatalf("expected failure when SendMessage errors, got job=%v ok=%v", job, ok)
+	if job, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "1"}); err == nil || job == nil || !job.LowConfidence {
+		t.Fatalf("expected a low-confidence fallback when SendMessage errors, got job=%v err=%v", job, err)
 	}
 }
 
-func TestParseWithStatsHandlesEmptyChoices(t *testing.T) {
-	client := &fakeOpenAIClient{
-		sendResp: openai.ChatCompletion{
-			Choices: []openai.ChatCompletionChoice{},
-		},
+func TestParseWithStatsHandlesEmptyResponse(t *testing.T) {
+	client := &fakeLLMClient{
+		responses: []string{"  "},
 	}
-	parser := NewParserService(client)
+	parser := NewParserService(client, nil)
 
-	if job, ok := parser.ParseWithStats(context.Background(), &models.Job{JobId: "2"}); job != nil || ok {
-		t.Fatalf("expected failure when no choices returned")
+	if job, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "2"}); err == nil || job == nil || !job.LowConfidence {
+		t.Fatalf("expected a low-confidence fallback when the response is empty")
 	}
 }
 
 func TestParseWithStatsHandlesUnmarshalError(t *testing.T) {
-	client := &fakeOpenAIClient{
-		sendResp: openai.ChatCompletion{
-			Choices: []openai.ChatCompletionChoice{
-				{
-					Message: openai.ChatCompletionMessage{
-						Content: "invalid",
-						Refusal: "",
-						Role:    constant.Assistant("assistant"),
-					},
-				},
-			},
-		},
-		unmarshalErr: errors.New("bad json"),
+	client := &fakeLLMClient{
+		responses: []string{"invalid"},
+	}
+	parser := NewParserService(client, nil)
+
+	if job, err := parser.ParseWithStats(context.Background(), &models.Job{JobId: "3"}); err == nil || job == nil || !job.LowConfidence {
+		t.Fatalf("expected a low-confidence fallback when parsing response fails")
 	}
-	parser := NewParserService(client)
+}
 
-	if job, ok := parser.ParseWithStats(context.Background(), &models.Job{JobId: "3"}); job != nil || ok {
-		t.Fatalf("expected failure when parsing response fails")
+func TestParseWithStatsFallsBackToRulesWhenLLMFails(t *testing.T) {
+	stats := &models.JobStats{}
+	parser := NewParserService(&fakeLLMClient{sendErr: errors.New("rate limited")}, stats)
+	description := "Hybrid, two days a week in our Seattle office. Requires a Bachelor's degree and 3+ years of professional experience with Go and PostgreSQL."
+
+	job, err := parser.ParseWithStats(context.Background(), &models.Job{
+		JobId:       "fallback",
+		Title:       "Backend Engineer",
+		Description: description,
+	})
+	if err == nil || job == nil {
+		t.Fatalf("expected a stored fallback reported as a failure, got err=%v job=%v", err, job)
+	}
+	if !job.LowConfidence || job.Description != description {
+		t.Fatalf("expected a low-confidence job keeping its description, got %+v", job)
+	}
+	if job.MinYearsExperience == nil || *job.MinYearsExperience != 3 || job.Domain != "Backend" || job.Modality != "Hybrid" {
+		t.Fatalf("expected rule-based fields, got %+v", job)
+	}
+	if stats.Snapshot().FallbackJobs != 1 {
+		t.Fatalf("expected 1 fallback job, got %d", stats.Snapshot().FallbackJobs)
+	}
+	if job.Provenance == nil || job.Provenance.Model != "" || job.Provenance.PromptHash != "" || job.Provenance.ExtractedAt == "" {
+		t.Fatalf("expected fallback provenance without a model or prompt, got %+v", job.Provenance)
 	}
 }
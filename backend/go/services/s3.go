@@ -2,6 +2,7 @@ package services
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -24,6 +25,8 @@ type S3Client interface {
 	UploadFile(ctx context.Context, bucketName string, objectKey string, fileName string) error
 	DownloadFile(ctx context.Context, bucketName string, objectKey string, fileName string) error
 	WriteJobsToJSONLFile(filename string, jobs []models.Job) error
+	PutObject(ctx context.Context, bucketName string, objectKey string, body []byte) error
+	GetObject(ctx context.Context, bucketName string, objectKey string) ([]byte, error)
 }
 
 type s3ClientImpl struct {
@@ -101,6 +104,39 @@ func (s *s3ClientImpl) DownloadFile(ctx context.Context, bucketName string, obje
 	return err
 }
 
+// PutObject writes body without waiting for the object to become visible,
+// for small objects written many times per run.
+func (s *s3ClientImpl) PutObject(ctx context.Context, bucketName string, objectKey string, body []byte) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   bytes.NewReader(body),
+	}
+	if ct := contentTypeForKey(objectKey); ct != "" {
+		input.ContentType = aws.String(ct)
+	}
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		return fmt.Errorf("put object %s/%s: %w", bucketName, objectKey, err)
+	}
+	return nil
+}
+
+func (s *s3ClientImpl) GetObject(ctx context.Context, bucketName string, objectKey string) ([]byte, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object %s/%s: %w", bucketName, objectKey, err)
+	}
+	defer result.Body.Close()
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read object %s/%s: %w", bucketName, objectKey, err)
+	}
+	return body, nil
+}
+
 func (s *s3ClientImpl) WriteJobsToJSONLFile(filename string, jobs []models.Job) error {
 	file, err := os.Create(filename)
 	if err != nil {
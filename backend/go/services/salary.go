@@ -0,0 +1,224 @@
+package services
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopher-source/models"
+)
+
+// Values of models.Job.SalarySource.
+const (
+	SalarySourceListing     = "listing"
+	SalarySourceDescription = "description"
+)
+
+const defaultSalaryCurrency = "USD"
+
+// periodsPerYear annualizes pay assuming full-time work: 40 hours a week,
+// five days a week and 52 weeks a year.
+var periodsPerYear = map[string]float64{
+	"hour":  2080,
+	"day":   260,
+	"week":  52,
+	"month": 12,
+	"year":  1,
+}
+
+var (
+	payNumberPattern = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(k\b)?`)
+	payDOEPattern    = regexp.MustCompile(`(?i)\b(doe|depends on experience|dependent on experience|commensurate with experience|negotiable)\b`)
+	payPeriodPattern = regexp.MustCompile(`(?i)(?:/|\bper\s+|\ban\s+|\ba\s+)\s*(hour|hr|day|week|wk|month|mo|year|yr|annum)\b`)
+	payCodePattern   = regexp.MustCompile(`\b(USD|CAD|EUR|GBP|AUD|MXN|INR)\b`)
+)
+
+// payRange is pay read from a listing or the LLM before it is set on a job.
+type payRange struct {
+	min, max float64
+	currency string
+	period   string
+	doe      bool
+}
+
+// parseListingPay reads a listing's pay, such as WorkSourceWA's "150,000 -
+// $180,000" with payType "Salary", "$45.50/hr", "60k-80k" or "DOE". payType
+// or a period in the amount wins over guessing the period from the size of
+// the numbers.
+func parseListingPay(amount, payType string) (payRange, bool) {
+	amount = strings.TrimSpace(amount)
+	if amount == "" {
+		return payRange{}, false
+	}
+	pay := payRange{currency: payCurrency(amount), period: listingPayPeriod(payType)}
+	if match := payPeriodPattern.FindStringSubmatch(amount); match != nil {
+		pay.period = normalizePayPeriod(match[1])
+	}
+
+	var values []float64
+	for _, match := range payNumberPattern.FindAllStringSubmatch(amount, 2) {
+		value, err := strconv.ParseFloat(strings.ReplaceAll(match[1], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		if match[2] != "" {
+			value *= 1000
+		}
+		values = append(values, value)
+	}
+	switch len(values) {
+	case 0:
+		if payDOEPattern.MatchString(amount) {
+			return payRange{doe: true}, true
+		}
+		return payRange{}, false
+	case 1:
+		pay.min, pay.max = values[0], values[0]
+	default:
+		pay.min, pay.max = min(values[0], values[1]), max(values[0], values[1])
+	}
+	if pay.min <= 0 && pay.max <= 0 {
+		return payRange{}, false
+	}
+	pay.doe = payDOEPattern.MatchString(amount)
+	if pay.period == "" {
+		pay.period = inferPayPeriod(pay.max)
+	}
+	return pay, true
+}
+
+// responsePay reads the range the LLM found in the description.
+func responsePay(res models.OpenAIJobParsingResponse) (payRange, bool) {
+	if res.SalaryMin == nil && res.SalaryMax == nil {
+		return payRange{}, false
+	}
+	pay := payRange{currency: strings.ToUpper(strings.TrimSpace(res.SalaryCurrency))}
+	switch {
+	case res.SalaryMin == nil:
+		pay.min, pay.max = *res.SalaryMax, *res.SalaryMax
+	case res.SalaryMax == nil:
+		pay.min, pay.max = *res.SalaryMin, *res.SalaryMin
+	default:
+		pay.min, pay.max = min(*res.SalaryMin, *res.SalaryMax), max(*res.SalaryMin, *res.SalaryMax)
+	}
+	if pay.max <= 0 {
+		return payRange{}, false
+	}
+	if pay.currency == "" {
+		pay.currency = defaultSalaryCurrency
+	}
+	pay.period = normalizePayPeriod(res.SalaryPeriod)
+	if pay.period == "" {
+		pay.period = inferPayPeriod(pay.max)
+	}
+	return pay, true
+}
+
+// setJobPay replaces the job's structured pay. Annual figures are left unset
+// when the period is unknown.
+func setJobPay(job *models.Job, pay payRange, source string) {
+	clearJobPay(job)
+	job.SalarySource = source
+	job.SalaryDOE = pay.doe
+	if pay.max <= 0 {
+		return
+	}
+	job.SalaryMin = floatPtr(pay.min)
+	job.SalaryMax = floatPtr(pay.max)
+	job.SalaryCurrency = pay.currency
+	job.SalaryPeriod = pay.period
+	if multiplier, ok := periodsPerYear[pay.period]; ok {
+		job.SalaryAnnualMin = floatPtr(pay.min * multiplier)
+		job.SalaryAnnualMax = floatPtr(pay.max * multiplier)
+	}
+}
+
+func clearJobPay(job *models.Job) {
+	job.SalaryMin, job.SalaryMax = nil, nil
+	job.SalaryAnnualMin, job.SalaryAnnualMax = nil, nil
+	job.SalaryCurrency, job.SalaryPeriod, job.SalarySource = "", "", ""
+	job.SalaryDOE = false
+}
+
+// applyListingPay sets the structured pay of a listing that states any.
+func applyListingPay(job *models.Job, amount, payType string) {
+	if pay, ok := parseListingPay(amount, payType); ok {
+		setJobPay(job, pay, SalarySourceListing)
+	}
+}
+
+// applyDescriptionPay sets the pay the LLM found in the description unless
+// the listing stated its own.
+func applyDescriptionPay(job *models.Job, res models.OpenAIJobParsingResponse) {
+	if job.SalarySource == SalarySourceListing {
+		return
+	}
+	clearJobPay(job)
+	if pay, ok := responsePay(res); ok {
+		setJobPay(job, pay, SalarySourceDescription)
+	}
+}
+
+func payCurrency(amount string) string {
+	switch {
+	case strings.Contains(amount, "€"):
+		return "EUR"
+	case strings.Contains(amount, "£"):
+		return "GBP"
+	}
+	if code := payCodePattern.FindString(strings.ToUpper(amount)); code != "" {
+		return code
+	}
+	return defaultSalaryCurrency
+}
+
+func listingPayPeriod(payType string) string {
+	switch strings.ToLower(strings.TrimSpace(payType)) {
+	case "salary", "annual", "yearly":
+		return "year"
+	case "hourly":
+		return "hour"
+	case "daily":
+		return "day"
+	case "weekly":
+		return "week"
+	case "monthly":
+		return "month"
+	default:
+		return ""
+	}
+}
+
+func normalizePayPeriod(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "hour", "hr", "hourly":
+		return "hour"
+	case "day", "daily":
+		return "day"
+	case "week", "wk", "weekly":
+		return "week"
+	case "month", "mo", "monthly":
+		return "month"
+	case "year", "yr", "annum", "annual", "yearly":
+		return "year"
+	default:
+		return ""
+	}
+}
+
+// inferPayPeriod guesses only where the size of the pay leaves no doubt:
+// nobody is paid $150 a year or $90,000 an hour.
+func inferPayPeriod(highest float64) string {
+	switch {
+	case highest > 0 && highest < 300:
+		return "hour"
+	case highest >= 20000:
+		return "year"
+	default:
+		return ""
+	}
+}
+
+func floatPtr(value float64) *float64 {
+	return &value
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestParseListingPay(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		payType  string
+		expected payRange
+		ok       bool
+	}{
+		{"salaryRange", "150,000 - $180,000", "Salary", payRange{min: 150000, max: 180000, currency: "USD", period: "year"}, true},
+		{"hourlyRange", "$45-$55", "Hourly", payRange{min: 45, max: 55, currency: "USD", period: "hour"}, true},
+		{"periodInAmount", "$32.50/hr", "", payRange{min: 32.5, max: 32.5, currency: "USD", period: "hour"}, true},
+		{"thousands", "60k-80k", "", payRange{min: 60000, max: 80000, currency: "USD", period: "year"}, true},
+		{"reversed", "$90,000 - $70,000 CAD", "Salary", payRange{min: 70000, max: 90000, currency: "CAD", period: "year"}, true},
+		{"monthlyUnknown", "$6,000", "", payRange{min: 6000, max: 6000, currency: "USD"}, true},
+		{"doe", "DOE", "Salary", payRange{doe: true}, true},
+		{"rangeDOE", "$25 - $35 DOE", "Hourly", payRange{min: 25, max: 35, currency: "USD", period: "hour", doe: true}, true},
+		{"empty", "", "Salary", payRange{}, false},
+		{"competitive", "Competitive", "", payRange{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pay, ok := parseListingPay(tt.amount, tt.payType)
+			if ok != tt.ok || pay != tt.expected {
+				t.Fatalf("parseListingPay(%q, %q) = %+v, %v; want %+v, %v", tt.amount, tt.payType, pay, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
+
+func TestSetJobPayAnnualizes(t *testing.T) {
+	job := models.Job{}
+	setJobPay(&job, payRange{min: 45, max: 55, currency: "USD", period: "hour"}, SalarySourceListing)
+	if *job.SalaryAnnualMin != 93600 || *job.SalaryAnnualMax != 114400 || job.SalarySource != SalarySourceListing {
+		t.Fatalf("expected hourly pay annualized at 2080 hours, got %+v", job)
+	}
+
+	setJobPay(&job, payRange{min: 6000, max: 6000, currency: "USD"}, SalarySourceListing)
+	if job.SalaryMin == nil || job.SalaryAnnualMin != nil || job.SalaryPeriod != "" {
+		t.Fatalf("expected no annual pay for an unknown period, got %+v", job)
+	}
+}
+
+func TestApplyDescriptionPayKeepsListingPay(t *testing.T) {
+	low, high := 120000.0, 150000.0
+	res := models.OpenAIJobParsingResponse{SalaryMin: &low, SalaryMax: &high, SalaryPeriod: "year"}
+
+	described := models.Job{}
+	applyDescriptionPay(&described, res)
+	if described.SalarySource != SalarySourceDescription || *described.SalaryMin != low || described.SalaryCurrency != "USD" || *described.SalaryAnnualMax != high {
+		t.Fatalf("expected pay from the description, got %+v", described)
+	}
+
+	listed := models.Job{}
+	applyListingPay(&listed, "$45-$55", "Hourly")
+	applyDescriptionPay(&listed, res)
+	if listed.SalarySource != SalarySourceListing || *listed.SalaryMin != 45 {
+		t.Fatalf("expected listing pay to win, got %+v", listed)
+	}
+
+	applyDescriptionPay(&described, models.OpenAIJobParsingResponse{SalaryPeriod: "unspecified"})
+	if described.SalaryMin != nil || described.SalarySource != "" {
+		t.Fatalf("expected a reparse without pay to clear description pay, got %+v", described)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -30,8 +32,15 @@ const (
 var jobHTMLTagPattern = regexp.MustCompile(`<[^>]+>`)
 
 type ScraperClient interface {
-	ScrapeJobs(ctx context.Context, query string, jobsChan chan<- models.Job, stats *models.JobStats) error
+	ScrapeJobs(ctx context.Context, plan []config.ScrapeQuery, jobsChan chan<- models.Job, stats *models.JobStats) error
 	GetProcessedIDs() map[string]bool
+	GetQueryStats() []models.QueryStats
+	GetSearchCoverage() []models.SearchCoverage
+	SetWatermarks(watermarks map[string]models.ScrapeWatermark)
+	SkipUnchangedPostings(store JobStore)
+	GetWatermarks() map[string]models.ScrapeWatermark
+	ResumeFrom(checkpoint models.ScrapeCheckpoint)
+	Checkpoint() models.ScrapeCheckpoint
 }
 
 type scraperClientImpl struct {
@@ -39,6 +48,23 @@ type scraperClientImpl struct {
 	debugEnabled bool
 	processedIDs map[string]bool
 	mutex        sync.Mutex
+	sources      []JobSource
+	// watermarks stays nil until SetWatermarks enables incremental searches
+	watermarks map[string]models.ScrapeWatermark
+	queryStats []models.QueryStats
+	// checkpoint state: carried over by ResumeFrom and updated as ScrapeJobs
+	// runs so an interrupted run can be resumed
+	completedQueries map[string]bool
+	cursors          map[string]models.ScrapeCursor
+	pendingJobs      []models.Job
+	// coverage is only collected when posting history is on
+	coverage []models.SearchCoverage
+	// postings stays nil unless SkipUnchangedPostings is called
+	postings JobStore
+}
+
+type workSourceJobSource struct {
+	config       config.Config
 	httpClient   *http.Client
 	apexEndpoint string
 }
@@ -56,6 +82,23 @@ type scraperApexResponse[T any] struct {
 	ReturnValue T `json:"returnValue"`
 }
 
+// workSourceCursor carries the loadMoreJobs state between pages and, inside a
+// ScrapeCursor, across invocations.
+type workSourceCursor struct {
+	Filters         json.RawMessage `json:"filters"`
+	FilterMap       json.RawMessage `json:"filterMap"`
+	GeoWrapper      json.RawMessage `json:"geoWrapper"`
+	BatchSize       int             `json:"batchSize"`
+	LastRecordID    string          `json:"lastRecordId"`
+	LastPostingDate string          `json:"lastPostingDate"`
+	TotalCount      int             `json:"totalCount"`
+	Fetched         int             `json:"fetched"`
+	SearchCalls     int             `json:"searchCalls"`
+	// Pages counts the calls made against MaxPages; a cursor carried past
+	// MaxPages starts the next run at zero
+	Pages int `json:"pages"`
+}
+
 type workSourceSearchResponse struct {
 	Jobs              []workSourceJob `json:"jobs"`
 	TotalCount        int             `json:"totalCount"`
@@ -80,134 +123,536 @@ type workSourceJob struct {
 	Description  string `json:"description"`
 }
 
+func init() {
+	RegisterJobSource(WorkSourceSourceName, func(cfg config.Config) (JobSource, error) {
+		return NewWorkSourceJobSource(cfg), nil
+	})
+}
+
 func NewScraper(config config.Config, debugEnabled bool) ScraperClient {
-	return newScraper(config, debugEnabled, make(map[string]bool))
+	return newScraper(config, debugEnabled, make(map[string]bool), nil)
 }
 
 func NewScraperWithKeyset(config config.Config, debugEnabled bool, existingKeySet map[string]bool) ScraperClient {
-	return newScraper(config, debugEnabled, existingKeySet)
+	return newScraper(config, debugEnabled, existingKeySet, nil)
+}
+
+// NewScraperWithSources fans each search out over the given sources; a nil or
+// empty list falls back to WorkSourceWA alone.
+func NewScraperWithSources(config config.Config, debugEnabled bool, existingKeySet map[string]bool, sources []JobSource) ScraperClient {
+	return newScraper(config, debugEnabled, existingKeySet, sources)
 }
 
-func newScraper(cfg config.Config, debugEnabled bool, processedIDs map[string]bool) ScraperClient {
+func newScraper(cfg config.Config, debugEnabled bool, processedIDs map[string]bool, sources []JobSource) ScraperClient {
 	if processedIDs == nil {
 		processedIDs = make(map[string]bool)
 	}
+	if len(sources) == 0 {
+		sources = []JobSource{NewWorkSourceJobSource(cfg)}
+	}
 	return &scraperClientImpl{
+		config:           cfg,
+		debugEnabled:     debugEnabled,
+		processedIDs:     processedIDs,
+		sources:          sources,
+		completedQueries: make(map[string]bool),
+		cursors:          make(map[string]models.ScrapeCursor),
+	}
+}
+
+func NewWorkSourceJobSource(cfg config.Config) JobSource {
+	return &workSourceJobSource{
 		config:       cfg,
-		debugEnabled: debugEnabled,
-		processedIDs: processedIDs,
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   NewHTTPClient(cfg, 30*time.Second),
 		apexEndpoint: workSourceApexEndpoint,
 	}
 }
 
-func (s *scraperClientImpl) ScrapeJobs(ctx context.Context, query string, jobsChan chan<- models.Job, stats *models.JobStats) error {
+// ScrapeJobs runs each plan query in order, searching every configured source
+// concurrently, and merges unseen listings into jobsChan, which is closed once
+// the plan finishes. A job found by an earlier query is skipped by later ones.
+// Jobs left pending by a resumed checkpoint are sent first, and queries it
+// completed are skipped.
+func (s *scraperClientImpl) ScrapeJobs(ctx context.Context, plan []config.ScrapeQuery, jobsChan chan<- models.Job, stats *models.JobStats) error {
 	defer close(jobsChan)
 
-	listings, searchCalls, totalCount, searchErr := s.searchJobs(ctx, query)
+	s.mutex.Lock()
+	pending := s.pendingJobs
+	s.pendingJobs = nil
+	s.mutex.Unlock()
+	if len(pending) > 0 {
+		utils.Debug(fmt.Sprintf("Resuming %d pending job(s) from checkpoint", len(pending)))
+		if err := s.sendJobs(ctx, pending, jobsChan, stats, nil); err != nil {
+			return err
+		}
+	}
+
+	var planErrs []error
+	for _, query := range plan {
+		if err := ctx.Err(); err != nil {
+			planErrs = append(planErrs, err)
+			break
+		}
+
+		s.mutex.Lock()
+		completed := s.completedQueries[query.Key()]
+		s.mutex.Unlock()
+		if completed {
+			utils.Debug(fmt.Sprintf("Skipping query '%s' completed before checkpoint", query.Label()))
+			continue
+		}
+
+		queryStats := &models.QueryStats{Query: query.Label()}
+		var wg sync.WaitGroup
+		sourceErrs := make([]error, len(s.sources))
+		for i, source := range s.sources {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				sourceErrs[i] = s.scrapeSource(ctx, source, query, jobsChan, stats, queryStats)
+			}()
+		}
+		wg.Wait()
+
+		if err := errors.Join(sourceErrs...); err != nil {
+			queryStats.Error = err.Error()
+			if len(plan) > 1 {
+				err = fmt.Errorf("query %q: %w", query.Label(), err)
+			}
+			planErrs = append(planErrs, err)
+		}
+		utils.Debug(fmt.Sprintf("Query '%s': %d listing(s), %d new, %d skipped", queryStats.Query, queryStats.Listings, queryStats.NewJobs, queryStats.SkippedJobs))
+
+		s.mutex.Lock()
+		s.queryStats = append(s.queryStats, *queryStats)
+		if ctx.Err() == nil {
+			s.completedQueries[query.Key()] = true
+		}
+		s.mutex.Unlock()
+	}
+
+	return errors.Join(planErrs...)
+}
+
+func (s *scraperClientImpl) scrapeSource(ctx context.Context, source JobSource, query config.ScrapeQuery, jobsChan chan<- models.Job, stats *models.JobStats, queryStats *models.QueryStats) error {
+	s.mutex.Lock()
+	_, resumed := s.cursors[ScrapeWatermarkKey(source.Name(), query)]
+	s.mutex.Unlock()
+
+	listings, since, searchErr := s.searchSource(ctx, source, query)
 	if searchErr != nil {
-		log.Printf("Error searching WorkSourceWA: %v", searchErr)
+		log.Printf("Error searching %s: %v", source.Name(), searchErr)
+		searchErr = fmt.Errorf("%s: %w", source.Name(), searchErr)
 		if len(listings) == 0 {
 			return searchErr
 		}
 	}
-	utils.Debug(fmt.Sprintf("WorkSourceWA returned %d of %d jobs in %d search call(s)", len(listings), totalCount, searchCalls))
 
-	for _, listing := range listings {
+	for i := range listings {
+		if listings[i].JobId == "" {
+			log.Printf("Skipping %s job without an ID", source.Name())
+		} else if listings[i].Source == "" {
+			listings[i].Source = source.Name()
+		}
+	}
+	// coverage keeps the listings the watermark covers, so postings behind it
+	// are still seen; a resumed search skipped the pages before its cursor
+	if s.config.UsePostingHistory && searchErr == nil && !resumed {
+		s.mutex.Lock()
+		s.coverage = append(s.coverage, models.SearchCoverage{Source: source.Name(), Query: query.Key(), Jobs: listings})
+		s.mutex.Unlock()
+	}
+	jobs := dropCovered(listings, since)
+	atomic.AddInt64(&queryStats.Listings, int64(len(jobs)))
+	if err := s.sendJobs(ctx, jobs, jobsChan, stats, queryStats); err != nil {
+		return err
+	}
+	return searchErr
+}
+
+// sendJobs forwards unseen jobs to jobsChan. If ctx ends first, the jobs not
+// yet sent are kept for the checkpoint instead of being marked processed.
+func (s *scraperClientImpl) sendJobs(ctx context.Context, jobs []models.Job, jobsChan chan<- models.Job, stats *models.JobStats, queryStats *models.QueryStats) error {
+	unchanged := s.unchangedPostings(ctx, jobs)
+	for i, job := range jobs {
 		if err := ctx.Err(); err != nil {
+			s.deferJobs(jobs[i:])
 			return err
 		}
-		if listing.RecordID == "" {
-			log.Printf("Skipping WorkSourceWA job without a recordId")
+		if job.JobId == "" {
 			continue
 		}
 
+		key := s.cacheKey(&job)
 		s.mutex.Lock()
-		seen := s.processedIDs[listing.RecordID]
+		seen := s.processedIDs[key]
 		if !seen {
-			s.processedIDs[listing.RecordID] = true
+			s.processedIDs[key] = true
 		}
 		s.mutex.Unlock()
 
-		if seen {
-			utils.Debug(fmt.Sprintf("\tSkipping already processed job: %s", listing.RecordID))
+		if seen || unchanged[i] {
+			if seen {
+				utils.Debug(fmt.Sprintf("\tSkipping already processed job: %s", job.JobId))
+			} else {
+				utils.Debug(fmt.Sprintf("\tSkipping unchanged posting: %s", job.JobId))
+			}
+			if queryStats != nil {
+				atomic.AddInt64(&queryStats.SkippedJobs, 1)
+			}
 			if stats != nil {
 				atomic.AddInt64(&stats.SkippedJobs, 1)
 			}
 			continue
 		}
 
-		if stats != nil {
-			atomic.AddInt64(&stats.TotalJobs, 1)
-		}
-		job := listing.toJob()
+		resolveJobLocation(&job)
 		select {
 		case jobsChan <- job:
 			utils.Debug(fmt.Sprintf("\tScraped job: %s", job.Title))
 		case <-ctx.Done():
+			s.mutex.Lock()
+			delete(s.processedIDs, key)
+			s.mutex.Unlock()
+			s.deferJobs(jobs[i:])
 			return ctx.Err()
 		}
+		if queryStats != nil {
+			atomic.AddInt64(&queryStats.NewJobs, 1)
+		}
+		if stats != nil {
+			atomic.AddInt64(&stats.TotalJobs, 1)
+		}
 	}
+	return nil
+}
 
-	return searchErr
+// deferJobs keeps unseen jobs for the next checkpoint.
+func (s *scraperClientImpl) deferJobs(jobs []models.Job) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, job := range jobs {
+		if job.JobId != "" && !s.processedIDs[s.cacheKey(&job)] {
+			s.pendingJobs = append(s.pendingJobs, job)
+		}
+	}
 }
 
-func (s *scraperClientImpl) searchJobs(ctx context.Context, query string) ([]workSourceJob, int, int, error) {
-	query = strings.TrimSpace(query)
-	response, err := callScraperApex[workSourceSearchResponse](ctx, s.httpClient, s.apexEndpoint, "initializeJobSearch", map[string]any{
-		"jobTitle":          query,
-		"location":          nil,
-		"companyId":         nil,
-		"industryId":        nil,
-		"currentLatitude":   nil,
-		"currentLongitude":  nil,
-		"additionalFilters": nil,
-	})
+func (s *scraperClientImpl) cacheKey(job *models.Job) string {
+	return JobCacheKey(s.config.JobWriteMode, job)
+}
+
+// unchangedPostings reports, by index, the jobs the store already holds with
+// the same fingerprint, looking up the ones the cache does not know in one
+// call. A failed lookup is logged and every job is sent.
+func (s *scraperClientImpl) unchangedPostings(ctx context.Context, jobs []models.Job) map[int]bool {
+	if s.postings == nil {
+		return nil
+	}
+	var lookup []models.Job
+	var indexes []int
+	s.mutex.Lock()
+	for i := range jobs {
+		if jobs[i].JobId != "" && !s.processedIDs[s.cacheKey(&jobs[i])] {
+			lookup = append(lookup, jobs[i])
+			indexes = append(indexes, i)
+		}
+	}
+	s.mutex.Unlock()
+	if len(lookup) == 0 {
+		return nil
+	}
+
+	hashes, err := s.postings.SourceHashes(ctx, lookup)
 	if err != nil {
-		return nil, 1, 0, err
+		log.Printf("Failed to look up stored postings; enriching all %d: %v", len(lookup), err)
+		return nil
+	}
+	unchanged := make(map[int]bool)
+	for i, hash := range hashes {
+		if hash != "" && hash == sourceHash(&lookup[i]) {
+			unchanged[indexes[i]] = true
+		}
+	}
+	return unchanged
+}
+
+// dropCovered returns the listings newer than since, the ones a run has not
+// sent before.
+func dropCovered(listings []models.Job, since models.ScrapeWatermark) []models.Job {
+	if since.IsZero() {
+		return listings
+	}
+	jobs := make([]models.Job, 0, len(listings))
+	for _, job := range listings {
+		if !since.Covers(job.PostedTime, job.JobId) {
+			jobs = append(jobs, job)
+		}
+	}
+	if covered := len(listings) - len(jobs); covered > 0 {
+		utils.Debug(fmt.Sprintf("Reached watermark %s; dropped %d already covered job(s)", since.LastPostingDate, covered))
+	}
+	return jobs
+}
+
+// searchSource returns every listing the search fetched and the watermark it
+// started from.
+func (s *scraperClientImpl) searchSource(ctx context.Context, source JobSource, query config.ScrapeQuery) ([]models.Job, models.ScrapeWatermark, error) {
+	key := ScrapeWatermarkKey(source.Name(), query)
+	s.mutex.Lock()
+	enabled := s.watermarks != nil
+	since := s.watermarks[key]
+	cursor := s.cursors[key]
+	delete(s.cursors, key)
+	s.mutex.Unlock()
+
+	var jobs []models.Job
+	var newest models.ScrapeWatermark
+	var err error
+	switch searcher := source.(type) {
+	case ResumableJobSource:
+		var next models.ScrapeCursor
+		if !cursor.IsZero() {
+			utils.Debug(fmt.Sprintf("Resuming %s from checkpoint cursor", key))
+		}
+		jobs, newest, next, err = searcher.SearchJobsFrom(ctx, query, since, cursor)
+		if !next.IsZero() {
+			s.mutex.Lock()
+			s.cursors[key] = next
+			s.mutex.Unlock()
+		}
+	case IncrementalJobSource:
+		if !enabled {
+			jobs, err = source.SearchJobs(ctx, query)
+			return jobs, since, err
+		}
+		jobs, newest, err = searcher.SearchJobsSince(ctx, query, since)
+	default:
+		jobs, err = source.SearchJobs(ctx, query)
+		return jobs, models.ScrapeWatermark{}, err
 	}
 
-	jobs := append([]workSourceJob(nil), response.Jobs...)
-	totalCount := response.TotalCount
-	batchSize := response.LoadMoreBatchSize
-	if batchSize < 1 {
-		batchSize = defaultSearchBatchSize
+	if enabled && !newest.IsZero() && newest != since {
+		s.mutex.Lock()
+		s.watermarks[key] = newest
+		s.mutex.Unlock()
+		utils.Debug(fmt.Sprintf("Advanced %s watermark to %s (%s)", key, newest.LastPostingDate, newest.LastRecordID))
 	}
-	filters := response.Filters
-	filterMap := response.FilterMap
-	geoWrapper := response.GeoWrapper
-	searchCalls := 1
-	maxPages := s.config.MaxPages
+	return jobs, since, err
+}
+
+func (w *workSourceJobSource) Name() string {
+	return WorkSourceSourceName
+}
+
+func (w *workSourceJobSource) SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error) {
+	jobs, _, err := w.SearchJobsSince(ctx, query, models.ScrapeWatermark{})
+	return jobs, err
+}
+
+// SearchJobsSince pages newest-first and stops at the first page that reaches
+// postings covered by since. The watermark only advances on a clean search so
+// a failed page is retried in full on the next run.
+func (w *workSourceJobSource) SearchJobsSince(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark) ([]models.Job, models.ScrapeWatermark, error) {
+	jobs, newest, _, err := w.SearchJobsFrom(ctx, query, since, models.ScrapeCursor{})
+	return jobs, newest, err
+}
+
+// SearchJobsFrom continues with loadMoreJobs from a cursor an earlier run
+// returned, counting its pages against MaxPages. The listings since covers
+// are returned too; the scraper drops them before sending. A cursor is returned when ctx
+// ends after the first page, or when MaxPages runs out before the search
+// reaches since; any other failure should restart the query. Either way the
+// watermark stays at since until a later run pages down to it, so the
+// postings between the last page fetched and since are not skipped.
+func (w *workSourceJobSource) SearchJobsFrom(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark, resume models.ScrapeCursor) ([]models.Job, models.ScrapeWatermark, models.ScrapeCursor, error) {
+	newest := since
+	var cursor workSourceCursor
+	if !resume.IsZero() {
+		if err := json.Unmarshal(resume.State, &cursor); err != nil {
+			log.Printf("Ignoring unreadable WorkSourceWA cursor: %v", err)
+			cursor = workSourceCursor{}
+		} else if resume.Newest.LastPostingDate > newest.LastPostingDate {
+			newest = resume.Newest
+		}
+	}
+
+	listings, cursor, more, searchErr := w.searchJobs(ctx, query, since, cursor)
+	utils.Debug(fmt.Sprintf("WorkSourceWA returned %d of %d jobs in %d search call(s)", len(listings), cursor.TotalCount, cursor.SearchCalls))
+
+	jobs := make([]models.Job, 0, len(listings))
+	reached := false
+	for _, listing := range listings {
+		if listing.RecordID == "" {
+			log.Printf("Skipping WorkSourceWA job without a recordId")
+			continue
+		}
+		if since.Covers(listing.PostingDate, listing.RecordID) {
+			reached = true
+		} else if listing.PostingDate > newest.LastPostingDate {
+			newest = models.ScrapeWatermark{LastPostingDate: listing.PostingDate, LastRecordID: listing.RecordID}
+		}
+		jobs = append(jobs, listing.toJob())
+	}
+
+	if searchErr != nil {
+		var next models.ScrapeCursor
+		if ctx.Err() != nil && cursor.SearchCalls > 0 {
+			next = carryCursor(cursor, newest)
+		}
+		return jobs, since, next, searchErr
+	}
+	// without a watermark MaxPages is simply how deep the first run looks
+	if more && !since.IsZero() && !reached {
+		utils.Debug(fmt.Sprintf("WorkSourceWA stopped at %d page(s) before reaching watermark %s; continuing next run", cursor.SearchCalls, since.LastPostingDate))
+		cursor.Pages = 0
+		return jobs, since, carryCursor(cursor, newest), nil
+	}
+	if newest != since {
+		newest.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	}
+	return jobs, newest, models.ScrapeCursor{}, nil
+}
+
+// carryCursor wraps cursor for a later run, along with the newest posting
+// seen before it.
+func carryCursor(cursor workSourceCursor, newest models.ScrapeWatermark) models.ScrapeCursor {
+	state, err := json.Marshal(cursor)
+	if err != nil {
+		return models.ScrapeCursor{}
+	}
+	return models.ScrapeCursor{Newest: newest, State: state}
+}
+
+// searchJobs starts with initializeJobSearch unless cursor already holds the
+// state of an earlier search, then pages with loadMoreJobs until a page
+// reaches since, or past it up to MaxPages when posting history is on. The
+// returned cursor points past the last page fetched; the bool reports that
+// MaxPages stopped the search before it ran out of results.
+func (w *workSourceJobSource) searchJobs(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark, cursor workSourceCursor) ([]workSourceJob, workSourceCursor, bool, error) {
+	maxPages := w.config.MaxPages
 	if maxPages < 1 {
 		maxPages = 1
 	}
+	// posting history needs the listings behind the watermark too, to tell a
+	// closed posting from one a short search never reached
+	if w.config.UsePostingHistory {
+		since = models.ScrapeWatermark{}
+	}
 
-	for searchCalls < maxPages && len(jobs) < totalCount {
-		if len(response.Jobs) == 0 || response.LastRecordID == "" || response.LastPostingDate == "" {
-			break
+	var jobs []workSourceJob
+	if cursor.SearchCalls == 0 {
+		response, err := callScraperApex[workSourceSearchResponse](ctx, w.httpClient, w.apexEndpoint, "initializeJobSearch", workSourceSearchParams(query))
+		if err != nil {
+			return nil, cursor, false, err
+		}
+		batchSize := response.LoadMoreBatchSize
+		if batchSize < 1 {
+			batchSize = defaultSearchBatchSize
+		}
+		cursor = workSourceCursor{
+			Filters:     response.Filters,
+			FilterMap:   response.FilterMap,
+			GeoWrapper:  response.GeoWrapper,
+			BatchSize:   batchSize,
+			TotalCount:  response.TotalCount,
+			SearchCalls: 1,
+			Pages:       1,
+		}
+		jobs = append(jobs, response.Jobs...)
+		if !cursor.advance(response, since) {
+			return jobs, cursor, false, nil
 		}
-		previousRecordID := response.LastRecordID
-		previousPostingDate := response.LastPostingDate
+	}
 
-		response, err = callScraperApex[workSourceSearchResponse](ctx, s.httpClient, s.apexEndpoint, "loadMoreJobs", map[string]any{
-			"filters":         filters,
-			"filterMap":       filterMap,
-			"limitSize":       batchSize,
-			"lastRecordId":    response.LastRecordID,
-			"lastPostingDate": response.LastPostingDate,
-			"geoWrapper":      geoWrapper,
+	for cursor.Fetched < cursor.TotalCount {
+		if cursor.Pages >= maxPages {
+			return jobs, cursor, true, nil
+		}
+		response, err := callScraperApex[workSourceSearchResponse](ctx, w.httpClient, w.apexEndpoint, "loadMoreJobs", map[string]any{
+			"filters":         cursor.Filters,
+			"filterMap":       cursor.FilterMap,
+			"limitSize":       cursor.BatchSize,
+			"lastRecordId":    cursor.LastRecordID,
+			"lastPostingDate": cursor.LastPostingDate,
+			"geoWrapper":      cursor.GeoWrapper,
 		})
-		searchCalls++
 		if err != nil {
-			return jobs, searchCalls, totalCount, err
+			return jobs, cursor, false, err
 		}
+		cursor.SearchCalls++
+		cursor.Pages++
 		jobs = append(jobs, response.Jobs...)
-		if len(response.Jobs) == 0 || response.LastRecordID == previousRecordID && response.LastPostingDate == previousPostingDate {
+		if !cursor.advance(response, since) {
 			break
 		}
 	}
 
-	return jobs, searchCalls, totalCount, nil
+	return jobs, cursor, false, nil
+}
+
+// advance moves the cursor past a fetched page and reports whether another
+// page should be requested.
+func (c *workSourceCursor) advance(response workSourceSearchResponse, since models.ScrapeWatermark) bool {
+	c.Fetched += len(response.Jobs)
+	if len(response.Jobs) == 0 || response.LastRecordID == "" || response.LastPostingDate == "" {
+		return false
+	}
+	if response.LastRecordID == c.LastRecordID && response.LastPostingDate == c.LastPostingDate {
+		return false
+	}
+	c.LastRecordID = response.LastRecordID
+	c.LastPostingDate = response.LastPostingDate
+	return !reachesWatermark(response.Jobs, since)
+}
+
+// workSourceSearchParams maps a plan query onto initializeJobSearch, sending
+// nil for every filter the query leaves unset.
+func workSourceSearchParams(query config.ScrapeQuery) map[string]any {
+	params := map[string]any{
+		"jobTitle":          strings.TrimSpace(query.Query),
+		"location":          nil,
+		"companyId":         nil,
+		"industryId":        nil,
+		"currentLatitude":   nil,
+		"currentLongitude":  nil,
+		"additionalFilters": nil,
+	}
+	if location := strings.TrimSpace(query.Location); location != "" {
+		params["location"] = location
+	}
+	if company := strings.TrimSpace(query.CompanyID); company != "" {
+		params["companyId"] = company
+	}
+	if industry := strings.TrimSpace(query.IndustryID); industry != "" {
+		params["industryId"] = industry
+	}
+	if query.Latitude != nil && query.Longitude != nil {
+		params["currentLatitude"] = *query.Latitude
+		params["currentLongitude"] = *query.Longitude
+	}
+
+	if len(query.AdditionalFilters) > 0 || query.RadiusMiles > 0 {
+		filters := make(map[string]any, len(query.AdditionalFilters)+1)
+		for name, value := range query.AdditionalFilters {
+			filters[name] = value
+		}
+		if query.RadiusMiles > 0 {
+			filters["radius"] = query.RadiusMiles
+		}
+		params["additionalFilters"] = filters
+	}
+	return params
+}
+
+func reachesWatermark(listings []workSourceJob, since models.ScrapeWatermark) bool {
+	for _, listing := range listings {
+		if since.Covers(listing.PostingDate, listing.RecordID) {
+			return true
+		}
+	}
+	return false
 }
 
 func callScraperApex[T any](ctx context.Context, client *http.Client, endpoint, method string, params any) (T, error) {
@@ -261,8 +706,9 @@ func (job workSourceJob) toJob() models.Job {
 		description = "No description available"
 	}
 
-	return models.Job{
+	result := models.Job{
 		JobId:       job.RecordID,
+		Source:      WorkSourceSourceName,
 		Title:       title,
 		Company:     company,
 		Location:    location,
@@ -274,6 +720,8 @@ func (job workSourceJob) toJob() models.Job {
 		URL:         "https://worksource.my.site.com/worksourcewa/job-search/job-details?jobId=" + url.QueryEscape(job.RecordID),
 		Description: description,
 	}
+	applyListingPay(&result, job.Amount, job.PayType)
+	return result
 }
 
 func (s *scraperClientImpl) GetProcessedIDs() map[string]bool {
@@ -287,6 +735,90 @@ func (s *scraperClientImpl) GetProcessedIDs() map[string]bool {
 	return result
 }
 
+// GetQueryStats returns per-query counts for every query ScrapeJobs has run.
+func (s *scraperClientImpl) GetQueryStats() []models.QueryStats {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]models.QueryStats(nil), s.queryStats...)
+}
+
+func (s *scraperClientImpl) GetSearchCoverage() []models.SearchCoverage {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]models.SearchCoverage(nil), s.coverage...)
+}
+
+// SetWatermarks enables incremental searches starting from the given
+// watermarks; a nil map starts every query from scratch.
+func (s *scraperClientImpl) SetWatermarks(watermarks map[string]models.ScrapeWatermark) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.watermarks = make(map[string]models.ScrapeWatermark, len(watermarks))
+	for key, watermark := range watermarks {
+		s.watermarks[key] = watermark
+	}
+}
+
+// SkipUnchangedPostings looks each search's new listings up in store before
+// sending them and skips the ones stored with the same SourceHash, so upserts
+// only pay to enrich postings that changed.
+func (s *scraperClientImpl) SkipUnchangedPostings(store JobStore) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.postings = store
+}
+
+func (s *scraperClientImpl) GetWatermarks() map[string]models.ScrapeWatermark {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	result := make(map[string]models.ScrapeWatermark, len(s.watermarks))
+	for key, watermark := range s.watermarks {
+		result[key] = watermark
+	}
+	return result
+}
+
+// ResumeFrom restores the state of an interrupted run. Call it before
+// ScrapeJobs.
+func (s *scraperClientImpl) ResumeFrom(checkpoint models.ScrapeCheckpoint) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, key := range checkpoint.CompletedQueries {
+		s.completedQueries[key] = true
+	}
+	for key, cursor := range checkpoint.Cursors {
+		s.cursors[key] = cursor
+	}
+	s.pendingJobs = append(s.pendingJobs, checkpoint.PendingJobs...)
+}
+
+// Checkpoint captures where ScrapeJobs stopped: the queries it finished, the
+// cursors of interrupted searches, and the listings it never sent.
+func (s *scraperClientImpl) Checkpoint() models.ScrapeCheckpoint {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var checkpoint models.ScrapeCheckpoint
+	for key := range s.completedQueries {
+		checkpoint.CompletedQueries = append(checkpoint.CompletedQueries, key)
+	}
+	sort.Strings(checkpoint.CompletedQueries)
+	if len(s.cursors) > 0 {
+		checkpoint.Cursors = make(map[string]models.ScrapeCursor, len(s.cursors))
+		for key, cursor := range s.cursors {
+			checkpoint.Cursors[key] = cursor
+		}
+	}
+	checkpoint.PendingJobs = append(checkpoint.PendingJobs, s.pendingJobs...)
+	return checkpoint
+}
+
 func postingDateOnly(value string) string {
 	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
 		return parsed.Format(time.DateOnly)
@@ -3,11 +3,13 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"gopher-source/config"
@@ -85,10 +87,7 @@ func TestScrapeJobsPaginatesEmitsUniqueJobsAndUpdatesStats(t *testing.T) {
 	defer server.Close()
 
 	cfg := config.Config{MaxPages: 2}
-	scraper := NewScraper(cfg, false)
-	impl := scraper.(*scraperClientImpl)
-	impl.httpClient = server.Client()
-	impl.apexEndpoint = server.URL
+	scraper := newTestWorkSourceScraper(cfg, server)
 
 	stats := &models.JobStats{}
 	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", stats)
@@ -123,9 +122,18 @@ func TestScrapeJobsPaginatesEmitsUniqueJobsAndUpdatesStats(t *testing.T) {
 	if first.ExpiresDate != "2026-09-18" || first.Salary != "$150,000 - $180,000/year" || first.Modality != "Hybrid" {
 		t.Fatalf("unexpected normalized fields: %+v", first)
 	}
+	if first.SalaryAnnualMin == nil || *first.SalaryAnnualMin != 150000 || *first.SalaryAnnualMax != 180000 || first.SalarySource != SalarySourceListing {
+		t.Fatalf("expected structured listing salary, got %+v", first)
+	}
+	if first.City != "Seattle" || first.State != "WA" || first.MetroArea != "Seattle-Tacoma-Bellevue, WA" || first.Latitude == nil {
+		t.Fatalf("expected a resolved location, got %+v", first)
+	}
 	if first.URL != "https://worksource.my.site.com/worksourcewa/job-search/job-details?jobId=record-123" {
 		t.Fatalf("unexpected listing URL: %q", first.URL)
 	}
+	if first.Source != WorkSourceSourceName {
+		t.Fatalf("expected source %q, got %q", WorkSourceSourceName, first.Source)
+	}
 
 	second, ok := jobsByID["record-456"]
 	if !ok {
@@ -150,10 +158,7 @@ func TestScrapeJobsReturnsInitialApexHTTPError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	scraper := NewScraper(config.Config{MaxPages: 1}, false)
-	impl := scraper.(*scraperClientImpl)
-	impl.httpClient = server.Client()
-	impl.apexEndpoint = server.URL
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 1}, server)
 
 	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
 	if err == nil || !strings.Contains(err.Error(), "initializeJobSearch returned HTTP 503") {
@@ -187,10 +192,7 @@ func TestScrapeJobsEmitsEarlierJobsAndReturnsLaterApexHTTPError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	scraper := NewScraper(config.Config{MaxPages: 2}, false)
-	impl := scraper.(*scraperClientImpl)
-	impl.httpClient = server.Client()
-	impl.apexEndpoint = server.URL
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 2}, server)
 
 	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
 	if err == nil || !strings.Contains(err.Error(), "loadMoreJobs returned HTTP 502") {
@@ -207,10 +209,7 @@ func TestScrapeJobsReturnsContextCancellation(t *testing.T) {
 	}))
 	defer server.Close()
 
-	scraper := NewScraper(config.Config{MaxPages: 1}, false)
-	impl := scraper.(*scraperClientImpl)
-	impl.httpClient = server.Client()
-	impl.apexEndpoint = server.URL
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 1}, server)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -223,11 +222,558 @@ func TestScrapeJobsReturnsContextCancellation(t *testing.T) {
 	}
 }
 
+func TestScrapeJobsStopsPagingAtWatermark(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request scraperApexRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		methods = append(methods, request.Method)
+		writeSearchResponse(t, w, workSourceSearchResponse{
+			Jobs: []workSourceJob{
+				{RecordID: "record-new", JobTitle: "New", PostingDate: "2026-08-19T08:00:00.000Z"},
+				{RecordID: "record-tie", JobTitle: "Same second", PostingDate: "2026-08-18T01:40:56.000Z"},
+				{RecordID: "record-123", JobTitle: "Covered", PostingDate: "2026-08-18T01:40:56.000Z"},
+				{RecordID: "record-old", JobTitle: "Older", PostingDate: "2026-08-17T00:00:00.000Z"},
+			},
+			TotalCount:        500,
+			LoadMoreBatchSize: 100,
+			LastRecordID:      "record-old",
+			LastPostingDate:   "2026-08-17T00:00:00.000Z",
+		})
+	}))
+	defer server.Close()
+
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 5}, server)
+	key := ScrapeWatermarkKey(WorkSourceSourceName, config.ScrapeQuery{Query: " Backend  Engineer "})
+	scraper.SetWatermarks(map[string]models.ScrapeWatermark{
+		key: {LastPostingDate: "2026-08-18T01:40:56.000Z", LastRecordID: "record-123"},
+	})
+
+	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if fmt.Sprint(methods) != "[initializeJobSearch]" {
+		t.Fatalf("expected paging to stop at the watermark, got %v", methods)
+	}
+	if len(jobs) != 2 || jobs[0].JobId != "record-new" || jobs[1].JobId != "record-tie" {
+		t.Fatalf("expected only uncovered jobs, got %+v", jobs)
+	}
+
+	watermark := scraper.GetWatermarks()[key]
+	if watermark.LastRecordID != "record-new" || watermark.LastPostingDate != "2026-08-19T08:00:00.000Z" || watermark.UpdatedAt == "" {
+		t.Fatalf("expected watermark to advance to newest job, got %+v", watermark)
+	}
+}
+
+func TestScrapeJobsPagesPastWatermarkForPostingHistory(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request scraperApexRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		methods = append(methods, request.Method)
+		page := workSourceSearchResponse{
+			Jobs: []workSourceJob{
+				{RecordID: "record-new", JobTitle: "New", PostingDate: "2026-08-19T08:00:00.000Z"},
+				{RecordID: "record-123", JobTitle: "Covered", PostingDate: "2026-08-18T01:40:56.000Z"},
+			},
+			TotalCount:        500,
+			LoadMoreBatchSize: 100,
+			LastRecordID:      "record-123",
+			LastPostingDate:   "2026-08-18T01:40:56.000Z",
+		}
+		if request.Method == "loadMoreJobs" {
+			page.Jobs = []workSourceJob{{RecordID: "record-old", JobTitle: "Older", PostingDate: "2026-08-17T00:00:00.000Z"}}
+			page.LastRecordID, page.LastPostingDate = "record-old", "2026-08-17T00:00:00.000Z"
+		}
+		writeSearchResponse(t, w, page)
+	}))
+	defer server.Close()
+
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 2, UsePostingHistory: true}, server)
+	key := ScrapeWatermarkKey(WorkSourceSourceName, config.ScrapeQuery{Query: "backend engineer"})
+	scraper.SetWatermarks(map[string]models.ScrapeWatermark{
+		key: {LastPostingDate: "2026-08-18T01:40:56.000Z", LastRecordID: "record-123"},
+	})
+
+	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if fmt.Sprint(methods) != "[initializeJobSearch loadMoreJobs]" {
+		t.Fatalf("expected paging past the watermark up to MaxPages, got %v", methods)
+	}
+	if len(jobs) != 1 || jobs[0].JobId != "record-new" {
+		t.Fatalf("expected only the uncovered job to be sent, got %+v", jobs)
+	}
+	coverage := scraper.GetSearchCoverage()
+	if len(coverage) != 1 || len(coverage[0].Jobs) != 3 {
+		t.Fatalf("expected coverage of every listing fetched, got %+v", coverage)
+	}
+	if checkpoint := scraper.Checkpoint(); len(checkpoint.Cursors) != 0 {
+		t.Fatalf("expected no cursor once the search reached the watermark, got %+v", checkpoint)
+	}
+	if watermark := scraper.GetWatermarks()[key]; watermark.LastRecordID != "record-new" {
+		t.Fatalf("expected the watermark to advance, got %+v", watermark)
+	}
+}
+
+func TestScrapeJobsKeepsWatermarkWhenPagingFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request scraperApexRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if request.Method != "initializeJobSearch" {
+			http.Error(w, "upstream failure", http.StatusBadGateway)
+			return
+		}
+		writeSearchResponse(t, w, workSourceSearchResponse{
+			Jobs:              []workSourceJob{{RecordID: "record-new", PostingDate: "2026-08-19T08:00:00.000Z"}},
+			TotalCount:        2,
+			LoadMoreBatchSize: 100,
+			LastRecordID:      "record-new",
+			LastPostingDate:   "2026-08-19T08:00:00.000Z",
+		})
+	}))
+	defer server.Close()
+
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 2}, server)
+	key := ScrapeWatermarkKey(WorkSourceSourceName, config.ScrapeQuery{Query: "backend engineer"})
+	previous := models.ScrapeWatermark{LastPostingDate: "2026-08-01T00:00:00.000Z", LastRecordID: "record-1"}
+	scraper.SetWatermarks(map[string]models.ScrapeWatermark{key: previous})
+
+	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err == nil || !strings.Contains(err.Error(), "loadMoreJobs returned HTTP 502") {
+		t.Fatalf("expected later page error, got %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("expected partial results to be emitted, got %+v", jobs)
+	}
+	if got := scraper.GetWatermarks()[key]; got != previous {
+		t.Fatalf("expected watermark to stay at %+v after a failed search, got %+v", previous, got)
+	}
+}
+
+func TestScrapeJobsCarriesCursorWhenMaxPagesStopsShortOfWatermark(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		methods = append(methods, request.Method)
+		switch {
+		case request.Method == "initializeJobSearch":
+			writeSearchResponse(t, w, workSourceSearchResponse{
+				Jobs:              []workSourceJob{{RecordID: "record-1", PostingDate: "2026-08-19T08:00:00.000Z"}},
+				TotalCount:        3,
+				LoadMoreBatchSize: 1,
+				LastRecordID:      "record-1",
+				LastPostingDate:   "2026-08-19T08:00:00.000Z",
+			})
+		case request.Params["lastRecordId"] == "record-1":
+			writeSearchResponse(t, w, workSourceSearchResponse{
+				Jobs:            []workSourceJob{{RecordID: "record-2", PostingDate: "2026-08-18T08:00:00.000Z"}},
+				TotalCount:      3,
+				LastRecordID:    "record-2",
+				LastPostingDate: "2026-08-18T08:00:00.000Z",
+			})
+		default:
+			writeSearchResponse(t, w, workSourceSearchResponse{
+				Jobs:            []workSourceJob{{RecordID: "record-old", PostingDate: "2026-08-01T00:00:00.000Z"}},
+				TotalCount:      3,
+				LastRecordID:    "record-old",
+				LastPostingDate: "2026-08-01T00:00:00.000Z",
+			})
+		}
+	}))
+	defer server.Close()
+
+	key := ScrapeWatermarkKey(WorkSourceSourceName, config.ScrapeQuery{Query: "backend engineer"})
+	previous := models.ScrapeWatermark{LastPostingDate: "2026-08-01T00:00:00.000Z", LastRecordID: "record-old"}
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 1}, server)
+	scraper.SetWatermarks(map[string]models.ScrapeWatermark{key: previous})
+	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].JobId != "record-1" {
+		t.Fatalf("expected only the first page, got %+v", jobs)
+	}
+	if got := scraper.GetWatermarks()[key]; got != previous {
+		t.Fatalf("expected the watermark to stay at %+v until the gap is paged, got %+v", previous, got)
+	}
+	checkpoint := scraper.Checkpoint()
+	if checkpoint.Cursors[key].IsZero() || checkpoint.Cursors[key].Newest.LastRecordID != "record-1" {
+		t.Fatalf("expected a cursor carrying the newest posting, got %+v", checkpoint)
+	}
+
+	methods = nil
+	scraper = newTestWorkSourceScraper(config.Config{MaxPages: 2}, server)
+	scraper.SetWatermarks(map[string]models.ScrapeWatermark{key: previous})
+	scraper.ResumeFrom(models.ScrapeCheckpoint{Cursors: checkpoint.Cursors})
+	jobs, err = collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err != nil {
+		t.Fatalf("resumed ScrapeJobs returned error: %v", err)
+	}
+	if fmt.Sprint(methods) != "[loadMoreJobs loadMoreJobs]" {
+		t.Fatalf("expected the next run to page on from the cursor with its own page budget, got %v", methods)
+	}
+	if len(jobs) != 1 || jobs[0].JobId != "record-2" {
+		t.Fatalf("expected the posting between the pages and the watermark, got %+v", jobs)
+	}
+	if got := scraper.GetWatermarks()[key]; got.LastRecordID != "record-1" {
+		t.Fatalf("expected the watermark to advance once the gap closed, got %+v", got)
+	}
+	if cursors := scraper.Checkpoint().Cursors; len(cursors) != 0 {
+		t.Fatalf("expected no cursor after reaching the watermark, got %+v", cursors)
+	}
+}
+
+func TestScrapeJobsCheckpointsAndResumesAtCursor(t *testing.T) {
+	var resumed atomic.Bool
+	var methods []string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		methods = append(methods, request.Method)
+		switch {
+		case request.Method == "initializeJobSearch":
+			writeSearchResponse(t, w, workSourceSearchResponse{
+				Jobs: []workSourceJob{
+					{RecordID: "record-1", JobTitle: "One", PostingDate: "2026-08-19T08:00:00.000Z"},
+					{RecordID: "record-2", JobTitle: "Two", PostingDate: "2026-08-18T08:00:00.000Z"},
+				},
+				TotalCount:        4,
+				LoadMoreBatchSize: 2,
+				LastRecordID:      "record-2",
+				LastPostingDate:   "2026-08-18T08:00:00.000Z",
+			})
+		case !resumed.Load():
+			// the deadline passes while the second page is in flight
+			cancel()
+			<-r.Context().Done()
+		default:
+			if request.Params["lastRecordId"] != "record-2" || request.Params["limitSize"] != float64(2) {
+				t.Errorf("expected resume from the checkpoint cursor, got %+v", request.Params)
+			}
+			writeSearchResponse(t, w, workSourceSearchResponse{
+				Jobs: []workSourceJob{
+					{RecordID: "record-3", JobTitle: "Three", PostingDate: "2026-08-17T08:00:00.000Z"},
+					{RecordID: "record-4", JobTitle: "Four", PostingDate: "2026-08-16T08:00:00.000Z"},
+				},
+				TotalCount:      4,
+				LastRecordID:    "record-4",
+				LastPostingDate: "2026-08-16T08:00:00.000Z",
+			})
+		}
+	}))
+	defer server.Close()
+
+	cfg := config.Config{MaxPages: 5}
+	scraper := newTestWorkSourceScraper(cfg, server)
+	scraper.SetWatermarks(nil)
+	jobs, err := collectScrapedJobs(ctx, scraper, "backend engineer", &models.JobStats{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context cancellation, got %v", err)
+	}
+	if len(jobs) != 0 || len(scraper.GetProcessedIDs()) != 0 {
+		t.Fatalf("expected unsent jobs to stay unprocessed, got jobs %+v", jobs)
+	}
+
+	checkpoint := scraper.Checkpoint()
+	key := ScrapeWatermarkKey(WorkSourceSourceName, config.ScrapeQuery{Query: "backend engineer"})
+	if len(checkpoint.CompletedQueries) != 0 || len(checkpoint.PendingJobs) != 2 || checkpoint.Cursors[key].IsZero() {
+		t.Fatalf("expected pending jobs and a cursor in the checkpoint, got %+v", checkpoint)
+	}
+	if checkpoint.Cursors[key].Newest.LastRecordID != "record-1" {
+		t.Fatalf("expected cursor to carry the newest posting seen, got %+v", checkpoint.Cursors[key].Newest)
+	}
+
+	resumed.Store(true)
+	methods = nil
+	scraper = newTestWorkSourceScraper(cfg, server)
+	scraper.SetWatermarks(nil)
+	scraper.ResumeFrom(checkpoint)
+	jobs, err = collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if err != nil {
+		t.Fatalf("resumed ScrapeJobs returned error: %v", err)
+	}
+	if fmt.Sprint(methods) != "[loadMoreJobs]" {
+		t.Fatalf("expected the resumed search to skip initializeJobSearch, got %v", methods)
+	}
+	var ids []string
+	for _, job := range jobs {
+		ids = append(ids, job.JobId)
+	}
+	if fmt.Sprint(ids) != "[record-1 record-2 record-3 record-4]" {
+		t.Fatalf("expected pending jobs before the remaining pages, got %v", ids)
+	}
+	if watermark := scraper.GetWatermarks()[key]; watermark.LastRecordID != "record-1" {
+		t.Fatalf("expected watermark from the interrupted run's first page, got %+v", watermark)
+	}
+
+	checkpoint = scraper.Checkpoint()
+	if len(checkpoint.Cursors) != 0 || len(checkpoint.PendingJobs) != 0 || fmt.Sprint(checkpoint.CompletedQueries) != "[backend engineer]" {
+		t.Fatalf("expected a finished checkpoint, got %+v", checkpoint)
+	}
+}
+
+func TestScrapeJobsSkipsQueriesCompletedBeforeCheckpoint(t *testing.T) {
+	source := &fakeJobSource{name: "fake", jobs: []models.Job{{JobId: "fake-1"}}}
+	scraper := NewScraperWithSources(config.Config{}, false, nil, []JobSource{source})
+	scraper.ResumeFrom(models.ScrapeCheckpoint{
+		CompletedQueries: []string{"software engineer"},
+		PendingJobs:      []models.Job{{JobId: "pending-1"}, {JobId: "fake-1"}},
+	})
+
+	plan := []config.ScrapeQuery{{Query: "Software Engineer"}, {Query: "sre"}}
+	stats := &models.JobStats{}
+	jobs, err := collectScrapedPlan(context.Background(), scraper, plan, stats)
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if len(source.queries) != 1 || source.queries[0].Query != "sre" {
+		t.Fatalf("expected only the unfinished query to run, got %+v", source.queries)
+	}
+	if len(jobs) != 2 || jobs[0].JobId != "pending-1" || jobs[1].JobId != "fake-1" {
+		t.Fatalf("expected pending jobs once each, got %+v", jobs)
+	}
+	if snapshot := stats.Snapshot(); snapshot.TotalJobs != 2 || snapshot.SkippedJobs != 1 {
+		t.Fatalf("unexpected stats: %+v", snapshot)
+	}
+}
+
+func TestScrapeJobsRunsPlanWithFiltersAndDedupesAcrossQueries(t *testing.T) {
+	var paramsByQuery = map[string]map[string]any{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		title, _ := request.Params["jobTitle"].(string)
+		paramsByQuery[title] = request.Params
+
+		jobs := []workSourceJob{{RecordID: "shared", JobTitle: "Platform Engineer"}}
+		if title == "site reliability" {
+			jobs = append(jobs, workSourceJob{RecordID: "sre-only", JobTitle: "SRE"})
+		}
+		writeSearchResponse(t, w, workSourceSearchResponse{Jobs: jobs, TotalCount: len(jobs)})
+	}))
+	defer server.Close()
+
+	latitude, longitude := 47.6, -122.3
+	plan := []config.ScrapeQuery{
+		{Query: "software engineer"},
+		{Name: "SRE Seattle", Query: "site reliability", Location: "Seattle, WA", Latitude: &latitude, Longitude: &longitude,
+			RadiusMiles: 25, IndustryID: "54", CompanyID: "001A", AdditionalFilters: map[string]any{"jobType": "Full Time"}},
+	}
+	scraper := newTestWorkSourceScraper(config.Config{MaxPages: 1}, server)
+	stats := &models.JobStats{}
+	jobs, err := collectScrapedPlan(context.Background(), scraper, plan, stats)
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("expected shared job once plus the SRE job, got %+v", jobs)
+	}
+
+	plain := paramsByQuery["software engineer"]
+	if plain == nil || plain["location"] != nil || plain["additionalFilters"] != nil || plain["currentLatitude"] != nil {
+		t.Fatalf("expected unset filters to be sent as null, got %+v", plain)
+	}
+	filtered := paramsByQuery["site reliability"]
+	if filtered["location"] != "Seattle, WA" || filtered["industryId"] != "54" || filtered["companyId"] != "001A" {
+		t.Fatalf("expected filters to pass through, got %+v", filtered)
+	}
+	if filtered["currentLatitude"] != 47.6 || filtered["currentLongitude"] != -122.3 {
+		t.Fatalf("expected coordinates to pass through, got %+v", filtered)
+	}
+	additional, _ := filtered["additionalFilters"].(map[string]any)
+	if additional["radius"] != float64(25) || additional["jobType"] != "Full Time" {
+		t.Fatalf("expected radius merged into additional filters, got %+v", filtered["additionalFilters"])
+	}
+
+	queryStats := scraper.GetQueryStats()
+	if len(queryStats) != 2 {
+		t.Fatalf("expected stats for both queries, got %+v", queryStats)
+	}
+	if queryStats[0] != (models.QueryStats{Query: "software engineer", Listings: 1, NewJobs: 1}) {
+		t.Fatalf("unexpected first query stats: %+v", queryStats[0])
+	}
+	if queryStats[1] != (models.QueryStats{Query: "SRE Seattle", Listings: 2, NewJobs: 1, SkippedJobs: 1}) {
+		t.Fatalf("unexpected second query stats: %+v", queryStats[1])
+	}
+	if snapshot := stats.Snapshot(); snapshot.TotalJobs != 2 || snapshot.SkippedJobs != 1 {
+		t.Fatalf("unexpected run stats: %+v", snapshot)
+	}
+}
+
+func TestScrapeJobsMergesSourcesAndKeepsPartialResults(t *testing.T) {
+	sources := []JobSource{
+		&fakeJobSource{name: "alpha", jobs: []models.Job{{JobId: "a-1", Title: "One"}, {JobId: "shared", Title: "Shared"}}},
+		&fakeJobSource{name: "beta", jobs: []models.Job{{JobId: "shared", Title: "Shared again"}, {JobId: "b-1", Title: "Two", Source: "beta-custom"}}},
+		&fakeJobSource{name: "broken", err: errors.New("board offline")},
+	}
+	scraper := NewScraperWithSources(config.Config{}, false, nil, sources)
+
+	stats := &models.JobStats{}
+	jobs, err := collectScrapedJobs(context.Background(), scraper, "backend engineer", stats)
+	if err == nil || !strings.Contains(err.Error(), "broken: board offline") {
+		t.Fatalf("expected failing source error, got %v", err)
+	}
+
+	sourcesByID := map[string]string{}
+	for _, job := range jobs {
+		sourcesByID[job.JobId] = job.Source
+	}
+	if len(jobs) != 3 || len(sourcesByID) != 3 {
+		t.Fatalf("expected 3 unique jobs across sources, got %+v", jobs)
+	}
+	if sourcesByID["a-1"] != "alpha" || sourcesByID["b-1"] != "beta-custom" {
+		t.Fatalf("unexpected source attribution: %+v", sourcesByID)
+	}
+	if sourcesByID["shared"] != "alpha" && sourcesByID["shared"] != "beta" {
+		t.Fatalf("expected shared job to keep its emitting source, got %q", sourcesByID["shared"])
+	}
+
+	snapshot := stats.Snapshot()
+	if snapshot.TotalJobs != 3 || snapshot.SkippedJobs != 1 {
+		t.Fatalf("unexpected stats: %+v", snapshot)
+	}
+	if processed := scraper.GetProcessedIDs(); len(processed) != 3 {
+		t.Fatalf("expected 3 processed IDs, got %+v", processed)
+	}
+}
+
+func TestScrapeJobsCollectsSearchCoverageForPostingHistory(t *testing.T) {
+	sources := []JobSource{
+		&fakeJobSource{name: "alpha", jobs: []models.Job{{JobId: "known", Title: "Known"}, {JobId: "a-1", Title: "New"}}},
+		&fakeJobSource{name: "broken", jobs: []models.Job{{JobId: "b-1"}}, err: errors.New("board offline")},
+	}
+	scraper := NewScraperWithSources(config.Config{UsePostingHistory: true}, false, map[string]bool{"known": true}, sources)
+
+	jobs, _ := collectScrapedJobs(context.Background(), scraper, "backend engineer", &models.JobStats{})
+	if len(jobs) != 2 {
+		t.Fatalf("expected the new alpha job and the partial broken one, got %+v", jobs)
+	}
+
+	coverage := scraper.GetSearchCoverage()
+	if len(coverage) != 1 || coverage[0].Source != "alpha" || coverage[0].Query != "backend engineer" {
+		t.Fatalf("expected coverage for the clean alpha search only, got %+v", coverage)
+	}
+	if ids := []string{coverage[0].Jobs[0].JobId, coverage[0].Jobs[1].JobId}; ids[0] != "known" || ids[1] != "a-1" {
+		t.Fatalf("expected coverage to include the already processed listing, got %v", ids)
+	}
+
+	untracked := NewScraperWithSources(config.Config{}, false, nil, sources[:1])
+	if _, err := collectScrapedJobs(context.Background(), untracked, "backend engineer", nil); err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if coverage := untracked.GetSearchCoverage(); len(coverage) != 0 {
+		t.Fatalf("expected no coverage with posting history off, got %+v", coverage)
+	}
+}
+
+func TestScrapeJobsSkipsPostingsStoredUnchangedInUpsertMode(t *testing.T) {
+	ctx := context.Background()
+	unchanged := models.Job{JobId: "same", Title: "Go Developer", Salary: "$120,000", PostedDate: "2026-10-15"}
+	repriced := models.Job{JobId: "repriced", Title: "SRE", Salary: "$130,000", PostedDate: "2026-10-15"}
+	store := NewMemoryStore()
+	for _, job := range []models.Job{unchanged, repriced} {
+		if err := store.PutJob(ctx, &job); err != nil {
+			t.Fatalf("PutJob returned error: %v", err)
+		}
+	}
+	repriced.Salary = "$150,000"
+	fresh := models.Job{JobId: "fresh", Title: "Data Engineer", PostedDate: "2026-10-15"}
+	cfg := config.Config{JobWriteMode: config.JobWriteUpsert}
+
+	sources := []JobSource{&fakeJobSource{name: "alpha", jobs: []models.Job{unchanged, repriced, fresh}}}
+	scraper := NewScraperWithSources(cfg, false, nil, sources)
+	scraper.SkipUnchangedPostings(store)
+	stats := &models.JobStats{}
+	jobs, err := collectScrapedJobs(ctx, scraper, "engineer", stats)
+	if err != nil {
+		t.Fatalf("ScrapeJobs returned error: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].JobId != "repriced" || jobs[1].JobId != "fresh" {
+		t.Fatalf("expected only the changed and new postings, got %+v", jobs)
+	}
+	if snapshot := stats.Snapshot(); snapshot.SkippedJobs != 1 {
+		t.Fatalf("expected the unchanged posting to count as skipped, got %+v", snapshot)
+	}
+
+	// the cache remembers each posting by fingerprint, so a later change to
+	// a cached posting still gets through
+	cached := scraper.GetProcessedIDs()
+	if len(cached) != 3 || !cached[JobCacheKey(config.JobWriteUpsert, &unchanged)] {
+		t.Fatalf("expected every posting cached by fingerprint, got %+v", cached)
+	}
+	repriced.Salary = "$160,000"
+	sources = []JobSource{&fakeJobSource{name: "alpha", jobs: []models.Job{unchanged, repriced, fresh}}}
+	jobs, err = collectScrapedJobs(ctx, NewScraperWithSources(cfg, false, cached, sources), "engineer", &models.JobStats{})
+	if err != nil || len(jobs) != 1 || jobs[0].JobId != "repriced" {
+		t.Fatalf("expected the cache to let only the changed posting through, got %+v (%v)", jobs, err)
+	}
+}
+
+type fakeJobSource struct {
+	name    string
+	jobs    []models.Job
+	err     error
+	queries []config.ScrapeQuery
+}
+
+func (f *fakeJobSource) Name() string {
+	return f.name
+}
+
+func (f *fakeJobSource) SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error) {
+	f.queries = append(f.queries, query)
+	return f.jobs, f.err
+}
+
+func newTestWorkSourceScraper(cfg config.Config, server *httptest.Server) ScraperClient {
+	source := NewWorkSourceJobSource(cfg).(*workSourceJobSource)
+	source.httpClient = server.Client()
+	source.apexEndpoint = server.URL
+	return NewScraperWithSources(cfg, false, nil, []JobSource{source})
+}
+
 func collectScrapedJobs(ctx context.Context, scraper ScraperClient, query string, stats *models.JobStats) ([]models.Job, error) {
+	return collectScrapedPlan(ctx, scraper, []config.ScrapeQuery{{Query: query}}, stats)
+}
+
+func collectScrapedPlan(ctx context.Context, scraper ScraperClient, plan []config.ScrapeQuery, stats *models.JobStats) ([]models.Job, error) {
 	jobsChan := make(chan models.Job)
 	errChan := make(chan error, 1)
 	go func() {
-		errChan <- scraper.ScrapeJobs(ctx, query, jobsChan, stats)
+		errChan <- scraper.ScrapeJobs(ctx, plan, jobsChan, stats)
 	}()
 
 	var jobs []models.Job
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"regexp"
+	"slices"
+
+	"gopher-source/models"
+)
+
+// Values of models.Job.Seniority, from the least to the most senior.
+const (
+	SeniorityIntern    = "Intern"
+	SeniorityEntry     = "Entry"
+	SeniorityMid       = "Mid"
+	SenioritySenior    = "Senior"
+	SeniorityStaff     = "Staff"
+	SeniorityPrincipal = "Principal"
+	SeniorityManager   = "Manager"
+	SeniorityDirector  = "Director"
+)
+
+// QualityFlagSeniorityMismatch marks a job whose title names a different
+// level than the LLM's Seniority.
+const QualityFlagSeniorityMismatch = "seniority_mismatch"
+
+// seniorityTitlePatterns are checked in order, so "Senior Engineering
+// Manager" is a Manager and "Senior Staff Engineer" is Staff. Titles without
+// a level cue, such as "Software Engineer", match nothing rather than Mid.
+var seniorityTitlePatterns = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{SeniorityIntern, regexp.MustCompile(`(?i)\b(intern|internship|co-?op)\b`)},
+	{SeniorityDirector, regexp.MustCompile(`(?i)\b(director|vp|vice president|head of|cto|chief)\b`)},
+	// product, project and program managers are individual contributors
+	{SeniorityManager, regexp.MustCompile(`(?i)\b(engineering|software|development|team|qa|data|it)\s+manager\b|\bmanager,?\s+(of\s+)?(software|engineering|development)\b`)},
+	{SeniorityPrincipal, regexp.MustCompile(`(?i)\b(principal|distinguished|fellow)\b`)},
+	{SeniorityStaff, regexp.MustCompile(`(?i)\bstaff\b`)},
+	{SenioritySenior, regexp.MustCompile(`(?i)\b(senior|sr)\b|\b(engineer|developer|sde)\s+(iii|3)\b`)},
+	{SeniorityEntry, regexp.MustCompile(`(?i)\b(junior|jr|entry[- ]level|new grad(uate)?|graduate|apprentice)\b|\b(engineer|developer|sde)\s+(i|1)\b`)},
+	{SeniorityMid, regexp.MustCompile(`(?i)\bmid[- ]level\b|\b(engineer|developer|sde)\s+(ii|2)\b`)},
+}
+
+// titleSeniority returns the level a title names, or "" when it names none.
+func titleSeniority(title string) string {
+	for _, candidate := range seniorityTitlePatterns {
+		if candidate.pattern.MatchString(title) {
+			return candidate.level
+		}
+	}
+	return ""
+}
+
+// checkSeniority flags a job whose title names a level other than
+// job.Seniority, replacing any earlier flag for it.
+func checkSeniority(job *models.Job) {
+	job.QualityFlags = slices.DeleteFunc(job.QualityFlags, func(flag string) bool {
+		return flag == QualityFlagSeniorityMismatch
+	})
+	if level := titleSeniority(job.Title); level != "" && job.Seniority != "" && level != job.Seniority {
+		job.QualityFlags = append(job.QualityFlags, QualityFlagSeniorityMismatch)
+	}
+}
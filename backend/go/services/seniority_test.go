@@ -0,0 +1,73 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestTitleSeniority(t *testing.T) {
+	tests := map[string]string{
+		"Software Engineering Intern, Summer 2027": SeniorityIntern,
+		"Software Engineer I, Front-End":           SeniorityEntry,
+		"Junior Developer":                         SeniorityEntry,
+		"New Grad Software Engineer":               SeniorityEntry,
+		"Software Engineer II":                     SeniorityMid,
+		"Software Engineer III":                    SenioritySenior,
+		"Sr. Backend Engineer":                     SenioritySenior,
+		"Backend Engineer, Senior":                 SenioritySenior,
+		"Senior Staff Engineer":                    SeniorityStaff,
+		"Principal Architect":                      SeniorityPrincipal,
+		"Senior Engineering Manager":               SeniorityManager,
+		"Manager, Software Development":            SeniorityManager,
+		"Senior Director of Engineering":           SeniorityDirector,
+		"VP, Platform":                             SeniorityDirector,
+		"Software Engineer":                        "",
+		"Senior Product Manager":                   SenioritySenior,
+		"Technical Program Manager":                "",
+		"Engineer in Test":                         "",
+	}
+	for title, want := range tests {
+		if got := titleSeniority(title); got != want {
+			t.Fatalf("titleSeniority(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestCheckSeniorityFlagsTitleMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		seniority string
+		flags     []string
+		want      []string
+	}{
+		{"agrees", "Senior Backend Engineer", SenioritySenior, nil, nil},
+		{"no title cue", "Backend Engineer", SeniorityStaff, nil, nil},
+		{"disagrees", "Senior Backend Engineer", SeniorityMid, nil, []string{QualityFlagSeniorityMismatch}},
+		{"reparsed to agree", "Staff Engineer", SeniorityStaff, []string{QualityFlagSeniorityMismatch}, []string{}},
+		{"flagged once", "Staff Engineer", SeniorityEntry, []string{QualityFlagSeniorityMismatch}, []string{QualityFlagSeniorityMismatch}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := models.Job{Title: tt.title, Seniority: tt.seniority, QualityFlags: tt.flags}
+			checkSeniority(&job)
+			if !reflect.DeepEqual(job.QualityFlags, tt.want) {
+				t.Fatalf("QualityFlags = %q, want %q", job.QualityFlags, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopulateJobFromResponseFlagsSeniorityMismatch(t *testing.T) {
+	job := models.Job{Title: "Senior Software Engineer"}
+	populateJobFromResponse(&job, models.OpenAIJobParsingResponse{Seniority: SeniorityEntry})
+
+	if job.Seniority != SeniorityEntry {
+		t.Fatalf("expected the LLM's seniority to be kept, got %q", job.Seniority)
+	}
+	if !reflect.DeepEqual(job.QualityFlags, []string{QualityFlagSeniorityMismatch}) {
+		t.Fatalf("expected a seniority mismatch flag, got %q", job.QualityFlags)
+	}
+}
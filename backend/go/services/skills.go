@@ -0,0 +1,129 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopher-source/models"
+)
+
+// Categories of the skill taxonomy. Only SkillCategoryLanguage skills belong
+// in Job.Languages; the rest go in Job.Technologies.
+const (
+	SkillCategoryLanguage  = "language"
+	SkillCategoryCloud     = "cloud"
+	SkillCategoryDatabase  = "database"
+	SkillCategoryFramework = "framework"
+	SkillCategoryTooling   = "tooling"
+)
+
+// skillsCSV maps each canonical skill name to its category and the aliases
+// the LLM writes for it.
+//
+//go:embed data/skills.csv
+var skillsCSV string
+
+var skills = newSkillTaxonomy(skillsCSV)
+
+// "Python 3", "Java 17" and "Angular 15.x" name the same skill as without the version
+var skillVersionPattern = regexp.MustCompile(`\s+v?\d+(?:\.\d+)*(?:\.x)?\+?$`)
+
+type skill struct {
+	name     string
+	category string
+}
+
+type skillTaxonomy map[string]skill
+
+// newSkillTaxonomy panics on a malformed file, which is bundled with the
+// binary.
+func newSkillTaxonomy(data string) skillTaxonomy {
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("services: read skill taxonomy: %v", err))
+	}
+	taxonomy := make(skillTaxonomy, len(records)*2)
+	for _, record := range records[1:] {
+		entry := skill{name: record[0], category: record[1]}
+		names := []string{record[0]}
+		if record[2] != "" {
+			names = append(names, strings.Split(record[2], "|")...)
+		}
+		for _, name := range names {
+			key := skillKey(name)
+			if existing, ok := taxonomy[key]; ok && existing.name != entry.name {
+				panic(fmt.Sprintf("services: skill alias %q names both %s and %s", name, existing.name, entry.name))
+			}
+			taxonomy[key] = entry
+		}
+	}
+	return taxonomy
+}
+
+func (t skillTaxonomy) lookup(term string) (skill, bool) {
+	if entry, ok := t[skillKey(term)]; ok {
+		return entry, true
+	}
+	entry, ok := t[skillKey(skillVersionPattern.ReplaceAllString(strings.TrimSpace(term), ""))]
+	return entry, ok
+}
+
+func skillKey(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
+
+// normalizeJobSkills rewrites Languages and Technologies to canonical names,
+// moves each known skill to the list its category belongs in, and drops
+// duplicates. Unknown terms stay where the LLM put them.
+func normalizeJobSkills(job *models.Job) {
+	var languages, technologies []string
+	seen := make(map[string]bool)
+	add := func(term string, inLanguages bool) {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			return
+		}
+		if entry, ok := skills.lookup(term); ok {
+			term, inLanguages = entry.name, entry.category == SkillCategoryLanguage
+		}
+		if key := skillKey(term); !seen[key] {
+			seen[key] = true
+			if inLanguages {
+				languages = append(languages, term)
+			} else {
+				technologies = append(technologies, term)
+			}
+		}
+	}
+	for _, term := range job.Languages {
+		add(term, true)
+	}
+	for _, term := range job.Technologies {
+		add(term, false)
+	}
+	job.Languages, job.Technologies = languages, technologies
+}
+
+// UnknownSkills returns the job's Languages and Technologies missing from
+// the taxonomy, for the review list.
+func UnknownSkills(job *models.Job) []string {
+	var unknown []string
+	for _, terms := range [][]string{job.Languages, job.Technologies} {
+		for _, term := range terms {
+			if _, ok := skills.lookup(term); !ok {
+				unknown = append(unknown, term)
+			}
+		}
+	}
+	return unknown
+}
+
+// SkillCategory returns the taxonomy category of a skill, or "" when the
+// skill is unknown.
+func SkillCategory(term string) string {
+	entry, _ := skills.lookup(term)
+	return entry.category
+}
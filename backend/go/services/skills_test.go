@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"gopher-source/models"
+)
+
+func TestNormalizeJobSkills(t *testing.T) {
+	job := models.Job{
+		Languages:    []string{"Golang", "python 3", "Docker", "go", "Gleam"},
+		Technologies: []string{"k8s", "Postgres", "SQL", "Angular 15.x", "AWS Lambda", "Kubernetes", "  "},
+	}
+
+	normalizeJobSkills(&job)
+
+	if want := []string{"Go", "Python", "Gleam", "SQL"}; !reflect.DeepEqual(job.Languages, want) {
+		t.Fatalf("Languages = %q, want %q", job.Languages, want)
+	}
+	if want := []string{"Docker", "Kubernetes", "PostgreSQL", "Angular", "AWS Lambda"}; !reflect.DeepEqual(job.Technologies, want) {
+		t.Fatalf("Technologies = %q, want %q", job.Technologies, want)
+	}
+}
+
+func TestUnknownSkills(t *testing.T) {
+	job := models.Job{Languages: []string{"Go", "Gleam"}, Technologies: []string{"Kubernetes", "Pulumi YAML"}}
+
+	if got, want := UnknownSkills(&job), []string{"Gleam", "Pulumi YAML"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("UnknownSkills = %q, want %q", got, want)
+	}
+}
+
+func TestSkillCategory(t *testing.T) {
+	tests := map[string]string{
+		"golang":     SkillCategoryLanguage,
+		"Java 17":    SkillCategoryLanguage,
+		"GCP":        SkillCategoryCloud,
+		"postgres":   SkillCategoryDatabase,
+		"React.js":   SkillCategoryFramework,
+		"Terraform":  SkillCategoryTooling,
+		"Gleam":      "",
+		"Definitely": "",
+	}
+	for term, want := range tests {
+		if got := SkillCategory(term); got != want {
+			t.Fatalf("SkillCategory(%q) = %q, want %q", term, got, want)
+		}
+	}
+}
+
+func TestSkillTaxonomyCategoriesAreKnown(t *testing.T) {
+	categories := map[string]bool{
+		SkillCategoryLanguage: true, SkillCategoryCloud: true, SkillCategoryDatabase: true,
+		SkillCategoryFramework: true, SkillCategoryTooling: true,
+	}
+	for key, entry := range skills {
+		if !categories[entry.category] {
+			t.Fatalf("%s has unknown category %q", key, entry.category)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopher-source/config"
+	"gopher-source/models"
+)
+
+const WorkSourceSourceName = "worksourcewa"
+
+// JobSource searches a single job board and maps its postings into models.Job.
+// Implementations may return the listings gathered so far alongside an error.
+type JobSource interface {
+	Name() string
+	SearchJobs(ctx context.Context, query config.ScrapeQuery) ([]models.Job, error)
+}
+
+// IncrementalJobSource is implemented by sources whose results are ordered
+// newest-first, letting a search stop once it reaches the stored watermark.
+// The returned watermark replaces since for the next run. Listings since
+// covers, matched on PostedTime and JobId, are returned with the rest; the
+// scraper keeps them for posting history only.
+type IncrementalJobSource interface {
+	JobSource
+	SearchJobsSince(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark) ([]models.Job, models.ScrapeWatermark, error)
+}
+
+// ResumableJobSource is implemented by sources that can continue a search an
+// earlier run abandoned at its deadline. A zero cursor starts from the first
+// page. When ctx ends mid-search the returned cursor marks where to resume.
+type ResumableJobSource interface {
+	IncrementalJobSource
+	SearchJobsFrom(ctx context.Context, query config.ScrapeQuery, since models.ScrapeWatermark, cursor models.ScrapeCursor) ([]models.Job, models.ScrapeWatermark, models.ScrapeCursor, error)
+}
+
+type JobSourceFactory func(cfg config.Config) (JobSource, error)
+
+var (
+	jobSourceRegistryMu sync.RWMutex
+	jobSourceRegistry   = make(map[string]JobSourceFactory)
+)
+
+// RegisterJobSource makes a source available to NewJobSources under name.
+func RegisterJobSource(name string, factory JobSourceFactory) {
+	name = normalizeJobSourceName(name)
+	if name == "" || factory == nil {
+		panic("services: RegisterJobSource requires a name and factory")
+	}
+
+	jobSourceRegistryMu.Lock()
+	defer jobSourceRegistryMu.Unlock()
+	if _, exists := jobSourceRegistry[name]; exists {
+		panic(fmt.Sprintf("services: job source %q already registered", name))
+	}
+	jobSourceRegistry[name] = factory
+}
+
+// NewJobSources builds the sources named in cfg.JobSources, defaulting to
+// WorkSourceWA when none are configured.
+func NewJobSources(cfg config.Config) ([]JobSource, error) {
+	names := cfg.JobSources
+	if len(names) == 0 {
+		names = []string{WorkSourceSourceName}
+	}
+
+	jobSourceRegistryMu.RLock()
+	defer jobSourceRegistryMu.RUnlock()
+
+	seen := make(map[string]bool, len(names))
+	sources := make([]JobSource, 0, len(names))
+	for _, name := range names {
+		name = normalizeJobSourceName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		factory, ok := jobSourceRegistry[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown job source %q (registered: %s)", name, strings.Join(registeredJobSourceNames(), ", "))
+		}
+		source, err := factory(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("create job source %s: %w", name, err)
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+func normalizeJobSourceName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// registeredJobSourceNames expects the caller to hold jobSourceRegistryMu.
+func registeredJobSourceNames() []string {
+	names := make([]string, 0, len(jobSourceRegistry))
+	for name := range jobSourceRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matchesJobQuery reports whether every query term appears in title and, when
+// the query sets a location, whether location contains it. Board APIs without
+// server-side search use it to keep a run on-topic.
+func matchesJobQuery(title, location string, query config.ScrapeQuery) bool {
+	title = strings.ToLower(title)
+	for _, term := range strings.Fields(strings.ToLower(query.Query)) {
+		if !strings.Contains(title, term) {
+			return false
+		}
+	}
+	wantLocation := strings.ToLower(strings.TrimSpace(query.Location))
+	return wantLocation == "" || strings.Contains(strings.ToLower(location), wantLocation)
+}
+
+func fetchSourceJSON[T any](ctx context.Context, client *http.Client, endpoint string) (T, error) {
+	var zero T
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return zero, fmt.Errorf("create request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "vapor-source-scraper/2.0")
+
+	response, err := client.Do(request)
+	if err != nil {
+		return zero, fmt.Errorf("send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 32<<20))
+	if err != nil {
+		return zero, fmt.Errorf("read response: %w", err)
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return zero, fmt.Errorf("%s returned HTTP %d: %s", endpoint, response.StatusCode, truncateScraperResponse(string(body), 300))
+	}
+
+	var result T
+	if err := json.Unmarshal(body, &result); err != nil {
+		return zero, fmt.Errorf("decode response: %w", err)
+	}
+	return result, nil
+}
+
+// ScrapeWatermarkKey identifies a watermark by source and query, including
+// any filters that change the result set.
+func ScrapeWatermarkKey(sourceName string, query config.ScrapeQuery) string {
+	return normalizeJobSourceName(sourceName) + "|" + query.Key()
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+)
+
+func TestNewJobSourcesDefaultsToWorkSource(t *testing.T) {
+	sources, err := NewJobSources(config.Config{})
+	if err != nil {
+		t.Fatalf("NewJobSources returned error: %v", err)
+	}
+	if len(sources) != 1 || sources[0].Name() != WorkSourceSourceName {
+		t.Fatalf("expected only %s, got %+v", WorkSourceSourceName, sources)
+	}
+}
+
+func TestNewJobSourcesResolvesRegisteredNames(t *testing.T) {
+	RegisterJobSource("test-registry-source", func(cfg config.Config) (JobSource, error) {
+		return &fakeJobSource{name: "test-registry-source"}, nil
+	})
+
+	sources, err := NewJobSources(config.Config{JobSources: []string{" WorkSourceWA ", "test-registry-source", "worksourcewa"}})
+	if err != nil {
+		t.Fatalf("NewJobSources returned error: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected duplicate names to collapse into 2 sources, got %d", len(sources))
+	}
+	if sources[0].Name() != WorkSourceSourceName || sources[1].Name() != "test-registry-source" {
+		t.Fatalf("unexpected source order: %s, %s", sources[0].Name(), sources[1].Name())
+	}
+	if _, err := sources[1].SearchJobs(context.Background(), config.ScrapeQuery{Query: "engineer"}); err != nil {
+		t.Fatalf("unexpected fake source error: %v", err)
+	}
+}
+
+func TestNewJobSourcesRejectsUnknownName(t *testing.T) {
+	_, err := NewJobSources(config.Config{JobSources: []string{"monster"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown job source "monster"`) {
+		t.Fatalf("expected unknown source error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), WorkSourceSourceName) {
+		t.Fatalf("expected error to list registered sources, got %v", err)
+	}
+}
+
+func TestWorkSourceJobSourceRecordsSourceName(t *testing.T) {
+	job := workSourceJob{RecordID: "record-1"}.toJob()
+	if job.Source != WorkSourceSourceName {
+		t.Fatalf("expected source %q, got %q", WorkSourceSourceName, job.Source)
+	}
+}
+
+func TestMatchesJobQuery(t *testing.T) {
+	cases := []struct {
+		name     string
+		title    string
+		location string
+		query    config.ScrapeQuery
+		want     bool
+	}{
+		{"allTerms", "Senior Software Engineer", "Remote", config.ScrapeQuery{Query: "software engineer"}, true},
+		{"missingTerm", "Backend Engineer", "Remote", config.ScrapeQuery{Query: "software engineer"}, false},
+		{"emptyQuery", "Recruiter", "Remote", config.ScrapeQuery{}, true},
+		{"locationMatch", "Data Engineer", "Seattle, WA", config.ScrapeQuery{Query: "engineer", Location: "seattle"}, true},
+		{"locationMismatch", "Data Engineer", "Austin, TX", config.ScrapeQuery{Query: "engineer", Location: "seattle"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchesJobQuery(tc.title, tc.location, tc.query); got != tc.want {
+				t.Fatalf("matchesJobQuery = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
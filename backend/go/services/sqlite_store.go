@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopher-source/models"
+	"gopher-source/utils"
+
+	// registers the "sqlite3" driver; it needs cgo, which the Lambda builds
+	// turn off, so only local runs can use this store
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteSchema keeps each job as its JSON document, with the columns the
+// store queries by pulled out beside it. Jobs are keyed like the DynamoDB
+// table, on JobId and PostedDate.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	job_id      TEXT NOT NULL,
+	posted_date TEXT NOT NULL,
+	posted_time TEXT NOT NULL,
+	item        TEXT NOT NULL,
+	PRIMARY KEY (job_id, posted_date)
+);
+CREATE INDEX IF NOT EXISTS jobs_posted_date ON jobs (posted_date, posted_time);
+`
+
+// sqliteKeyMigration rebuilds a jobs table keyed on job_id alone, as stores
+// created before the composite key have it.
+const sqliteKeyMigration = `
+ALTER TABLE jobs RENAME TO jobs_by_id;
+DROP INDEX IF EXISTS jobs_posted_date;
+` + sqliteSchema + `
+INSERT INTO jobs (job_id, posted_date, posted_time, item) SELECT job_id, posted_date, posted_time, item FROM jobs_by_id;
+DROP TABLE jobs_by_id;
+`
+
+type sqliteStoreImpl struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens, and creates if needed, a job database at path.
+func NewSQLiteStore(path string) (JobStore, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, fmt.Errorf("sqlite path is required")
+	}
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000&_journal_mode=WAL")
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+	// one writer at a time; concurrent parsers otherwise see "database is locked"
+	db.SetMaxOpenConns(1)
+	if err := migrateSQLiteKey(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate sqlite schema: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create sqlite schema: %w", err)
+	}
+	return &sqliteStoreImpl{db: db}, nil
+}
+
+// migrateSQLiteKey moves a jobs table keyed on job_id alone to the composite
+// key. A new database has no jobs table and is left for the schema to create.
+func migrateSQLiteKey(db *sql.DB) error {
+	var keyed bool
+	err := db.QueryRow(`SELECT pk > 0 FROM pragma_table_info('jobs') WHERE name = 'posted_date'`).Scan(&keyed)
+	if errors.Is(err, sql.ErrNoRows) || keyed {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec(sqliteKeyMigration); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqliteStoreImpl) PutJob(ctx context.Context, job *models.Job) error {
+	item, err := json.Marshal(firstVersion(job))
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	result, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (job_id, posted_date, posted_time, item) VALUES (?, ?, ?, ?) ON CONFLICT (job_id, posted_date) DO NOTHING`,
+		job.JobId, job.PostedDate, job.PostedTime, string(item))
+	if err != nil {
+		return fmt.Errorf("failed to put job: %w", err)
+	}
+	if inserted, err := result.RowsAffected(); err == nil && inserted == 0 {
+		utils.Debug("\t⚠️  Item already exists, skipping")
+		return nil
+	}
+	utils.Debug(fmt.Sprintf("\t📦 Post successful: for job %s", job.Title))
+	return nil
+}
+
+// PutJobs inserts jobs in one transaction.
+func (s *sqliteStoreImpl) PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error) {
+	result := WriteResult{Calls: 1}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return WriteResult{}, fmt.Errorf("failed to put jobs: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i := range jobs {
+		item, err := json.Marshal(firstVersion(&jobs[i]))
+		if err != nil {
+			return WriteResult{}, fmt.Errorf("failed to marshal job: %w", err)
+		}
+		inserted, err := tx.ExecContext(ctx,
+			`INSERT INTO jobs (job_id, posted_date, posted_time, item) VALUES (?, ?, ?, ?) ON CONFLICT (job_id, posted_date) DO NOTHING`,
+			jobs[i].JobId, jobs[i].PostedDate, jobs[i].PostedTime, string(item))
+		if err != nil {
+			return WriteResult{}, fmt.Errorf("failed to put job %s: %w", jobs[i].JobId, err)
+		}
+		if rows, err := inserted.RowsAffected(); err == nil && rows == 0 {
+			result.Skipped++
+		} else {
+			result.Stored++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return WriteResult{}, fmt.Errorf("failed to put jobs: %w", err)
+	}
+	utils.Debug(fmt.Sprintf("\t📦 Stored %d job(s) in one transaction", result.Stored))
+	return result, nil
+}
+
+func (s *sqliteStoreImpl) UpsertJob(ctx context.Context, job *models.Job) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("upsert job %s: %w", job.JobId, err)
+	}
+	defer tx.Rollback()
+
+	var current *models.Job
+	var item string
+	err = tx.QueryRowContext(ctx, `SELECT item FROM jobs WHERE job_id = ? AND posted_date = ?`, job.JobId, job.PostedDate).Scan(&item)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return false, fmt.Errorf("upsert job %s: %w", job.JobId, err)
+	default:
+		current = &models.Job{}
+		if err := json.Unmarshal([]byte(item), current); err != nil {
+			return false, fmt.Errorf("decode stored job %s: %w", job.JobId, err)
+		}
+	}
+	next, changed := nextVersion(current, job)
+	if !changed {
+		utils.Debug("\t⚠️  Posting unchanged, skipping")
+		return false, nil
+	}
+	data, err := json.Marshal(next)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO jobs (job_id, posted_date, posted_time, item) VALUES (?, ?, ?, ?)
+		ON CONFLICT (job_id, posted_date) DO UPDATE SET posted_time = excluded.posted_time, item = excluded.item`,
+		job.JobId, job.PostedDate, job.PostedTime, string(data)); err != nil {
+		return false, fmt.Errorf("upsert job %s: %w", job.JobId, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("upsert job %s: %w", job.JobId, err)
+	}
+	utils.Debug(fmt.Sprintf("\t📦 Upserted job %s", job.Title))
+	return true, nil
+}
+
+func (s *sqliteStoreImpl) UpdateJobEnrichment(ctx context.Context, job *models.Job) error {
+	if err := s.updateAttributes(ctx, job, enrichedAttributes); err != nil {
+		return fmt.Errorf("update enrichment of job %s: %w", job.JobId, err)
+	}
+	utils.Debug(fmt.Sprintf("\t📝 Updated enrichment for job %s", job.Title))
+	return nil
+}
+
+func (s *sqliteStoreImpl) UpdateJobHistory(ctx context.Context, job *models.Job) error {
+	if err := s.updateAttributes(ctx, job, historyAttributes); err != nil {
+		return fmt.Errorf("update history of job %s: %w", job.JobId, err)
+	}
+	return nil
+}
+
+func (s *sqliteStoreImpl) updateAttributes(ctx context.Context, job *models.Job, names []string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var item string
+	err = tx.QueryRowContext(ctx, `SELECT item FROM jobs WHERE job_id = ? AND posted_date = ?`, job.JobId, job.PostedDate).Scan(&item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrJobNotStored
+	}
+	if err != nil {
+		return err
+	}
+	var stored models.Job
+	if err := json.Unmarshal([]byte(item), &stored); err != nil {
+		return fmt.Errorf("decode stored job: %w", err)
+	}
+	merged, err := mergeAttributes(stored, *job, names)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE jobs SET item = ? WHERE job_id = ? AND posted_date = ?`, string(data), job.JobId, job.PostedDate); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// QueryJobsByPostedDate returns the newest jobs first, like the DynamoDB
+// index.
+func (s *sqliteStoreImpl) QueryJobsByPostedDate(ctx context.Context, date string) ([]models.Job, error) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return nil, fmt.Errorf("posted date is required")
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT item FROM jobs WHERE posted_date = ? ORDER BY posted_time DESC`, date)
+	if err != nil {
+		return nil, fmt.Errorf("query jobs: %w", err)
+	}
+	return scanJobRows(rows, JobFilter{})
+}
+
+func (s *sqliteStoreImpl) QueryJobsByDateRange(ctx context.Context, startDate, endDate string) ([]models.Job, error) {
+	filter, err := dateRangeFilter(startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return s.QueryJobs(ctx, filter)
+}
+
+// QueryJobs narrows by posted date in SQL, over the posted date index, and
+// checks the other fields against each job's document.
+func (s *sqliteStoreImpl) QueryJobs(ctx context.Context, filter JobFilter) ([]models.Job, error) {
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	query := `SELECT item FROM jobs WHERE 1 = 1`
+	var args []any
+	if filter.PostedFrom != "" {
+		query += ` AND posted_date >= ?`
+		args = append(args, filter.PostedFrom)
+	}
+	if filter.PostedTo != "" {
+		query += ` AND posted_date <= ?`
+		args = append(args, filter.PostedTo)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY posted_date DESC, posted_time DESC`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query jobs: %w", err)
+	}
+	return scanJobRows(rows, filter)
+}
+
+// scanJobRows decodes the job documents in rows that match filter and closes
+// rows.
+func scanJobRows(rows *sql.Rows, filter JobFilter) ([]models.Job, error) {
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		var item string
+		if err := rows.Scan(&item); err != nil {
+			return nil, fmt.Errorf("query jobs: %w", err)
+		}
+		var job models.Job
+		if err := json.Unmarshal([]byte(item), &job); err != nil {
+			return nil, fmt.Errorf("unmarshal jobs: %w", err)
+		}
+		if filter.matches(job) {
+			jobs = append(jobs, job)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (s *sqliteStoreImpl) GetAllJobIds(ctx context.Context) (map[string]bool, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT job_id FROM jobs`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job ids: %w", err)
+	}
+	defer rows.Close()
+
+	jobIds := make(map[string]bool)
+	for rows.Next() {
+		var jobID string
+		if err := rows.Scan(&jobID); err != nil {
+			return nil, fmt.Errorf("failed to list job ids: %w", err)
+		}
+		jobIds[jobID] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list job ids: %w", err)
+	}
+	utils.Debug(fmt.Sprintf("📊 Retrieved %d job IDs from SQLite", len(jobIds)))
+	return jobIds, nil
+}
+
+func (s *sqliteStoreImpl) SourceHashes(ctx context.Context, jobs []models.Job) ([]string, error) {
+	hashes := make([]string, len(jobs))
+	for i, job := range jobs {
+		var item string
+		err := s.db.QueryRowContext(ctx, `SELECT item FROM jobs WHERE job_id = ? AND posted_date = ?`, job.JobId, job.PostedDate).Scan(&item)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("look up job %s: %w", job.JobId, err)
+		}
+		var stored models.Job
+		if err := json.Unmarshal([]byte(item), &stored); err != nil {
+			return nil, fmt.Errorf("decode stored job %s: %w", job.JobId, err)
+		}
+		hashes[i] = stored.SourceHash
+	}
+	return hashes, nil
+}
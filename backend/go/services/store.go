@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopher-source/config"
+	"gopher-source/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+)
+
+// ErrJobNotStored is returned by UpdateJobEnrichment for a job the store
+// does not hold.
+var ErrJobNotStored = errors.New("job not stored")
+
+// JobStore persists enriched jobs. Every backend keys a job on JobId and
+// PostedDate, like the DynamoDB table, so a listing reposted on a new date is
+// a new job. PutJob keeps the first job stored under a key and skips later
+// puts of it; UpsertJob replaces it when the source posting changed.
+type JobStore interface {
+	PutJob(ctx context.Context, job *models.Job) error
+	// PutJobs inserts several jobs like PutJob, in as few requests as the
+	// backend allows.
+	PutJobs(ctx context.Context, jobs []models.Job) (WriteResult, error)
+	// UpsertJob stores job, or replaces the stored one and bumps its Version
+	// when a field of the source posting changed. It reports whether it wrote.
+	UpsertJob(ctx context.Context, job *models.Job) (bool, error)
+	// UpdateJobEnrichment rewrites only the enriched fields of a stored job.
+	UpdateJobEnrichment(ctx context.Context, job *models.Job) error
+	// UpdateJobHistory rewrites only ClosedAt and History of a stored job.
+	UpdateJobHistory(ctx context.Context, job *models.Job) error
+	QueryJobsByPostedDate(ctx context.Context, date string) ([]models.Job, error)
+	// QueryJobsByDateRange returns the jobs posted from startDate to endDate,
+	// inclusive, newest first.
+	QueryJobsByDateRange(ctx context.Context, startDate, endDate string) ([]models.Job, error)
+	// QueryJobs returns the jobs matching every set field of filter, newest
+	// first. DynamoDB serves company and domain filters from their indexes.
+	QueryJobs(ctx context.Context, filter JobFilter) ([]models.Job, error)
+	GetAllJobIds(ctx context.Context) (map[string]bool, error)
+	// SourceHashes returns the SourceHash stored under each job's key, in
+	// order, or "" for a job the store does not hold.
+	SourceHashes(ctx context.Context, jobs []models.Job) ([]string, error)
+}
+
+// WriteResult counts what one PutJobs call did.
+type WriteResult struct {
+	Stored  int
+	Skipped int // already stored, or repeated within the call
+	Calls   int
+	// Retries counts the calls that resent items the backend left unprocessed
+	Retries int
+}
+
+// JobFilter selects jobs for QueryJobs. Empty fields match every job;
+// PostedFrom and PostedTo are inclusive YYYY-MM-DD bounds on PostedDate, and
+// the other fields must equal the stored value exactly.
+type JobFilter struct {
+	PostedFrom string
+	PostedTo   string
+	Company    string
+	Domain     string
+	Modality   string
+}
+
+const postedDateLayout = "2006-01-02"
+
+func (f JobFilter) validate() error {
+	for _, date := range []string{f.PostedFrom, f.PostedTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(postedDateLayout, date); err != nil {
+			return fmt.Errorf("invalid posted date %q: %w", date, err)
+		}
+	}
+	if f.PostedFrom != "" && f.PostedTo != "" && f.PostedTo < f.PostedFrom {
+		return fmt.Errorf("posted date range ends (%s) before it starts (%s)", f.PostedTo, f.PostedFrom)
+	}
+	return nil
+}
+
+func (f JobFilter) matches(job models.Job) bool {
+	return (f.PostedFrom == "" || job.PostedDate >= f.PostedFrom) &&
+		(f.PostedTo == "" || job.PostedDate <= f.PostedTo) &&
+		(f.Company == "" || job.Company == f.Company) &&
+		(f.Domain == "" || job.Domain == f.Domain) &&
+		(f.Modality == "" || job.Modality == f.Modality)
+}
+
+// dateRangeFilter is the filter behind QueryJobsByDateRange, which
+// needs both ends of the range.
+func dateRangeFilter(start, end string) (JobFilter, error) {
+	filter := JobFilter{PostedFrom: strings.TrimSpace(start), PostedTo: strings.TrimSpace(end)}
+	if filter.PostedFrom == "" || filter.PostedTo == "" {
+		return JobFilter{}, fmt.Errorf("posted date range needs a start and an end")
+	}
+	return filter, filter.validate()
+}
+
+// sortNewestFirst orders jobs by PostedDate, then PostedTime, descending.
+func sortNewestFirst(jobs []models.Job) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].PostedDate != jobs[j].PostedDate {
+			return jobs[i].PostedDate > jobs[j].PostedDate
+		}
+		return jobs[i].PostedTime > jobs[j].PostedTime
+	})
+}
+
+// NewJobStore opens the backend cfg.JobStore names. awsConfig is only used
+// by DynamoDB.
+func NewJobStore(cfg config.Config, awsConfig aws.Config) (JobStore, error) {
+	switch cfg.JobStore {
+	case config.JobStoreDynamoDB:
+		return NewDynamoService(awsConfig, cfg.DynamoTableName, cfg.DynamoEndpoint), nil
+	case config.JobStoreSQLite:
+		return NewSQLiteStore(cfg.SQLitePath)
+	case config.JobStoreMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown job store %q", cfg.JobStore)
+	}
+}
+
+// mergeAttributes returns stored with the named attributes of job, as an
+// UpdateItem setting them would leave it in DynamoDB.
+func mergeAttributes(stored, job models.Job, names []string) (models.Job, error) {
+	storedItem, err := attributevalue.MarshalMap(stored)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("marshal stored job: %w", err)
+	}
+	item, err := attributevalue.MarshalMap(job)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("marshal job: %w", err)
+	}
+	for _, name := range names {
+		if value, ok := item[name]; ok {
+			storedItem[name] = value
+		} else {
+			delete(storedItem, name)
+		}
+	}
+	var merged models.Job
+	if err := attributevalue.UnmarshalMap(storedItem, &merged); err != nil {
+		return models.Job{}, fmt.Errorf("unmarshal merged job: %w", err)
+	}
+	return merged, nil
+}
+
+// sourceHash fingerprints the fields of a job that come from the posting
+// itself, so a repost with new pay or a new closing date is told apart from
+// a re-scrape of the same listing. Enrichment replaces ExpiresDate with the
+// LLM's deadline, so the parser takes the fingerprint before it does.
+func sourceHash(job *models.Job) string {
+	return shortHash([]byte(job.Title), []byte(job.Company), []byte(job.Location),
+		[]byte(job.Salary), []byte(job.ExpiresDate), []byte(job.URL))
+}
+
+// JobCacheKey is job's entry in the job ID cache. In upsert mode it carries
+// the posting's fingerprint, so a changed posting gets past the cache while
+// an unchanged one is still skipped.
+func JobCacheKey(mode string, job *models.Job) string {
+	if mode != config.JobWriteUpsert {
+		return job.JobId
+	}
+	return job.JobId + " " + sourceHash(job)
+}
+
+// firstVersion returns job as it is first stored: version 1, fingerprinted
+// unless the parser already fingerprinted the listing.
+func firstVersion(job *models.Job) models.Job {
+	stored := *job
+	stored.Version = 1
+	if stored.SourceHash == "" {
+		stored.SourceHash = sourceHash(job)
+	}
+	return stored
+}
+
+// nextVersion returns job as it replaces stored, or false when the source
+// posting is unchanged and stored should be kept. The posting history is
+// carried over from stored.
+func nextVersion(stored, job *models.Job) (models.Job, bool) {
+	next := firstVersion(job)
+	if stored == nil {
+		return next, true
+	}
+	if stored.SourceHash == next.SourceHash {
+		return models.Job{}, false
+	}
+	next.Version = stored.Version + 1
+	next.ClosedAt = stored.ClosedAt
+	next.History = stored.History
+	return next, true
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopher-source/config"
+	"gopher-source/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// testStores returns one store per backend, DynamoDB against an in-memory
+// fake table, so every backend is held to the same contract.
+func testStores(t *testing.T) map[string]JobStore {
+	t.Helper()
+	sqliteStore, err := NewSQLiteStore(filepath.Join(t.TempDir(), "jobs.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore returned error: %v", err)
+	}
+	return map[string]JobStore{
+		config.JobStoreMemory:   NewMemoryStore(),
+		config.JobStoreSQLite:   sqliteStore,
+		config.JobStoreDynamoDB: newFakeDynamoStore(t),
+	}
+}
+
+func TestJobStorePutQueryAndList(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			jobs := []models.Job{
+				{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-15", PostedTime: "2026-10-15T08:00:00Z", Languages: []string{"Go"}},
+				{JobId: "2", Title: "SRE", PostedDate: "2026-10-15", PostedTime: "2026-10-15T09:00:00Z"},
+				{JobId: "3", Title: "Data Engineer", PostedDate: "2026-10-16", PostedTime: "2026-10-16T08:00:00Z"},
+				{JobId: "1", Title: "Go Developer (repost)", PostedDate: "2026-10-15", PostedTime: "2026-10-15T10:00:00Z"},
+			}
+			for i := range jobs {
+				if err := store.PutJob(ctx, &jobs[i]); err != nil {
+					t.Fatalf("PutJob(%s) returned error: %v", jobs[i].JobId, err)
+				}
+			}
+
+			got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+			if err != nil {
+				t.Fatalf("QueryJobsByPostedDate returned error: %v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("expected 2 jobs posted 2026-10-15, got %+v", got)
+			}
+			for _, job := range got {
+				if job.JobId == "1" && (job.Title != "Go Developer" || len(job.Languages) != 1) {
+					t.Fatalf("expected the first put of job 1 to be kept, got %+v", job)
+				}
+			}
+
+			ids, err := store.GetAllJobIds(ctx)
+			if err != nil {
+				t.Fatalf("GetAllJobIds returned error: %v", err)
+			}
+			if len(ids) != 3 || !ids["1"] || !ids["2"] || !ids["3"] {
+				t.Fatalf("unexpected job IDs: %+v", ids)
+			}
+		})
+	}
+}
+
+func TestJobStoreKeysJobsOnJobIdAndPostedDate(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			first := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-15", Domain: "Backend"}
+			reposted := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-16", Domain: "Backend"}
+			if err := store.PutJob(ctx, &first); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+			if result, err := store.PutJobs(ctx, []models.Job{reposted}); err != nil || result.Stored != 1 {
+				t.Fatalf("expected the same JobId on another date to be stored, got %+v (%v)", result, err)
+			}
+
+			update := models.Job{JobId: "1", PostedDate: "2026-10-16", Domain: "Full-Stack"}
+			if err := store.UpdateJobEnrichment(ctx, &update); err != nil {
+				t.Fatalf("UpdateJobEnrichment returned error: %v", err)
+			}
+			repriced := first
+			repriced.Salary = "$140,000"
+			if written, err := store.UpsertJob(ctx, &repriced); err != nil || !written {
+				t.Fatalf("expected the changed posting to be written, got %v (%v)", written, err)
+			}
+
+			jobs, err := store.QueryJobsByDateRange(ctx, "2026-10-15", "2026-10-16")
+			if err != nil || len(jobs) != 2 {
+				t.Fatalf("expected one job per posted date, got %+v (%v)", jobs, err)
+			}
+			want := map[string]models.Job{
+				"2026-10-15": {Domain: "Backend", Salary: "$140,000", Version: 2},
+				"2026-10-16": {Domain: "Full-Stack", Version: 1},
+			}
+			for _, job := range jobs {
+				w := want[job.PostedDate]
+				if job.Domain != w.Domain || job.Salary != w.Salary || job.Version != w.Version {
+					t.Fatalf("expected each write to reach only its own posted date, got %+v", job)
+				}
+			}
+
+			ids, err := store.GetAllJobIds(ctx)
+			if err != nil || len(ids) != 1 || !ids["1"] {
+				t.Fatalf("expected the JobId listed once, got %+v (%v)", ids, err)
+			}
+		})
+	}
+}
+
+func TestJobStorePutJobs(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			existing := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-15"}
+			if err := store.PutJob(ctx, &existing); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+
+			result, err := store.PutJobs(ctx, []models.Job{
+				{JobId: "1", Title: "Go Developer (repost)", PostedDate: "2026-10-15"},
+				{JobId: "2", Title: "SRE", PostedDate: "2026-10-15"},
+				{JobId: "2", Title: "SRE (repost)", PostedDate: "2026-10-15"},
+				{JobId: "3", Title: "Data Engineer", PostedDate: "2026-10-15"},
+			})
+			if err != nil {
+				t.Fatalf("PutJobs returned error: %v", err)
+			}
+			if result.Stored != 2 || result.Skipped != 2 {
+				t.Fatalf("expected 2 stored and 2 skipped, got %+v", result)
+			}
+
+			got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+			if err != nil || len(got) != 3 {
+				t.Fatalf("expected 3 stored jobs, got %+v (%v)", got, err)
+			}
+			want := map[string]string{"1": "Go Developer", "2": "SRE", "3": "Data Engineer"}
+			for _, job := range got {
+				if job.Title != want[job.JobId] {
+					t.Fatalf("expected the first write of each job to be kept, got %+v", job)
+				}
+			}
+		})
+	}
+}
+
+func TestJobStoreQueryJobs(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			jobs := []models.Job{
+				{JobId: "1", Company: "Acme", Domain: "Backend", Modality: "Remote", PostedDate: "2026-10-13", PostedTime: "2026-10-13T08:00:00Z"},
+				{JobId: "2", Company: "Acme", Domain: "Data", Modality: "Hybrid", PostedDate: "2026-10-14", PostedTime: "2026-10-14T08:00:00Z"},
+				{JobId: "3", Company: "Globex", Domain: "Backend", Modality: "Remote", PostedDate: "2026-10-15", PostedTime: "2026-10-15T08:00:00Z"},
+				{JobId: "4", Company: "Acme", Domain: "Backend", Modality: "Remote", PostedDate: "2026-10-15", PostedTime: "2026-10-15T09:00:00Z"},
+				{JobId: "5", Company: "Initech", PostedDate: "2026-10-16", PostedTime: "2026-10-16T08:00:00Z"},
+			}
+			if _, err := store.PutJobs(ctx, jobs); err != nil {
+				t.Fatalf("PutJobs returned error: %v", err)
+			}
+			ids := func(jobs []models.Job) string {
+				var ids []string
+				for _, job := range jobs {
+					ids = append(ids, job.JobId)
+				}
+				return strings.Join(ids, ",")
+			}
+
+			ranged, err := store.QueryJobsByDateRange(ctx, "2026-10-14", "2026-10-15")
+			if err != nil {
+				t.Fatalf("QueryJobsByDateRange returned error: %v", err)
+			}
+			if got := ids(ranged); got != "4,3,2" {
+				t.Fatalf("expected jobs 4,3,2 newest first, got %s", got)
+			}
+
+			cases := []struct {
+				filter JobFilter
+				want   string
+			}{
+				{JobFilter{Company: "Acme"}, "4,2,1"},
+				{JobFilter{Company: "Acme", PostedFrom: "2026-10-14"}, "4,2"},
+				{JobFilter{Domain: "Backend", Modality: "Remote", PostedTo: "2026-10-14"}, "1"},
+				{JobFilter{Modality: "Remote"}, "4,3,1"},
+				{JobFilter{PostedFrom: "2026-10-16"}, "5"},
+				{JobFilter{}, "5,4,3,2,1"},
+			}
+			for _, tc := range cases {
+				got, err := store.QueryJobs(ctx, tc.filter)
+				if err != nil {
+					t.Fatalf("QueryJobs(%+v) returned error: %v", tc.filter, err)
+				}
+				if ids(got) != tc.want {
+					t.Fatalf("QueryJobs(%+v) = %s, want %s", tc.filter, ids(got), tc.want)
+				}
+			}
+
+			if _, err := store.QueryJobs(ctx, JobFilter{PostedFrom: "2026-10-15", PostedTo: "2026-10-14"}); err == nil {
+				t.Fatal("expected an error for a range that ends before it starts")
+			}
+			if _, err := store.QueryJobsByDateRange(ctx, "2026-10-14", ""); err == nil {
+				t.Fatal("expected an error for a range without an end")
+			}
+		})
+	}
+}
+
+func TestJobStoreUpdateJobEnrichment(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			stored := models.Job{JobId: "1", Title: "Go Developer", Company: "Acme", PostedDate: "2026-10-15", Description: "raw", LowConfidence: true}
+			if err := store.PutJob(ctx, &stored); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+
+			update := models.Job{JobId: "1", PostedDate: "2026-10-15", Company: "ignored", Domain: "Backend", ExtractionVersion: "v2"}
+			if err := store.UpdateJobEnrichment(ctx, &update); err != nil {
+				t.Fatalf("UpdateJobEnrichment returned error: %v", err)
+			}
+			got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+			if err != nil || len(got) != 1 {
+				t.Fatalf("expected the updated job, got %+v (%v)", got, err)
+			}
+			job := got[0]
+			if job.Company != "Acme" || job.Title != "Go Developer" {
+				t.Fatalf("expected listing fields to be kept, got %+v", job)
+			}
+			if job.Domain != "Backend" || job.ExtractionVersion != "v2" || job.Description != "" || job.LowConfidence {
+				t.Fatalf("expected enriched fields to be rewritten, got %+v", job)
+			}
+
+			missing := models.Job{JobId: "missing", PostedDate: "2026-10-15"}
+			if err := store.UpdateJobEnrichment(ctx, &missing); !errors.Is(err, ErrJobNotStored) {
+				t.Fatalf("expected ErrJobNotStored updating a job that is not stored, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJobStoreUpsertJob(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			stored := func() models.Job {
+				t.Helper()
+				got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+				if err != nil || len(got) != 1 {
+					t.Fatalf("expected one stored job, got %+v (%v)", got, err)
+				}
+				return got[0]
+			}
+
+			job := models.Job{JobId: "1", Title: "Go Developer", Salary: "$120,000", PostedDate: "2026-10-15", PostedTime: "2026-10-15T08:00:00Z", Domain: "Backend"}
+			if written, err := store.UpsertJob(ctx, &job); err != nil || !written {
+				t.Fatalf("expected the new job to be written, got %v (%v)", written, err)
+			}
+			if got := stored(); got.Version != 1 {
+				t.Fatalf("expected version 1, got %+v", got)
+			}
+
+			rescraped := job
+			rescraped.Domain = "Full-Stack"
+			if written, err := store.UpsertJob(ctx, &rescraped); err != nil || written {
+				t.Fatalf("expected an unchanged posting to be skipped, got %v (%v)", written, err)
+			}
+			if got := stored(); got.Version != 1 || got.Domain != "Backend" {
+				t.Fatalf("expected the stored job to be kept, got %+v", got)
+			}
+
+			repriced := job
+			repriced.Salary = "$140,000"
+			repriced.Domain = "Full-Stack"
+			if written, err := store.UpsertJob(ctx, &repriced); err != nil || !written {
+				t.Fatalf("expected a changed posting to be written, got %v (%v)", written, err)
+			}
+			if got := stored(); got.Version != 2 || got.Salary != "$140,000" || got.Domain != "Full-Stack" {
+				t.Fatalf("expected version 2 with the new pay, got %+v", got)
+			}
+
+			reposted := repriced
+			reposted.Title = "Senior Go Developer"
+			if err := store.PutJob(ctx, &reposted); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+			if got := stored(); got.Title != "Go Developer" || got.Version != 2 {
+				t.Fatalf("expected PutJob to leave the stored job alone, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestJobStoreUpsertJobIgnoresEnrichmentChanges(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			scraped := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-15", ExpiresDate: "2026-11-30", Description: "Build services."}
+			enrich := func(deadline, domain string) *models.Job {
+				job := scraped
+				populateJobFromResponse(&job, models.OpenAIJobParsingResponse{DeadlineDate: deadline, Domain: domain, IsSoftwareEngineerRelated: true})
+				return &job
+			}
+
+			if written, err := store.UpsertJob(ctx, enrich("Ongoing until requisition is closed", "Backend")); err != nil || !written {
+				t.Fatalf("expected the new job to be written, got %v (%v)", written, err)
+			}
+			if written, err := store.UpsertJob(ctx, enrich("2026-12-01", "Full-Stack")); err != nil || written {
+				t.Fatalf("expected a re-enrichment of the same listing to be skipped, got %v (%v)", written, err)
+			}
+			if written, err := store.UpsertJob(ctx, FallbackJob(&scraped)); err != nil || written {
+				t.Fatalf("expected a fallback of the same listing to be skipped, got %v (%v)", written, err)
+			}
+			got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+			if err != nil || len(got) != 1 || got[0].Version != 1 || got[0].Domain != "Backend" {
+				t.Fatalf("expected version 1 to be kept, got %+v (%v)", got, err)
+			}
+
+			reposted := scraped
+			reposted.ExpiresDate = "2026-12-31"
+			if written, err := store.UpsertJob(ctx, &reposted); err != nil || !written {
+				t.Fatalf("expected a new listing closing date to be written, got %v (%v)", written, err)
+			}
+		})
+	}
+}
+
+func TestJobStoreUpdateJobHistory(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			job := models.Job{JobId: "1", Title: "Go Developer", Salary: "$120,000", PostedDate: "2026-10-15", Domain: "Backend"}
+			if err := store.PutJob(ctx, &job); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+
+			closed := models.Job{
+				JobId:      "1",
+				PostedDate: "2026-10-15",
+				ClosedAt:   "2026-10-16T08:00:00Z",
+				History:    []models.PostingEvent{{Type: models.PostingEventDisappeared, At: "2026-10-16T08:00:00Z"}},
+			}
+			if err := store.UpdateJobHistory(ctx, &closed); err != nil {
+				t.Fatalf("UpdateJobHistory returned error: %v", err)
+			}
+			got, err := store.QueryJobsByPostedDate(ctx, "2026-10-15")
+			if err != nil || len(got) != 1 {
+				t.Fatalf("expected the closed job, got %+v (%v)", got, err)
+			}
+			if got[0].ClosedAt != closed.ClosedAt || len(got[0].History) != 1 || got[0].Title != "Go Developer" || got[0].Domain != "Backend" {
+				t.Fatalf("expected only the history to change, got %+v", got[0])
+			}
+
+			repriced := job
+			repriced.Salary = "$140,000"
+			if _, err := store.UpsertJob(ctx, &repriced); err != nil {
+				t.Fatalf("UpsertJob returned error: %v", err)
+			}
+			if got, _ := store.QueryJobsByPostedDate(ctx, "2026-10-15"); got[0].ClosedAt == "" || len(got[0].History) != 1 {
+				t.Fatalf("expected an upsert to keep the posting history, got %+v", got[0])
+			}
+
+			missing := models.Job{JobId: "missing", PostedDate: "2026-10-15"}
+			if err := store.UpdateJobHistory(ctx, &missing); !errors.Is(err, ErrJobNotStored) {
+				t.Fatalf("expected ErrJobNotStored, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJobStoreSourceHashes(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			stored := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-15"}
+			if err := store.PutJob(ctx, &stored); err != nil {
+				t.Fatalf("PutJob returned error: %v", err)
+			}
+
+			hashes, err := store.SourceHashes(ctx, []models.Job{
+				stored,
+				{JobId: "1", PostedDate: "2026-10-16"},
+				{JobId: "2", PostedDate: "2026-10-15"},
+				stored,
+			})
+			if err != nil {
+				t.Fatalf("SourceHashes returned error: %v", err)
+			}
+			want := []string{sourceHash(&stored), "", "", sourceHash(&stored)}
+			if strings.Join(hashes, ",") != strings.Join(want, ",") {
+				t.Fatalf("expected hashes %v, got %v", want, hashes)
+			}
+		})
+	}
+}
+
+func TestSQLiteStoreMigratesJobIdKey(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "jobs.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	_, err = db.Exec(`CREATE TABLE jobs (job_id TEXT PRIMARY KEY, posted_date TEXT NOT NULL, posted_time TEXT NOT NULL, item TEXT NOT NULL);
+CREATE INDEX jobs_posted_date ON jobs (posted_date, posted_time);
+INSERT INTO jobs VALUES ('1', '2026-10-15', '', '{"JobId":"1","Title":"Go Developer","PostedDate":"2026-10-15","Version":1}');`)
+	db.Close()
+	if err != nil {
+		t.Fatalf("create job_id keyed table: %v", err)
+	}
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore returned error: %v", err)
+	}
+	reposted := models.Job{JobId: "1", Title: "Go Developer", PostedDate: "2026-10-16"}
+	if err := store.PutJob(ctx, &reposted); err != nil {
+		t.Fatalf("PutJob returned error: %v", err)
+	}
+	jobs, err := store.QueryJobsByDateRange(ctx, "2026-10-15", "2026-10-16")
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("expected the migrated job and its repost, got %+v (%v)", jobs, err)
+	}
+}
+
+func TestNewJobStoreRejectsUnknownBackend(t *testing.T) {
+	if _, err := NewJobStore(config.Config{JobStore: "postgres"}, aws.Config{}); err == nil {
+		t.Fatal("expected an error for an unknown job store")
+	}
+	store, err := NewJobStore(config.Config{JobStore: config.JobStoreMemory}, aws.Config{})
+	if err != nil || store == nil {
+		t.Fatalf("expected a memory store, got %v (%v)", store, err)
+	}
+}
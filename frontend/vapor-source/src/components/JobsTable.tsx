@@ -147,6 +147,27 @@ function formatFilterValue(value: unknown) {
   return String(value ?? '');
 }
 
+const SENIORITY_ORDER = ['Intern', 'Entry', 'Mid', 'Senior', 'Staff', 'Principal', 'Manager', 'Director'];
+
+function seniorityRank(level: string) {
+  const rank = SENIORITY_ORDER.indexOf(level);
+  return rank === -1 ? SENIORITY_ORDER.length : rank;
+}
+
+const POSTING_STATUSES = ['Open', 'Closed'];
+
+function postingStatus(job: Job) {
+  return job.closedAt ? 'Closed' : 'Open';
+}
+
+// days from posting to closing, or to today for postings still open
+function daysOpen(job: Job) {
+  const posted = Date.parse(job.postedDate);
+  if (Number.isNaN(posted)) return null;
+  const end = job.closedAt ? Date.parse(job.closedAt) : Date.now();
+  return Math.max(0, Math.floor((end - posted) / 86_400_000));
+}
+
 const textFilterControls = [
   { id: 'location', label: 'Location', placeholder: 'Filter location' },
   { id: 'company', label: 'Company', placeholder: 'Filter company' },
@@ -155,6 +176,8 @@ const textFilterControls = [
 
 const modalFilterButtons = [
   { id: 'modality', label: 'Modality' },
+  { id: 'seniority', label: 'Level' },
+  { id: 'status', label: 'Status' },
   { id: 'languages', label: 'Languages' },
   { id: 'domain', label: 'Domain' },
   { id: 'technologies', label: 'Technologies' },
@@ -257,6 +280,9 @@ function JobDetailModal({ job, onClose }: JobDetailModalProps) {
     { label: 'Location', value: job.location ?? '—' },
     { label: 'Modality', value: job.modality ?? '—' },
     { label: 'Domain', value: job.domain ?? '—' },
+    { label: 'Level', value: job.seniority ?? '—' },
+    { label: 'Status', value: job.closedAt ? `Closed ${job.closedAt.slice(0, 10)}` : 'Open' },
+    { label: job.closedAt ? 'Days to close' : 'Days open', value: daysOpen(job) ?? '—' },
     { label: 'Min YOE', value: job.minYearsExperience ?? '—' },
     { label: 'Degree', value: job.minDegree ?? '—' },
     { label: 'Languages', value: formatList(job.languages) },
@@ -378,11 +404,13 @@ export function JobsTable({ jobs, pageSize = 25 }: JobsTableProps) {
     const technologies = new Set<string>();
     const languages = new Set<string>();
     const degrees = new Set<string>();
+    const seniority = new Set<string>();
 
     for (const job of jobs) {
       if (job.modality) modality.add(job.modality);
       if (job.domain) domain.add(job.domain);
       if (job.minDegree) degrees.add(job.minDegree);
+      if (job.seniority) seniority.add(job.seniority);
       (job.technologies ?? []).forEach((tech) => tech && technologies.add(tech));
       (job.languages ?? []).forEach((lang) => lang && languages.add(lang));
     }
@@ -395,6 +423,7 @@ export function JobsTable({ jobs, pageSize = 25 }: JobsTableProps) {
       technologies: toSortedArray(technologies),
       languages: toSortedArray(languages),
       degrees: toSortedArray(degrees),
+      seniority: Array.from(seniority).sort((a, b) => seniorityRank(a) - seniorityRank(b)),
     };
   }, [jobs]);
 
@@ -452,6 +481,19 @@ export function JobsTable({ jobs, pageSize = 25 }: JobsTableProps) {
           </span>
         ),
       }),
+      columnHelper.accessor('seniority', {
+        header: 'Level',
+        filterFn: multiSelectFilter,
+        meta: { filterType: 'multi', options: filterOptions.seniority, title: 'Level' },
+        cell: (info) => info.getValue() || '—',
+      }),
+      columnHelper.accessor(postingStatus, {
+        id: 'status',
+        header: 'Status',
+        filterFn: multiSelectFilter,
+        meta: { filterType: 'multi', options: POSTING_STATUSES, title: 'Status' },
+        cell: (info) => info.getValue(),
+      }),
       columnHelper.accessor('minYearsExperience', {
         header: 'YOE',
         filterFn: numericMaxFilter,
@@ -1,16 +1,48 @@
+export interface PostingEvent {
+  type: 'changed' | 'closing_date_changed' | 'disappeared' | 'reappeared';
+  at: string;
+  field?: string;
+  old?: string;
+  new?: string;
+}
+
 export interface Job {
   jobId: string;
+  source?: string;
   title: string;
   company: string;
   location: string;
+  city?: string;
+  state?: string;
+  postalCode?: string;
+  country?: string;
+  latitude?: number;
+  longitude?: number;
+  metroArea?: string;
   modality?: string;
   postedDate: string;
   expiresDate?: string;
   postedTime: string;
   salary: string;
+  salaryMin?: number;
+  salaryMax?: number;
+  salaryCurrency?: string;
+  salaryPeriod?: 'hour' | 'day' | 'week' | 'month' | 'year';
+  salaryAnnualMin?: number;
+  salaryAnnualMax?: number;
+  salaryDoe?: boolean;
+  salarySource?: 'listing' | 'description';
   url: string;
   minYearsExperience?: number;
   minDegree?: string;
+  visaSponsorship?: 'Available' | 'Not Available' | 'Unspecified';
+  securityClearance?: 'Public Trust' | 'Secret' | 'Top Secret' | 'TS/SCI' | 'Unspecified';
+  workAuthorization?: 'U.S. Citizen' | 'U.S. Person' | 'Authorized to Work' | 'Unspecified';
+  seniority?: 'Intern' | 'Entry' | 'Mid' | 'Senior' | 'Staff' | 'Principal' | 'Manager' | 'Director';
+  qualityFlags?: string[];
+  version?: number;
+  closedAt?: string;
+  history?: PostingEvent[];
   domain?: string;
   description?: string;
   parsedDescription?: string;
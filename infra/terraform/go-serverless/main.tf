@@ -195,12 +195,36 @@ resource "aws_dynamodb_table" "jobs" {
     type = "S"
   }
 
+  attribute {
+    name = "Company"
+    type = "S"
+  }
+
+  attribute {
+    name = "Domain"
+    type = "S"
+  }
+
   global_secondary_index {
     name            = "PostedDate-Index"
     hash_key        = "PostedDate"
     range_key       = "PostedTime"
     projection_type = "ALL"
   }
+
+  global_secondary_index {
+    name            = "Company-Index"
+    hash_key        = "Company"
+    range_key       = "PostedDate"
+    projection_type = "ALL"
+  }
+
+  global_secondary_index {
+    name            = "Domain-Index"
+    hash_key        = "Domain"
+    range_key       = "PostedDate"
+    projection_type = "ALL"
+  }
 }
 
 data "aws_iam_policy_document" "lambda_assume" {
@@ -236,6 +260,10 @@ resource "aws_iam_role_policy" "job_scraper" {
         Action = [
           "dynamodb:PutItem",
           "dynamodb:BatchWriteItem",
+          # batch writes look up stored jobs before inserting
+          "dynamodb:BatchGetItem",
+          # versioned upserts, enrichment replacement and posting history
+          "dynamodb:UpdateItem",
           "dynamodb:Scan",
           "dynamodb:DescribeTable"
         ]
@@ -247,7 +275,8 @@ resource "aws_iam_role_policy" "job_scraper" {
           "s3:GetObject",
           "s3:PutObject"
         ]
-        Resource = "${aws_s3_bucket.job_id_cache.arn}/${var.job_ids_s3_key}"
+        # job-ids.txt plus the scrape-watermarks.json stored beside it
+        Resource = "${aws_s3_bucket.job_id_cache.arn}/*"
       },
       {
         Effect = "Allow"
@@ -374,7 +403,9 @@ resource "aws_iam_role_policy" "job_snapshot" {
         ]
         Resource = [
           aws_dynamodb_table.jobs.arn,
-          "${aws_dynamodb_table.jobs.arn}/index/PostedDate-Index"
+          "${aws_dynamodb_table.jobs.arn}/index/PostedDate-Index",
+          "${aws_dynamodb_table.jobs.arn}/index/Company-Index",
+          "${aws_dynamodb_table.jobs.arn}/index/Domain-Index"
         ]
       },
       {
@@ -55,19 +55,22 @@ variable "scraper_environment_variables" {
   description = "Environment variables passed into the scraper Lambda."
   type        = map(string)
   default = {
-    QUERY               = "software engineer"
-    DEBUG_OUTPUT        = "true"
-    API_DRY_RUN         = "false"
-    USE_JOB_ID_FILE     = "false"
-    USE_S3_JOB_ID_FILE  = "false"
-    OPENAI_API_KEY      = ""
-    DYNAMODB_TABLE_NAME = "Jobs"
-    DYNAMODB_ENDPOINT   = ""
-    JOB_IDS_BUCKET      = ""
-    JOB_IDS_S3_KEY      = ""
-    SNAPSHOT_BUCKET     = ""
-    SNAPSHOT_S3_KEY     = ""
-    MAX_PAGES           = "5"
+    QUERY                   = "software engineer"
+    JOB_SOURCES             = "worksourcewa"
+    GREENHOUSE_BOARD_TOKENS = ""
+    LEVER_BOARD_TOKENS      = ""
+    DEBUG_OUTPUT            = "true"
+    API_DRY_RUN             = "false"
+    USE_JOB_ID_FILE         = "false"
+    USE_S3_JOB_ID_FILE      = "false"
+    OPENAI_API_KEY          = ""
+    DYNAMODB_TABLE_NAME     = "Jobs"
+    DYNAMODB_ENDPOINT       = ""
+    JOB_IDS_BUCKET          = ""
+    JOB_IDS_S3_KEY          = ""
+    SNAPSHOT_BUCKET         = ""
+    SNAPSHOT_S3_KEY         = ""
+    MAX_PAGES               = "5"
   }
 }
 